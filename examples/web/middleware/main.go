@@ -3,6 +3,7 @@ package main
 import (
 	_ "github.com/hidevopsio/hiboot/examples/web/middleware/controller"
 	_ "github.com/hidevopsio/hiboot/examples/web/middleware/logging"
+	_ "github.com/hidevopsio/hiboot/examples/web/middleware/security"
 	"github.com/hidevopsio/hiboot/pkg/app"
 	"github.com/hidevopsio/hiboot/pkg/app/web"
 	"github.com/hidevopsio/hiboot/pkg/starter/actuator"
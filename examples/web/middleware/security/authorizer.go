@@ -0,0 +1,42 @@
+package security
+
+import (
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/utils/str"
+)
+
+// subject is the demo user who is permitted to read and list users
+type subject struct {
+	permissions []string
+}
+
+func (s *subject) IsAuthenticated() bool {
+	return true
+}
+
+func (s *subject) HasRole(role string) bool {
+	return false
+}
+
+func (s *subject) IsPermitted(permission string) bool {
+	return str.InSlice(permission, s.permissions)
+}
+
+// authorizer resolves the subject that is checked by at.RequiresPermissions,
+// a real application resolves it from the credentials of the request, e.g. the jwt starter
+type authorizer struct{}
+
+func newAuthorizer() web.Authorizer {
+	return &authorizer{}
+}
+
+func init() {
+	app.Register(newAuthorizer)
+}
+
+// Subject returns the demo user for every request
+func (a *authorizer) Subject(ctx context.Context) web.Subject {
+	return &subject{permissions: []string{"user:read", "user:list"}}
+}
@@ -133,9 +133,9 @@ func TestController(t *testing.T) {
 			}).Expect().Status(http.StatusOK).Body().Contains("abc")
 	})
 
-	t.Run("should report 500 error if create employee without request body", func(t *testing.T) {
+	t.Run("should report 400 error if create employee without request body", func(t *testing.T) {
 		testApp.Post("/employee").
-			Expect().Status(http.StatusInternalServerError)
+			Expect().Status(http.StatusBadRequest)
 	})
 
 	t.Run("should get employees", func(t *testing.T) {
@@ -0,0 +1,236 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aop provides the method interceptors of hiboot, the interceptor is the component that embeds at.Interceptor
+// and the custom annotations that embed at.InterceptorBinding, it sees the method name, arguments, results and error
+// of the methods that are annotated with these annotations.
+//
+// The methods of controllers and scheduled tasks are intercepted by the framework, the other components are
+// intercepted through the proxies that are registered for the interfaces they are injected as.
+package aop
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/inject/annotation"
+	"github.com/hidevopsio/hiboot/pkg/utils/reflector"
+	"github.com/hidevopsio/hiboot/pkg/utils/str"
+)
+
+// lowestPrecedence is the order of the interceptor without at.Order
+const lowestPrecedence = math.MaxInt32
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Interceptor intercepts the invocation of the method, it calls invocation.Proceed() to invoke the next interceptor
+// or the method itself, it may also skip, retry or change the arguments, the results and the error of the invocation
+type Interceptor interface {
+	Intercept(invocation *Invocation)
+}
+
+// Invocation is the method invocation that is intercepted
+type Invocation struct {
+	// Target is the component that the method belongs to
+	Target interface{}
+	// Method is the intercepted method
+	Method reflect.Method
+	// Args are the arguments of the method, without the receiver
+	Args []interface{}
+	// Results are the results of the method
+	Results []interface{}
+	// Err is the error result of the method, it overrides the last result if the method returns an error
+	Err error
+
+	receiver     reflect.Value
+	interceptors []Interceptor
+	index        int
+}
+
+type candidate struct {
+	interceptor Interceptor
+	order       int
+}
+
+func newInvocation(interceptors []Interceptor, method reflect.Method, receiver reflect.Value, args []interface{}) *Invocation {
+	return &Invocation{
+		Target:       receiver.Interface(),
+		Method:       method,
+		Args:         args,
+		receiver:     receiver,
+		interceptors: interceptors,
+	}
+}
+
+// Name returns the full name of the method, e.g. main.fooController.Get
+func (inv *Invocation) Name() string {
+	return reflector.IndirectType(inv.receiver.Type()).String() + "." + inv.Method.Name
+}
+
+// Annotation returns the annotation of the method, or of the target if the method is not annotated with it
+func (inv *Invocation) Annotation(att interface{}) (ann *annotation.Annotation) {
+	ann = annotation.FindMethodAnnotation(inv.Method, att)
+	if ann == nil {
+		ann = targetAnnotation(inv.Target, inv.Method, att)
+	}
+	if ann != nil {
+		_ = annotation.Inject(ann)
+	}
+	return
+}
+
+// Proceed invokes the next interceptor, or the method once all interceptors are invoked, it returns the error result
+func (inv *Invocation) Proceed() error {
+	if inv.index < len(inv.interceptors) {
+		interceptor := inv.interceptors[inv.index]
+		inv.index++
+		defer func() {
+			inv.index--
+		}()
+		interceptor.Intercept(inv)
+	} else {
+		inv.call()
+	}
+	return inv.Err
+}
+
+func (inv *Invocation) call() {
+	typ := inv.Method.Type
+	inputs := make([]reflect.Value, typ.NumIn())
+	inputs[0] = inv.receiver
+	for i := 1; i < len(inputs); i++ {
+		var arg interface{}
+		if i <= len(inv.Args) {
+			arg = inv.Args[i-1]
+		}
+		inputs[i] = valueOf(arg, typ.In(i))
+	}
+
+	var outputs []reflect.Value
+	if typ.IsVariadic() {
+		outputs = inv.Method.Func.CallSlice(inputs)
+	} else {
+		outputs = inv.Method.Func.Call(inputs)
+	}
+	inv.Results = interfaces(outputs)
+	inv.Err = nil
+	if inv.returnsError() {
+		inv.Err, _ = inv.Results[len(inv.Results)-1].(error)
+	}
+}
+
+func (inv *Invocation) returnsError() bool {
+	numOut := inv.Method.Type.NumOut()
+	return numOut > 0 && inv.Method.Type.Out(numOut-1) == errorType
+}
+
+// values returns the results as the declared result types, the missing ones are zero values
+func (inv *Invocation) values() (values []reflect.Value) {
+	typ := inv.Method.Type
+	values = make([]reflect.Value, typ.NumOut())
+	for i := range values {
+		var result interface{}
+		if i < len(inv.Results) {
+			result = inv.Results[i]
+		}
+		if i == len(values)-1 && inv.returnsError() {
+			result = inv.Err
+		}
+		values[i] = valueOf(result, typ.Out(i))
+	}
+	return
+}
+
+func valueOf(v interface{}, typ reflect.Type) reflect.Value {
+	if v == nil {
+		return reflect.Zero(typ)
+	}
+	return reflect.ValueOf(v)
+}
+
+func interfaces(values []reflect.Value) (retVal []interface{}) {
+	retVal = make([]interface{}, len(values))
+	for i, v := range values {
+		retVal[i] = v.Interface()
+	}
+	return
+}
+
+// Match returns the interceptors that are bound to the annotations of the method, or of the target for all of its
+// methods, they are sorted by at.Order
+func Match(interceptors []*factory.MetaData, target interface{}, method reflect.Method) (matched []Interceptor) {
+	var candidates []*candidate
+	for _, md := range interceptors {
+		interceptor, ok := md.Instance.(Interceptor)
+		if ok && bound(md.Instance, target, method) {
+			c := &candidate{interceptor: interceptor, order: lowestPrecedence}
+			if ann := annotation.GetAnnotation(md.Instance, at.Order{}); ann != nil {
+				order, err := strconv.Atoi(ann.Field.StructField.Tag.Get("value"))
+				if err == nil {
+					c.order = order
+				}
+			}
+			candidates = append(candidates, c)
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].order < candidates[b].order
+	})
+	for _, c := range candidates {
+		matched = append(matched, c.interceptor)
+	}
+	return
+}
+
+// bound returns true if the method or the target is annotated with any of the binding annotations of the interceptor
+func bound(interceptor, target interface{}, method reflect.Method) bool {
+	ans := annotation.GetAnnotations(interceptor)
+	if ans == nil {
+		return false
+	}
+	for _, item := range ans.Items {
+		binding := reflect.New(item.Field.StructField.Type).Elem().Interface()
+		if !reflector.HasEmbeddedFieldType(binding, at.InterceptorBinding{}) {
+			continue
+		}
+		if annotation.FindMethodAnnotation(method, binding) != nil || targetAnnotation(target, method, binding) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// targetAnnotation returns the annotation of the target if it applies to the method,
+// it applies to the methods in its tag methods, or to all methods if the tag is not specified
+func targetAnnotation(target interface{}, method reflect.Method, att interface{}) (ann *annotation.Annotation) {
+	ann = annotation.GetAnnotation(target, att)
+	if ann != nil {
+		if methods := ann.Field.StructField.Tag.Get("methods"); methods != "" && !str.InSlice(method.Name, strings.Split(strings.ReplaceAll(methods, " ", ""), ",")) {
+			ann = nil
+		}
+	}
+	return
+}
+
+// Invoke calls the method through the interceptors, the inputs are the receiver and the arguments of the method
+func Invoke(interceptors []Interceptor, method reflect.Method, inputs []reflect.Value) (results []reflect.Value) {
+	inv := newInvocation(interceptors, method, inputs[0], interfaces(inputs[1:]))
+	_ = inv.Proceed()
+	return inv.values()
+}
@@ -0,0 +1,321 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aop_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/aop"
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/factory/instantiate"
+	"github.com/hidevopsio/hiboot/pkg/utils/cmap"
+	"github.com/stretchr/testify/assert"
+)
+
+// Audit records the invocations of the annotated methods
+type Audit struct {
+	at.Annotation
+
+	at.InterceptorBinding
+
+	AtValue string `at:"value" json:"-"`
+}
+
+// Retryable retries the annotated methods once they fail
+type Retryable struct {
+	at.Annotation
+
+	at.InterceptorBinding
+}
+
+type auditInterceptor struct {
+	at.Interceptor
+	at.Order `value:"1"`
+	Audit
+
+	mu      sync.Mutex
+	records []string
+}
+
+func (i *auditInterceptor) Intercept(inv *aop.Invocation) {
+	err := inv.Proceed()
+	var tag string
+	if ann := inv.Annotation(Audit{}); ann != nil {
+		tag = ann.Field.Value.Interface().(Audit).AtValue
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.records = append(i.records, strings.Join([]string{tag, inv.Method.Name, toString(inv.Args), toString(inv.Results), toString(err)}, "|"))
+}
+
+func (i *auditInterceptor) last() string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if len(i.records) == 0 {
+		return ""
+	}
+	return i.records[len(i.records)-1]
+}
+
+type retryPolicy struct {
+	attempts int
+}
+
+type retryInterceptor struct {
+	at.Interceptor
+	Retryable
+
+	policy *retryPolicy
+}
+
+func newRetryInterceptor(policy *retryPolicy) *retryInterceptor {
+	return &retryInterceptor{policy: policy}
+}
+
+func (i *retryInterceptor) Intercept(inv *aop.Invocation) {
+	err := inv.Proceed()
+	for n := 1; err != nil && n < i.policy.attempts; n++ {
+		err = inv.Proceed()
+	}
+}
+
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "<nil>"
+	case error:
+		return v.Error()
+	case string:
+		return v
+	case []interface{}:
+		var s []string
+		for _, item := range v {
+			s = append(s, toString(item))
+		}
+		return strings.Join(s, ",")
+	}
+	return "?"
+}
+
+type Greeter interface {
+	Greet(name string) (string, error)
+}
+
+type greeter struct {
+	Audit `value:"greeter"`
+	Retryable
+
+	calls int
+	// failed is true once the flaky greeting failed
+	failed bool
+}
+
+func newGreeter() Greeter {
+	return &greeter{}
+}
+
+func (g *greeter) Greet(name string) (string, error) {
+	g.calls++
+	if name == "" {
+		return "", errors.New("empty name")
+	}
+	if name == "flaky" && !g.failed {
+		g.failed = true
+		return "", errors.New("flaky")
+	}
+	return "hello " + name, nil
+}
+
+type greeterProxy struct {
+	*aop.Proxy
+}
+
+func (p *greeterProxy) Greet(name string) (string, error) {
+	results := p.Invoke("Greet", name)
+	err, _ := results[1].(error)
+	return results[0].(string), err
+}
+
+type greeterClient struct {
+	greeter Greeter
+}
+
+func newGreeterClient(greeter Greeter) *greeterClient {
+	return &greeterClient{greeter: greeter}
+}
+
+func init() {
+	aop.RegisterProxy(new(Greeter), func(p *aop.Proxy) Greeter {
+		return &greeterProxy{p}
+	})
+}
+
+func TestProxy(t *testing.T) {
+	audit := new(auditInterceptor)
+	f := instantiate.NewInstantiateFactory(cmap.New(), []*factory.MetaData{
+		factory.NewMetaData(newGreeterClient),
+		factory.NewMetaData(newGreeter),
+		factory.NewMetaData(audit),
+		factory.NewMetaData(newRetryInterceptor),
+		factory.NewMetaData(&retryPolicy{attempts: 2}),
+	}, cmap.New())
+	err := f.BuildComponents()
+	assert.Equal(t, nil, err)
+
+	client := f.GetInstance(greeterClient{}).(*greeterClient)
+	proxy, ok := client.greeter.(*greeterProxy)
+	assert.Equal(t, true, ok)
+
+	t.Run("should intercept the method of the interface-typed component", func(t *testing.T) {
+		greeting, err := client.greeter.Greet("hiboot")
+		assert.Equal(t, nil, err)
+		assert.Equal(t, "hello hiboot", greeting)
+		assert.Equal(t, "greeter|Greet|hiboot|hello hiboot,<nil>|<nil>", audit.last())
+	})
+
+	t.Run("should see the error of the method", func(t *testing.T) {
+		_, err := client.greeter.Greet("")
+		assert.Equal(t, "empty name", err.Error())
+		assert.Equal(t, "greeter|Greet||,empty name|empty name", audit.last())
+	})
+
+	t.Run("should invoke the interceptors by at.Order", func(t *testing.T) {
+		calls := proxy.Target().(*greeter).calls
+		greeting, err := client.greeter.Greet("flaky")
+		assert.Equal(t, nil, err)
+		assert.Equal(t, "hello flaky", greeting)
+		assert.Equal(t, calls+2, proxy.Target().(*greeter).calls)
+		// the audit interceptor sees the result of the retry
+		assert.Equal(t, "greeter|Greet|flaky|hello flaky,<nil>|<nil>", audit.last())
+	})
+
+	t.Run("should panic if the method is not the one of the interface", func(t *testing.T) {
+		assert.Panics(t, func() {
+			proxy.Invoke("Bye")
+		})
+	})
+}
+
+func TestNoProxy(t *testing.T) {
+	t.Run("should not proxy the component without the interceptors", func(t *testing.T) {
+		f := instantiate.NewInstantiateFactory(cmap.New(), []*factory.MetaData{
+			factory.NewMetaData(newGreeterClient),
+			factory.NewMetaData(newGreeter),
+		}, cmap.New())
+		err := f.BuildComponents()
+		assert.Equal(t, nil, err)
+
+		client := f.GetInstance(greeterClient{}).(*greeterClient)
+		_, ok := client.greeter.(*greeter)
+		assert.Equal(t, true, ok)
+	})
+
+	t.Run("should not register the invalid proxy", func(t *testing.T) {
+		aop.RegisterProxy(new(Greeter), func() Greeter { return nil })
+		aop.RegisterProxy(greeter{}, func(p *aop.Proxy) Greeter { return nil })
+	})
+}
+
+type auditController struct {
+	at.RestController
+	at.RequestMapping `value:"/audit"`
+}
+
+func newAuditController() *auditController {
+	return &auditController{}
+}
+
+func (c *auditController) Get(_ struct {
+	at.GetMapping `value:"/{name}"`
+	Audit         `value:"web"`
+}, name string) (string, error) {
+	if name == "nobody" {
+		return "", errors.New("nobody")
+	}
+	return "hi " + name, nil
+}
+
+func (c *auditController) Delete(_ struct {
+	at.DeleteMapping `value:"/{name}"`
+}, name string) string {
+	return "bye " + name
+}
+
+type reportService struct {
+	at.EnableScheduling
+}
+
+func newReportService() *reportService {
+	return &reportService{}
+}
+
+func (s *reportService) Report(_ struct {
+	at.Scheduled `limit:"1"`
+	Audit        `value:"job"`
+}) {
+}
+
+func (i *auditInterceptor) contains(record string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, r := range i.records {
+		if r == record {
+			return true
+		}
+	}
+	return false
+}
+
+func TestControllerInterceptor(t *testing.T) {
+	audit := new(auditInterceptor)
+	app.Register(audit, newReportService)
+	testApp := web.NewTestApp(newAuditController).Run(t)
+
+	t.Run("should intercept the annotated controller method", func(t *testing.T) {
+		testApp.Get("/audit/{name}").
+			WithPath("name", "hiboot").
+			Expect().Status(http.StatusOK).Body().Contains("hi hiboot")
+		assert.Equal(t, "web|Get|?,hiboot|hi hiboot,<nil>|<nil>", audit.last())
+	})
+
+	t.Run("should see the error of the controller method", func(t *testing.T) {
+		testApp.Get("/audit/{name}").
+			WithPath("name", "nobody").
+			Expect().Status(http.StatusInternalServerError)
+		assert.Equal(t, "web|Get|?,nobody|,nobody|nobody", audit.last())
+	})
+
+	t.Run("should intercept the scheduled task", func(t *testing.T) {
+		assert.Eventually(t, func() bool {
+			return audit.contains("job|Report|?||<nil>")
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should not intercept the method that is not annotated", func(t *testing.T) {
+		last := audit.last()
+		testApp.Delete("/audit/{name}").
+			WithPath("name", "hiboot").
+			Expect().Status(http.StatusOK)
+		assert.Equal(t, last, audit.last())
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aop
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+var (
+	// ErrInvalidProxy the proxy constructor should be a func(p *aop.Proxy) that returns the implementation of the interface
+	ErrInvalidProxy = errors.New("[aop] invalid proxy, it should be a func(p *aop.Proxy) that returns the interface")
+
+	// ErrMethodNotFound the method is not the one of the proxied interface
+	ErrMethodNotFound = errors.New("[aop] method is not found")
+
+	proxyType = reflect.TypeOf((*Proxy)(nil))
+
+	proxies   = make(map[reflect.Type]reflect.Value)
+	proxiesMu sync.RWMutex
+)
+
+// Proxy calls the methods of the component through the interceptors, it is wrapped by the implementation of the
+// interface that is registered by RegisterProxy, e.g.
+//
+//	type greeterProxy struct {
+//	  *aop.Proxy
+//	}
+//
+//	func (p *greeterProxy) Greet(name string) (string, error) {
+//	  results := p.Invoke("Greet", name)
+//	  err, _ := results[1].(error)
+//	  return results[0].(string), err
+//	}
+//
+//	func init() {
+//	  aop.RegisterProxy(new(Greeter), func(p *aop.Proxy) Greeter { return &greeterProxy{p} })
+//	}
+type Proxy struct {
+	target  reflect.Value
+	methods map[string]*proxyMethod
+}
+
+type proxyMethod struct {
+	method       reflect.Method
+	interceptors []Interceptor
+}
+
+// RegisterProxy registers the proxy constructor of the interface, the components that are injected as the interface
+// are wrapped by the proxy if any interceptor is bound to their methods
+func RegisterProxy(iface interface{}, constructor interface{}) {
+	typ := reflect.TypeOf(iface)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	fn := reflect.ValueOf(constructor)
+	if typ == nil || typ.Kind() != reflect.Interface || fn.Kind() != reflect.Func {
+		log.Errorf("%v: %v", ErrInvalidProxy, reflect.TypeOf(constructor))
+		return
+	}
+	ft := fn.Type()
+	if ft.NumIn() != 1 || ft.In(0) != proxyType || ft.NumOut() != 1 || !ft.Out(0).Implements(typ) {
+		log.Errorf("%v: %v", ErrInvalidProxy, ft)
+		return
+	}
+	proxiesMu.Lock()
+	defer proxiesMu.Unlock()
+	proxies[typ] = fn
+}
+
+// HasProxy returns true if the proxy of the interface is registered
+func HasProxy(iface reflect.Type) (ok bool) {
+	proxiesMu.RLock()
+	defer proxiesMu.RUnlock()
+	_, ok = proxies[iface]
+	return
+}
+
+// NewProxy returns the proxy of the target as the interface if its proxy is registered
+// and any of the interceptors is bound to the methods of the target
+func NewProxy(iface reflect.Type, target interface{}, interceptors []*factory.MetaData) (proxy interface{}, ok bool) {
+	proxiesMu.RLock()
+	constructor, registered := proxies[iface]
+	proxiesMu.RUnlock()
+	if !registered || target == nil || len(interceptors) == 0 {
+		return
+	}
+
+	p := &Proxy{
+		target:  reflect.ValueOf(target),
+		methods: make(map[string]*proxyMethod),
+	}
+	typ := p.target.Type()
+	for i := 0; i < iface.NumMethod(); i++ {
+		method, found := typ.MethodByName(iface.Method(i).Name)
+		if found {
+			pm := &proxyMethod{method: method, interceptors: Match(interceptors, target, method)}
+			p.methods[method.Name] = pm
+			ok = ok || len(pm.interceptors) > 0
+		}
+	}
+	if ok {
+		proxy = constructor.Call([]reflect.Value{reflect.ValueOf(p)})[0].Interface()
+	}
+	return
+}
+
+// Target returns the proxied component
+func (p *Proxy) Target() interface{} {
+	return p.target.Interface()
+}
+
+// Invoke calls the method of the target by name through the interceptors, the args and the results are the ones
+// of the interface method, the results are zero values if they are not set by the interceptors
+func (p *Proxy) Invoke(name string, args ...interface{}) (results []interface{}) {
+	pm, ok := p.methods[name]
+	if !ok {
+		panic(fmt.Errorf("%v: %v", ErrMethodNotFound, name))
+	}
+	inv := newInvocation(pm.interceptors, pm.method, p.target, args)
+	_ = inv.Proceed()
+	return interfaces(inv.values())
+}
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hidevopsio/hiboot/pkg/app/web/context"
 	"github.com/hidevopsio/hiboot/pkg/at"
@@ -53,6 +54,7 @@ type Application interface {
 	GetProperty(name string) (value interface{}, ok bool)
 	SetAddCommandLineProperties(enabled bool) Application
 	Run()
+	Shutdown() error
 }
 
 // ApplicationContext is the alias interface of Application
@@ -78,7 +80,11 @@ type BaseApplication struct {
 	// SetAddCommandLineProperties
 	addCommandLineProperties bool
 
-	schedulers []*scheduler.Scheduler
+	schedulers   []*scheduler.Scheduler
+	shutdownOnce sync.Once
+	stopWatching chan struct{}
+
+	eventPublisher *eventPublisher
 }
 
 var (
@@ -154,6 +160,9 @@ func (a *BaseApplication) Build() {
 
 	a.WorkDir = io.GetWorkDir()
 
+	a.eventPublisher = newEventPublisher()
+	a.Publish(&ApplicationStartingEvent{})
+
 	instantiateFactory := instantiate.NewInstantiateFactory(a.instances, componentContainer, a.defaultProperties)
 	configurableFactory := autoconfigure.NewConfigurableFactory(instantiateFactory, a.configurations)
 	a.configurableFactory = configurableFactory
@@ -161,12 +170,39 @@ func (a *BaseApplication) Build() {
 	a.postProcessor = newPostProcessor(instantiateFactory)
 	a.systemConfig, _ = configurableFactory.BuildProperties()
 
+	applyLogging(a.systemConfig.Logging)
+
+	// the event publisher is injectable as app.ApplicationEventPublisher
+	if a.systemConfig != nil && a.systemConfig.App != nil {
+		a.eventPublisher.configure(a.systemConfig.App.Event)
+	}
+	instantiateFactory.AppendComponent(ApplicationEventPublisherName, a.eventPublisher)
+	_ = instantiateFactory.SetInstance(ApplicationEventPublisherName, a.eventPublisher)
+	a.Publish(&PropertiesBoundEvent{SystemConfig: a.systemConfig})
+}
+
+// Publish publishes the application event to the listeners
+func (a *BaseApplication) Publish(event interface{}) {
+	if a.eventPublisher != nil {
+		a.eventPublisher.Publish(event)
+	}
+}
+
+// applyLogging applies the logging properties, it is called again once the configuration properties are refreshed
+func applyLogging(logging *system.Logging) {
 	// set logging level
-	log.SetLevel(a.systemConfig.Logging.Level)
+	log.SetLevel(logging.Level)
 	// set logging time format
-	log.SetTimeFormat(a.systemConfig.Logging.TimeFormat)
+	log.SetTimeFormat(logging.TimeFormat)
 	// set logging fileline prefix toggle
-	log.SetShowFileLine(a.systemConfig.Logging.FileLine)
+	log.SetShowFileLine(logging.FileLine)
+	// set logging format and the levels of named loggers
+	log.SetFormat(logging.Format)
+	for name, level := range logging.Levels {
+		log.SetLoggerLevel(name, level)
+	}
+	// set logging file appender
+	setLogFile(&logging.File)
 }
 
 // SystemConfig returns application config
@@ -181,10 +217,21 @@ func (a *BaseApplication) BuildConfigurations() (err error) {
 	// build components
 	err = a.configurableFactory.BuildComponents()
 
+	// subscribe the at.EventListener methods of the components
+	if a.eventPublisher != nil {
+		a.eventPublisher.subscribe(a.configurableFactory)
+	}
+
 	// Start Scheduler after build
 	schedulerServices := a.configurableFactory.GetInstances(at.EnableScheduling{})
 	a.schedulers = a.configurableFactory.StartSchedulers(schedulerServices)
 
+	// watch the config files if hot reload is enabled
+	if a.systemConfig != nil && a.systemConfig.App != nil && a.systemConfig.App.Refresh.Watch {
+		a.stopWatching = make(chan struct{})
+		go a.watchConfig(time.Duration(a.systemConfig.App.Refresh.Interval)*time.Second, a.stopWatching)
+	}
+
 	return
 }
 
@@ -198,6 +245,7 @@ func (a *BaseApplication) AfterInitialization(configs ...cmap.ConcurrentMap) {
 	// pass user's instances
 	a.postProcessor.Init()
 	a.postProcessor.AfterInitialization()
+	a.Publish(&ContextRefreshedEvent{})
 	log.Infof("command line properties is enabled: %t", a.addCommandLineProperties)
 }
 
@@ -221,6 +269,30 @@ func (a *BaseApplication) Run() {
 	log.Warn("application is not implemented!")
 }
 
+// Shutdown stops all schedulers, then destroys the singleton components in reverse dependency order
+// and closes the log file
+func (a *BaseApplication) Shutdown() error {
+	a.shutdownOnce.Do(func() {
+		log.Info("Shutting down Hiboot Application")
+		a.Publish(&ShuttingDownEvent{})
+		if a.stopWatching != nil {
+			close(a.stopWatching)
+		}
+		for _, sch := range a.schedulers {
+			sch.Stop()
+		}
+		// wait for the asynchronous event listeners before the components are destroyed
+		if a.eventPublisher != nil {
+			a.eventPublisher.close()
+		}
+		if a.configurableFactory != nil {
+			a.configurableFactory.DestroyComponents()
+		}
+		closeLogFile()
+	})
+	return nil
+}
+
 // GetInstance get application instance by name
 func (a *BaseApplication) GetInstance(params ...interface{}) (instance interface{}) {
 	if a.configurableFactory != nil {
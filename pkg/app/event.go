@@ -0,0 +1,287 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/inject/annotation"
+	"github.com/hidevopsio/hiboot/pkg/log"
+	"github.com/hidevopsio/hiboot/pkg/system"
+)
+
+const (
+	// ApplicationEventPublisherName is the instance name of the application event publisher
+	ApplicationEventPublisherName = "github.com/hidevopsio/hiboot/pkg/app.applicationEventPublisher"
+
+	defaultEventWorkers   = 4
+	defaultEventQueueSize = 256
+)
+
+var (
+	// ErrInvalidEventListener the event listener should be a func or a method that has only one event parameter
+	ErrInvalidEventListener = errors.New("[app] invalid event listener, it should have only one event parameter")
+
+	eventListeners []interface{}
+)
+
+// ApplicationEventPublisher publishes the application event to the listeners, the listeners are the methods that are
+// annotated with at.EventListener, or the funcs that are registered by RegisterEventListener
+type ApplicationEventPublisher interface {
+	Publish(event interface{})
+}
+
+// ApplicationStartingEvent is published once the application starts to build, before the properties are bound
+type ApplicationStartingEvent struct {
+}
+
+// PropertiesBoundEvent is published once the system properties are bound
+type PropertiesBoundEvent struct {
+	SystemConfig *system.Configuration
+}
+
+// ContextRefreshedEvent is published once all components are built and initialized
+type ContextRefreshedEvent struct {
+}
+
+// ServerStartedEvent is published once the listeners of the web server are bound, so the server is ready to accept
+// the connections
+type ServerStartedEvent struct {
+	// Address is the bound address of the web server, e.g. [::]:8080, the port is the actual one if server.port is 0
+	Address string
+	// ManagementAddress is the address of the management server, it is empty if the management endpoints are served by the web server
+	ManagementAddress string
+}
+
+// ShuttingDownEvent is published once the application starts to shut down, before the components are destroyed
+type ShuttingDownEvent struct {
+}
+
+// RegisterEventListener registers the event listener funcs, e.g. func(event *app.ApplicationStartingEvent),
+// it is the only way to listen to the events that are published before the components are built
+func RegisterEventListener(listeners ...interface{}) {
+	eventListeners = append(eventListeners, listeners...)
+}
+
+type eventListener struct {
+	name      string
+	fn        reflect.Value
+	args      []reflect.Value
+	eventType reflect.Type
+	eventIdx  int
+	async     bool
+	order     int
+}
+
+func newEventListener(name string, fn reflect.Value, args []reflect.Value) (l *eventListener, err error) {
+	fnTyp := fn.Type()
+	l = &eventListener{name: name, fn: fn, args: args, eventIdx: -1}
+	for i := len(args); i < fnTyp.NumIn(); i++ {
+		if l.eventType != nil {
+			return nil, ErrInvalidEventListener
+		}
+		l.eventType, l.eventIdx = fnTyp.In(i), i
+	}
+	if l.eventType == nil {
+		return nil, ErrInvalidEventListener
+	}
+	return
+}
+
+func (l *eventListener) accept(eventType reflect.Type) bool {
+	return l.eventType == eventType || (l.eventType.Kind() == reflect.Interface && eventType.Implements(l.eventType))
+}
+
+func (l *eventListener) invoke(event interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("event listener %v panic: %v", l.name, r)
+		}
+	}()
+	inputs := make([]reflect.Value, 0, len(l.args)+1)
+	inputs = append(inputs, l.args[:l.eventIdx]...)
+	inputs = append(inputs, reflect.ValueOf(event))
+	inputs = append(inputs, l.args[l.eventIdx:]...)
+	results := l.fn.Call(inputs)
+	if len(results) != 0 {
+		if err, ok := results[len(results)-1].Interface().(error); ok && err != nil {
+			log.Errorf("event listener %v failed: %v", l.name, err)
+		}
+	}
+}
+
+type eventPublisher struct {
+	mu        sync.RWMutex
+	listeners []*eventListener
+	workers   int
+	queueSize int
+	once      sync.Once
+	queue     chan func()
+	wg        sync.WaitGroup
+	closed    bool
+}
+
+func newEventPublisher() *eventPublisher {
+	p := &eventPublisher{
+		workers:   defaultEventWorkers,
+		queueSize: defaultEventQueueSize,
+	}
+	for _, fn := range eventListeners {
+		p.addFunc(fn)
+	}
+	return p
+}
+
+// configure sets the size of the worker pool before it is started
+func (p *eventPublisher) configure(properties system.Event) {
+	if properties.Workers > 0 {
+		p.workers = properties.Workers
+	}
+	if properties.QueueSize > 0 {
+		p.queueSize = properties.QueueSize
+	}
+}
+
+func (p *eventPublisher) add(l *eventListener) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.listeners = append(p.listeners, l)
+	sort.SliceStable(p.listeners, func(i, j int) bool {
+		return p.listeners[i].order < p.listeners[j].order
+	})
+}
+
+func (p *eventPublisher) addFunc(fn interface{}) {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func {
+		log.Errorf("%v: %v", ErrInvalidEventListener, fn)
+		return
+	}
+	l, err := newEventListener(fv.Type().String(), fv, nil)
+	if err != nil {
+		log.Errorf("%v: %v", err, fv.Type())
+		return
+	}
+	p.add(l)
+}
+
+// subscribe finds the methods that are annotated with at.EventListener of the singleton components
+func (p *eventPublisher) subscribe(f factory.InstantiateFactory) {
+	seen := make(map[uintptr]bool)
+	for _, item := range f.Items() {
+		md := factory.CastMetaData(item)
+		if md == nil || md.Instance == nil {
+			continue
+		}
+		iv := reflect.ValueOf(md.Instance)
+		if iv.Kind() != reflect.Ptr || seen[iv.Pointer()] {
+			continue
+		}
+		seen[iv.Pointer()] = true
+		methods, annotations := annotation.FindAnnotatedMethods(md.Instance, at.EventListener{})
+		for i, method := range methods {
+			ann := annotations[i]
+			_ = annotation.Inject(ann)
+			el := ann.Field.Value.Interface().(at.EventListener)
+			// the receiver and the annotation are the fixed arguments of the method
+			args := []reflect.Value{iv, ann.Parent.Value}
+			if method.Type.NumIn() < 2 || !annotation.IsAnnotation(method.Type.In(1)) {
+				log.Errorf("%v: %v.%v", ErrInvalidEventListener, md.Name, method.Name)
+				continue
+			}
+			l, err := newEventListener(md.Name+"."+method.Name, method.Func, args)
+			if err != nil {
+				log.Errorf("%v: %v.%v", err, md.Name, method.Name)
+				continue
+			}
+			l.async, l.order = el.AtAsync, el.AtOrder
+			log.Debugf("event listener %v listens to %v", l.name, l.eventType)
+			p.add(l)
+		}
+	}
+}
+
+func (p *eventPublisher) start() {
+	p.queue = make(chan func(), p.queueSize)
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for task := range p.queue {
+				task()
+			}
+		}()
+	}
+}
+
+// Publish delivers the event to the listeners that listen to the type of the event in order,
+// the asynchronous listeners are called by the worker pool
+func (p *eventPublisher) Publish(event interface{}) {
+	if event == nil {
+		return
+	}
+	eventType := reflect.TypeOf(event)
+	p.mu.RLock()
+	listeners := p.listeners
+	p.mu.RUnlock()
+	for _, l := range listeners {
+		if !l.accept(eventType) {
+			continue
+		}
+		if l.async {
+			p.dispatch(l, event)
+		} else {
+			l.invoke(event)
+		}
+	}
+}
+
+func (p *eventPublisher) dispatch(l *eventListener, event interface{}) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	// the listener is called synchronously once the publisher is closed
+	if p.closed {
+		l.invoke(event)
+		return
+	}
+	p.once.Do(p.start)
+	select {
+	case p.queue <- func() { l.invoke(event) }:
+	default:
+		// the publisher calls the listener if the queue is full
+		log.Warnf("event queue is full, event listener %v is called synchronously", l.name)
+		l.invoke(event)
+	}
+}
+
+// close waits for the asynchronous listeners to complete
+func (p *eventPublisher) close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	if p.queue != nil {
+		close(p.queue)
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
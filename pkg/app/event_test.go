@@ -0,0 +1,138 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderCreatedEvent struct {
+	ID string
+}
+
+type orderListener struct {
+	mu        sync.Mutex
+	received  []string
+	async     sync.WaitGroup
+	refreshed bool
+	shutdown  bool
+}
+
+func newOrderListener() *orderListener {
+	return &orderListener{}
+}
+
+func (l *orderListener) record(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.received = append(l.received, name)
+}
+
+func (l *orderListener) OnSecond(_ struct {
+	at.EventListener `order:"2"`
+}, event *orderCreatedEvent) {
+	l.record("second:" + event.ID)
+}
+
+func (l *orderListener) OnFirst(_ struct {
+	at.EventListener `order:"1"`
+}, event *orderCreatedEvent) {
+	l.record("first:" + event.ID)
+}
+
+func (l *orderListener) OnAsync(_ struct {
+	at.EventListener `async:"true"`
+}, event *orderCreatedEvent) {
+	l.async.Done()
+}
+
+func (l *orderListener) OnContextRefreshed(_ struct{ at.EventListener }, event *app.ContextRefreshedEvent) {
+	l.refreshed = true
+}
+
+func (l *orderListener) OnShuttingDown(_ struct{ at.EventListener }, event *app.ShuttingDownEvent) {
+	l.shutdown = true
+}
+
+type orderService struct {
+	publisher app.ApplicationEventPublisher
+}
+
+func newOrderService(publisher app.ApplicationEventPublisher) *orderService {
+	return &orderService{publisher: publisher}
+}
+
+func (s *orderService) Create(id string) {
+	s.publisher.Publish(&orderCreatedEvent{ID: id})
+}
+
+func TestEventPublisher(t *testing.T) {
+	mux.Lock()
+	defer mux.Unlock()
+
+	var starting, bound int
+	app.RegisterEventListener(
+		func(event *app.ApplicationStartingEvent) {
+			starting++
+		},
+		func(event *app.PropertiesBoundEvent) {
+			if event.SystemConfig != nil {
+				bound++
+			}
+		},
+	)
+	app.Register(newOrderListener, newOrderService)
+
+	ba := new(app.BaseApplication)
+	err := ba.Initialize()
+	assert.Equal(t, nil, err)
+	ba.Build()
+	_ = ba.BuildConfigurations()
+	ba.AfterInitialization()
+
+	listener := ba.GetInstance(orderListener{}).(*orderListener)
+	service := ba.GetInstance(orderService{}).(*orderService)
+
+	t.Run("should publish the events before the components are built to the registered funcs", func(t *testing.T) {
+		assert.Equal(t, 1, starting)
+		assert.Equal(t, 1, bound)
+	})
+
+	t.Run("should publish ContextRefreshedEvent", func(t *testing.T) {
+		assert.Equal(t, true, listener.refreshed)
+	})
+
+	t.Run("should deliver the event to the listeners in order", func(t *testing.T) {
+		listener.async.Add(1)
+		service.Create("1")
+		listener.async.Wait()
+		assert.Equal(t, []string{"first:1", "second:1"}, listener.received)
+	})
+
+	t.Run("should not deliver the event of other types", func(t *testing.T) {
+		ba.Publish(&struct{ ID string }{ID: "2"})
+		assert.Equal(t, 2, len(listener.received))
+	})
+
+	t.Run("should publish ShuttingDownEvent", func(t *testing.T) {
+		_ = ba.Shutdown()
+		assert.Equal(t, true, listener.shutdown)
+	})
+}
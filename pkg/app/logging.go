@@ -0,0 +1,96 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/hidevopsio/hiboot/pkg/log"
+	"github.com/hidevopsio/hiboot/pkg/system"
+)
+
+var (
+	logFileMu  sync.Mutex
+	logFile    *log.RotateFile
+	reopenOnce sync.Once
+)
+
+// setLogFile writes the logs to the rotated log file if logging.file.path is set,
+// the log file is reopened on SIGHUP so that it works with the external log rotation tools as well
+func setLogFile(c *system.LogFile) {
+	if c.Path == "" {
+		return
+	}
+
+	logFileMu.Lock()
+	defer logFileMu.Unlock()
+	// the application may be initialized more than once, e.g. in tests
+	if logFile != nil && logFile.Path() == c.Path {
+		return
+	}
+
+	f, err := log.NewRotateFile(c.Path, c.MaxSize, c.MaxAge, c.MaxBackups, c.Compress)
+	if err != nil {
+		log.Errorf("failed to open log file %v: %v", c.Path, err)
+		return
+	}
+	var w io.Writer = f
+	if c.Stdout {
+		w = io.MultiWriter(os.Stdout, f)
+	}
+	log.SetOutput(w)
+
+	if logFile != nil {
+		_ = logFile.Close()
+	}
+	logFile = f
+	reopenOnce.Do(func() {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		go reopenLogFile(hup)
+	})
+}
+
+// closeLogFile closes the log file on shutdown, the logs are written to stdout afterwards
+func closeLogFile() {
+	logFileMu.Lock()
+	defer logFileMu.Unlock()
+	if logFile == nil {
+		return
+	}
+	log.SetOutput(os.Stdout)
+	if err := logFile.Close(); err != nil {
+		log.Errorf("failed to close log file %v: %v", logFile.Path(), err)
+	}
+	logFile = nil
+}
+
+func reopenLogFile(hup chan os.Signal) {
+	for range hup {
+		logFileMu.Lock()
+		if logFile == nil {
+			logFileMu.Unlock()
+			continue
+		}
+		if err := logFile.Reopen(); err != nil {
+			log.Errorf("failed to reopen log file %v: %v", logFile.Path(), err)
+		}
+		logFileMu.Unlock()
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/log"
+	"github.com/hidevopsio/hiboot/pkg/system"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLogFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	defer closeLogFile()
+
+	setLogFile(&system.LogFile{Path: path, MaxSize: 1, Stdout: true})
+	// should not open the same log file twice
+	f := logFile
+	setLogFile(&system.LogFile{Path: path, MaxSize: 1, Stdout: true})
+	assert.Equal(t, f, logFile)
+
+	log.Warn("written to log file")
+	b, err := os.ReadFile(path)
+	assert.Equal(t, nil, err)
+	assert.Contains(t, string(b), "written to log file")
+
+	t.Run("should reopen log file on SIGHUP", func(t *testing.T) {
+		assert.Equal(t, nil, os.Rename(path, filepath.Join(dir, "moved.log")))
+		p, err := os.FindProcess(os.Getpid())
+		assert.Equal(t, nil, err)
+		assert.Equal(t, nil, p.Signal(syscall.SIGHUP))
+
+		var content string
+		for i := 0; i < 50 && !strings.Contains(content, "after SIGHUP"); i++ {
+			time.Sleep(10 * time.Millisecond)
+			log.Warn("after SIGHUP")
+			b, _ = os.ReadFile(path)
+			content = string(b)
+		}
+		assert.Contains(t, content, "after SIGHUP")
+	})
+
+	t.Run("should close log file on shutdown", func(t *testing.T) {
+		assert.Equal(t, nil, new(BaseApplication).Shutdown())
+		assert.Equal(t, (*log.RotateFile)(nil), logFile)
+		// the logs are written to stdout after shutdown
+		log.Warn("after shutdown")
+		b, _ = os.ReadFile(path)
+		assert.NotContains(t, string(b), "after shutdown")
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/log"
+	"github.com/hidevopsio/hiboot/pkg/system"
+)
+
+// OnRefresh applies the refreshed logging properties once the configuration properties are refreshed
+func (a *BaseApplication) OnRefresh(event *factory.RefreshEvent) {
+	for _, p := range event.Properties {
+		if logging, ok := p.(*system.Logging); ok {
+			applyLogging(logging)
+		}
+	}
+}
+
+// watchConfig refreshes the configuration properties once the config files are changed
+func (a *BaseApplication) watchConfig(interval time.Duration, stop chan struct{}) {
+	reloader, ok := a.configurableFactory.Builder().(system.Reloader)
+	if !ok || interval <= 0 {
+		log.Warn("configuration hot reload is not supported")
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	files := reloader.ConfigFiles()
+	for {
+		select {
+		case <-ticker.C:
+			latest := reloader.ConfigFiles()
+			if reflect.DeepEqual(files, latest) {
+				continue
+			}
+			files = latest
+			if _, err := a.configurableFactory.Refresh(); err != nil {
+				log.Errorf("failed to refresh configuration properties: %v", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
@@ -15,11 +15,16 @@
 package web
 
 import (
+	stdcontext "context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"regexp"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/hidevopsio/hiboot/pkg/app"
@@ -60,6 +65,15 @@ type application struct {
 	dispatcher  *Dispatcher
 	//controllerMap map[string][]interface{}
 	startUpTime time.Time
+	server      *http.Server
+	quit        chan os.Signal
+	// stopped is closed once Shutdown is completed
+	stopped  chan struct{}
+	stopOnce sync.Once
+	// handlers are the global middleware that are applied to both the application and management server
+	handlers         []iris.Handler
+	management       *webApp
+	managementServer *http.Server
 }
 
 var (
@@ -95,28 +109,125 @@ func (a *application) Run() {
 		if conf.Server.Port != "" {
 			serverPort = fmt.Sprintf(":%v", conf.Server.Port)
 		}
-		log.Infof("Hiboot started on port(s) http://localhost%v", serverPort)
 		timeDiff := time.Since(a.startUpTime)
 		log.Infof("Started %v in %f seconds", conf.App.Name, timeDiff.Seconds())
 		// build web app
 		a.webApp.Configure(iris.WithConfiguration(defaultConfiguration()))
 		err = a.webApp.Build()
 
-		// handler to Serve HTTP
-		http.Handle("/", a.webApp)
+		if err == nil && a.management != nil {
+			a.management.Configure(iris.WithConfiguration(defaultConfiguration()))
+			err = a.management.Build()
+		}
 
 		// serve web app with server port, default port number is 8080
 		if err == nil {
-			if conf.Server.TlsCert != "" && conf.Server.TlsKey != "" {
-				log.Infof("Serving Hiboot web application with TLS")
-				err = http.ListenAndServeTLS(serverPort, conf.Server.TlsCert, conf.Server.TlsKey, nil)
-			} else {
-				log.Infof("Serving Hiboot web application")
-				err = http.ListenAndServe(serverPort, nil)
-				log.Debug(err)
+			a.server = newServer(conf.Server, serverPort, a.webApp)
+			if a.management != nil {
+				props := a.GetInstance(managementProperties{}).(*managementProperties)
+				managementAddr := fmt.Sprintf("%v:%v", props.Server.Address, props.Server.Port)
+				a.managementServer = newServer(conf.Server, managementAddr, a.management)
+			}
+			err = a.serve()
+			log.Debug(err)
+		}
+	} else if err != nil {
+		log.Errorf("failed to build the application: %v", err)
+	}
+}
+
+// serve binds the listeners and publishes ServerStartedEvent, then serves the http requests until the servers are
+// stopped or the process receives SIGINT/SIGTERM
+func (a *application) serve() (err error) {
+	ln, err := net.Listen("tcp", a.server.Addr)
+	if err != nil {
+		return
+	}
+	var managementLn net.Listener
+	if a.managementServer != nil {
+		if managementLn, err = net.Listen("tcp", a.managementServer.Addr); err != nil {
+			_ = ln.Close()
+			return
+		}
+	}
+	signal.Notify(a.quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(a.quit)
+
+	errCh := make(chan error, 2)
+	event := &app.ServerStartedEvent{Address: ln.Addr().String()}
+	if managementLn != nil {
+		event.ManagementAddress = managementLn.Addr().String()
+		log.Infof("Management endpoints started on %v", event.ManagementAddress)
+		go func() {
+			errCh <- a.serveListener(a.managementServer, managementLn)
+		}()
+	}
+	log.Infof("Hiboot started on %v", event.Address)
+	go func() {
+		errCh <- a.serveListener(a.server, ln)
+	}()
+
+	a.Publish(event)
+
+	select {
+	case err = <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			// the server is stopped by Shutdown, wait until the in-flight requests are drained and
+			// the components are destroyed
+			<-a.stopped
+			err = nil
+		} else if a.managementServer != nil {
+			// one of the servers failed to start, stop the other one as well
+			_ = a.server.Close()
+			_ = a.managementServer.Close()
+		}
+	case sig := <-a.quit:
+		log.Infof("Received signal %v, shutting down gracefully", sig)
+		err = a.Shutdown()
+	}
+	return
+}
+
+// serveListener serves the http requests of srv on ln, with TLS if server.tls_cert and server.tls_key are set,
+// the management server shares the certificate of the application server
+func (a *application) serveListener(srv *http.Server, ln net.Listener) error {
+	conf := a.SystemConfig()
+	if conf.Server.TlsCert != "" && conf.Server.TlsKey != "" {
+		log.Infof("Serving %v with TLS", ln.Addr())
+		return srv.ServeTLS(ln, conf.Server.TlsCert, conf.Server.TlsKey)
+	}
+	log.Infof("Serving %v", ln.Addr())
+	return srv.Serve(ln)
+}
+
+// Shutdown stops accepting new connections and waits server.shutdown_timeout seconds for in-flight requests,
+// then stops the schedulers and destroys the singleton components, Run returns once it is completed
+func (a *application) Shutdown() (err error) {
+	defer a.stopOnce.Do(func() {
+		if a.stopped != nil {
+			close(a.stopped)
+		}
+	})
+	if a.server != nil {
+		timeout := 30 * time.Second
+		conf := a.SystemConfig()
+		if conf != nil && conf.Server.ShutdownTimeout > 0 {
+			timeout = time.Duration(conf.Server.ShutdownTimeout) * time.Second
+		}
+		ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), timeout)
+		defer cancel()
+		err = a.server.Shutdown(ctx)
+		if err != nil {
+			log.Errorf("server shutdown: %v", err)
+		}
+		if a.managementServer != nil {
+			if e := a.managementServer.Shutdown(ctx); e != nil {
+				log.Errorf("management server shutdown: %v", e)
 			}
 		}
 	}
+	_ = a.BaseApplication.Shutdown()
+	return
 }
 
 func unique(intSlice []string) []string {
@@ -167,6 +278,15 @@ func (a *application) build() (err error) {
 	// create dispatcher
 	a.dispatcher = a.GetInstance(Dispatcher{}).(*Dispatcher)
 
+	// serve management endpoints on a separate web app if management.server.port is set
+	props := a.GetInstance(managementProperties{}).(*managementProperties)
+	if props.Server.Port != "" && props.Server.Port != systemConfig.Server.Port {
+		a.management = newWebApplication()
+		a.management.Use(a.handlers...)
+		a.dispatcher.management = a.management
+		a.dispatcher.managementContextPath = props.Server.ContextPath
+	}
+
 	// first register anon controllers
 	err = a.RegisterController(at.RestController{})
 	if err == nil {
@@ -193,7 +313,13 @@ func (a *application) RegisterController(controller interface{}) error {
 func (a *application) Use(handlers ...context.Handler) {
 	// pass user's instances
 	for _, hdl := range handlers {
-		a.webApp.Use(Handler(hdl))
+		h := Handler(hdl)
+		a.handlers = append(a.handlers, h)
+		a.webApp.Use(h)
+		// the management app is created by build, apply the middleware that is added afterwards as well
+		if a.management != nil {
+			a.management.Use(h)
+		}
 	}
 }
 
@@ -206,6 +332,8 @@ func (a *application) initialize(controllers ...interface{}) (err error) {
 
 	// new iris app
 	a.webApp = newWebApplication()
+	a.quit = make(chan os.Signal, 1)
+	a.stopped = make(chan struct{})
 	app.Register(a.webApp)
 
 	err = a.Initialize()
@@ -17,16 +17,30 @@
 package web_test
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/hidevopsio/hiboot/pkg/app"
 	"github.com/hidevopsio/hiboot/pkg/app/web"
 	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/app/web/server"
 	_ "github.com/hidevopsio/hiboot/pkg/app/web/statik"
 	"github.com/hidevopsio/hiboot/pkg/at"
 	"github.com/hidevopsio/hiboot/pkg/inject/annotation"
@@ -734,11 +748,11 @@ func TestWebApplication(t *testing.T) {
 			Expect().Status(http.StatusUnauthorized)
 	})
 
-	t.Run("should return http.StatusInternalServerError when input form field validation failed", func(t *testing.T) {
+	t.Run("should return http.StatusBadRequest when input form field can not be bound", func(t *testing.T) {
 		// test request form
 		testApp.Post("/foobar").
 			WithFormField("name", "John Doe").
-			Expect().Status(http.StatusInternalServerError)
+			Expect().Status(http.StatusBadRequest)
 	})
 
 	t.Run("should return (http.StatusOK on /foobar", func(t *testing.T) {
@@ -791,20 +805,20 @@ func TestWebApplication(t *testing.T) {
 			Expect().Status(http.StatusOK)
 	})
 
-	t.Run("should return http.StatusOK on /foo with PUT, PATCH, DELETE methods", func(t *testing.T) {
+	t.Run("should return http.StatusBadRequest if the path variable can not be converted", func(t *testing.T) {
 		testApp.Put("/foo/id/{id}/name/{name}/age/{age}").
 			WithPath("id", " ").
 			WithPath("name", " ").
 			WithPath("age", " ").
-			Expect().Status(http.StatusOK)
+			Expect().Status(http.StatusBadRequest)
 	})
 
-	t.Run("should return http.StatusOK on /foo with PUT, PATCH, DELETE methods", func(t *testing.T) {
+	t.Run("should return http.StatusBadRequest if the path variable can not be converted", func(t *testing.T) {
 		testApp.Put("/foo/id/{id}/name/{name}/age/{age}").
 			WithPath("id", "").
 			WithPath("name", "").
 			WithPath("age", " ").
-			Expect().Status(http.StatusOK)
+			Expect().Status(http.StatusBadRequest)
 	})
 
 	t.Run("should return http.StatusOK on /foo with PUT, PATCH, DELETE methods", func(t *testing.T) {
@@ -941,6 +955,140 @@ func TestNewApplication(t *testing.T) {
 	mu.Unlock()
 }
 
+// startedEvent is the ServerStartedEvent with the error of dialing its address in the event listener
+type startedEvent struct {
+	*app.ServerStartedEvent
+	dialErr error
+}
+
+var startedEvents = make(chan startedEvent, 1)
+
+func init() {
+	app.RegisterEventListener(func(event *app.ServerStartedEvent) {
+		conn, err := net.Dial("tcp", event.Address)
+		if err == nil {
+			_ = conn.Close()
+		}
+		select {
+		case startedEvents <- startedEvent{event, err}:
+		default:
+		}
+	})
+}
+
+// runApplication runs the application in background and waits for ServerStartedEvent,
+// done is closed once the application stops running
+func runApplication(t *testing.T, testApp app.Application) (event startedEvent, done chan struct{}) {
+	for len(startedEvents) > 0 {
+		<-startedEvents
+	}
+	done = make(chan struct{})
+	go func() {
+		testApp.Run()
+		close(done)
+	}()
+	select {
+	case event = <-startedEvents:
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("web application is not started")
+	}
+	return
+}
+
+func TestServerStartedEvent(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	t.Run("should publish the bound address", func(t *testing.T) {
+		testApp := web.NewApplication(newPingController).
+			SetProperty(server.Port, 0).
+			SetProperty(server.ShutdownTimeout, 1).
+			SetProperty(app.BannerDisabled, true)
+		event, done := runApplication(t, testApp)
+		assert.NotEqual(t, nil, event.ServerStartedEvent)
+		assert.Equal(t, nil, event.dialErr)
+		_, port, _ := net.SplitHostPort(event.Address)
+		assert.NotEqual(t, "0", port)
+
+		resp, err := http.Get("http://" + event.Address + "/ping")
+		assert.Equal(t, nil, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		assert.Equal(t, nil, testApp.Shutdown())
+		<-done
+	})
+
+	t.Run("should not publish if the port is in use", func(t *testing.T) {
+		ln, err := net.Listen("tcp", ":0")
+		assert.Equal(t, nil, err)
+		defer ln.Close()
+		testApp := web.NewApplication(newPingController).
+			SetProperty(server.Port, ln.Addr().(*net.TCPAddr).Port).
+			SetProperty(app.BannerDisabled, true)
+		event, done := runApplication(t, testApp)
+		assert.Equal(t, (*app.ServerStartedEvent)(nil), event.ServerStartedEvent)
+		<-done
+	})
+}
+
+type slowController struct {
+	at.RestController
+	closed atomic.Bool
+}
+
+func (c *slowController) Get() string {
+	time.Sleep(300 * time.Millisecond)
+	return "done"
+}
+
+// Close is called once the application is shut down
+func (c *slowController) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+func TestShutdown(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+	ctrl := new(slowController)
+	testApp := web.NewApplication(func() *slowController { return ctrl }).
+		SetProperty(server.Port, 0).
+		SetProperty(server.ShutdownTimeout, 1).
+		SetProperty(app.BannerDisabled, true)
+	event, done := runApplication(t, testApp)
+
+	t.Run("should shutdown web application gracefully", func(t *testing.T) {
+		responses := make(chan string, 1)
+		go func() {
+			resp, err := http.Get("http://" + event.Address + "/slow")
+			if err != nil {
+				responses <- err.Error()
+				return
+			}
+			defer resp.Body.Close()
+			buf := new(bytes.Buffer)
+			_, _ = buf.ReadFrom(resp.Body)
+			responses <- buf.String()
+		}()
+		// wait until the request is in flight
+		time.Sleep(100 * time.Millisecond)
+
+		go func() {
+			_ = testApp.Shutdown()
+		}()
+		select {
+		case <-done:
+			// Run returns once the components are destroyed
+			assert.Equal(t, true, ctrl.closed.Load())
+		case <-time.After(3 * time.Second):
+			t.Error("web application is not stopped")
+		}
+		assert.Equal(t, "done", <-responses)
+	})
+}
+
 func TestAnonymousController(t *testing.T) {
 	mu.Lock()
 	t.Run("should failed to register anonymous controller", func(t *testing.T) {
@@ -1289,3 +1437,201 @@ func TestController(t *testing.T) {
 	})
 	mu.Unlock()
 }
+
+// writeCertificate writes a self-signed certificate of localhost and its private key to the temporary directory
+func writeCertificate(t *testing.T) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Equal(t, nil, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Equal(t, nil, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Equal(t, nil, err)
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "tls.crt")
+	keyFile = filepath.Join(dir, "tls.key")
+	assert.Equal(t, nil, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Equal(t, nil, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return
+}
+
+func TestManagementServerTLS(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+	certFile, keyFile := writeCertificate(t)
+	testApp := web.NewApplication(newStatusController, newPingController).
+		SetProperty(server.Port, 0).
+		SetProperty(server.ManagementPort, 8093).
+		SetProperty(server.TlsCert, certFile).
+		SetProperty(server.TlsKey, keyFile).
+		SetProperty(server.ShutdownTimeout, 1).
+		SetProperty(app.BannerDisabled, true)
+	event, done := runApplication(t, testApp)
+	assert.NotEqual(t, nil, event.ServerStartedEvent)
+
+	client := &http.Client{Transport: &http.Transport{
+		DisableKeepAlives: true,
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+	}}
+	get := func(url string) int {
+		resp, err := client.Get(url)
+		if err != nil {
+			return 0
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	t.Run("should serve the management endpoints with the certificate of the application", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, get("https://"+event.ManagementAddress+"/status"))
+		assert.Equal(t, http.StatusOK, get("https://"+event.Address+"/ping"))
+		// the tls server responds 400 to the plain http requests
+		assert.Equal(t, http.StatusBadRequest, get("http://"+event.ManagementAddress+"/status"))
+	})
+
+	assert.Equal(t, nil, testApp.Shutdown())
+	<-done
+}
+
+type statusController struct {
+	at.RestController
+	at.ManagementEndpoint
+	at.RequestMapping `value:"/status" no_context_path:"true"`
+}
+
+func newStatusController() *statusController {
+	return &statusController{}
+}
+
+func (c *statusController) Get() string {
+	return "UP"
+}
+
+// LateRestController is the annotation of the controllers that are registered after the application is built, like the controllers of the post processors
+type LateRestController struct {
+	at.Annotation
+
+	at.BaseAnnotation
+}
+
+type lateStatusController struct {
+	LateRestController
+	at.ManagementEndpoint
+	at.RequestMapping `value:"/late-status"`
+}
+
+func newLateStatusController() *lateStatusController {
+	return &lateStatusController{}
+}
+
+func (c *lateStatusController) Get() string {
+	return "UP"
+}
+
+// latePostProcessor uses the middleware and registers the controllers after the application is built
+type latePostProcessor struct {
+	applicationContext app.ApplicationContext
+}
+
+func init() {
+	app.RegisterPostProcessor(newLatePostProcessor)
+}
+
+func newLatePostProcessor(applicationContext app.ApplicationContext) *latePostProcessor {
+	return &latePostProcessor{applicationContext: applicationContext}
+}
+
+func (p *latePostProcessor) AfterInitialization() {
+	p.applicationContext.Use(func(ctx context.Context) {
+		ctx.Header("X-Late", "true")
+		ctx.Next()
+	})
+	_ = p.applicationContext.RegisterController(LateRestController{})
+}
+
+type pingController struct {
+	at.RestController
+}
+
+func newPingController() *pingController {
+	return &pingController{}
+}
+
+func (c *pingController) Get() string {
+	return "pong"
+}
+
+func TestManagementServer(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	t.Run("should serve management endpoints on the application if management port is not set", func(t *testing.T) {
+		testApp := web.NewTestApp(newStatusController, newPingController).
+			SetProperty(server.ContextPath, "/api").
+			Run(t)
+		testApp.Get("/status").Expect().Status(http.StatusOK).Body().Equal("UP")
+		testApp.ManagementRequest(http.MethodGet, "/status").Expect().Status(http.StatusOK)
+	})
+
+	t.Run("should serve management endpoints on the management port", func(t *testing.T) {
+		testApp := web.NewTestApp(newStatusController, newPingController).
+			SetProperty(server.ContextPath, "/api").
+			SetProperty(server.ManagementPort, 8090).
+			SetProperty(server.ManagementContextPath, "/manage").
+			Run(t)
+		testApp.Get("/status").Expect().Status(http.StatusNotFound)
+		testApp.Get("/api/ping").Expect().Status(http.StatusOK)
+		testApp.ManagementRequest(http.MethodGet, "/manage/status").Expect().Status(http.StatusOK).Body().Equal("UP")
+		testApp.ManagementRequest(http.MethodGet, "/api/ping").Expect().Status(http.StatusNotFound)
+	})
+
+	t.Run("should apply the middleware that is used after build to the management endpoints", func(t *testing.T) {
+		testApp := web.NewTestApp(newStatusController, newLateStatusController).
+			SetProperty(server.ManagementPort, 8090).
+			Run(t)
+		testApp.ManagementRequest(http.MethodGet, "/late-status").Expect().
+			Status(http.StatusOK).Header("X-Late").Equal("true")
+	})
+}
+
+func TestManagementServerListener(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+	testApp := web.NewApplication(newStatusController, newPingController).
+		SetProperty(server.Port, 8091).
+		SetProperty(server.ManagementPort, 8092).
+		SetProperty(server.ShutdownTimeout, 1).
+		SetProperty(app.BannerDisabled, true)
+	_, done := runApplication(t, testApp)
+
+	// the pending connections of the keep-alive client would delay the shutdown
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	get := func(url string) int {
+		resp, err := client.Get(url)
+		if err != nil {
+			return 0
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	t.Run("should serve management endpoints on the management port", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, get("http://localhost:8092/status"))
+		assert.Equal(t, http.StatusNotFound, get("http://localhost:8091/status"))
+		assert.Equal(t, http.StatusOK, get("http://localhost:8091/ping"))
+	})
+
+	t.Run("should shutdown both servers", func(t *testing.T) {
+		assert.Equal(t, nil, testApp.Shutdown())
+		<-done
+		assert.Equal(t, 0, get("http://localhost:8092/status"))
+	})
+}
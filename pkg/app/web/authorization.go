@@ -0,0 +1,168 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/inject/annotation"
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+// Subject is the security view of the user who sends current request
+type Subject interface {
+	// IsAuthenticated returns true if the user has been authenticated
+	IsAuthenticated() bool
+	// HasRole returns true if the user has the specific role
+	HasRole(role string) bool
+	// IsPermitted returns true if the user is permitted to do the specific permission, e.g. user:read
+	IsPermitted(permission string) bool
+}
+
+// Authorizer resolves the Subject of current request, it is used for checking
+// at.RequiresAuthentication, at.RequiresUser, at.RequiresGuest, at.RequiresRoles and at.RequiresPermissions
+type Authorizer interface {
+	Subject(ctx context.Context) Subject
+}
+
+type authorization struct {
+	authorizer Authorizer
+	logical    at.Logical
+	items      []*annotation.Annotation
+}
+
+// newAuthorization returns nil if neither controller nor method is annotated with the at.Requires* annotations,
+// the subject is resolved by the Authorizer, e.g. the one provided by jwt starter,
+// the request is regarded as unauthenticated if there is no Authorizer, so that the annotated method is denied
+func newAuthorization(authorizer Authorizer, atController *annotation.Annotations, atMethod *annotation.Annotations) (a *authorization) {
+	var items []*annotation.Annotation
+	var logical at.Logical
+	for _, ann := range []*annotation.Annotations{atController, atMethod} {
+		for _, item := range annotation.FilterIn(ann, at.RequiresLogical{}) {
+			switch item.Field.Value.Interface().(type) {
+			case at.RequiresLogical:
+				// standalone at.RequiresLogical combines the other requires annotations
+				logical = item.Field.Value.Interface().(at.RequiresLogical).AtLogical
+			default:
+				items = append(items, item)
+			}
+		}
+		items = append(items, annotation.FilterIn(ann, at.RequiresAuthentication{})...)
+		items = append(items, annotation.FilterIn(ann, at.RequiresUser{})...)
+		items = append(items, annotation.FilterIn(ann, at.RequiresGuest{})...)
+	}
+	if len(items) != 0 {
+		a = &authorization{
+			authorizer: authorizer,
+			logical:    logical,
+			items:      items,
+		}
+	}
+	return
+}
+
+// Serve checks if the subject of current request is authorized, response 401 or 403 if it is not
+func (a *authorization) Serve(ctx context.Context) {
+	var subject Subject
+	if a.authorizer != nil {
+		subject = a.authorizer.Subject(ctx)
+	}
+
+	code := http.StatusOK
+	for _, item := range a.items {
+		code = a.check(subject, item)
+		if a.logical == at.OR && code == http.StatusOK {
+			break
+		}
+		if a.logical != at.OR && code != http.StatusOK {
+			break
+		}
+	}
+
+	switch code {
+	case http.StatusOK:
+		ctx.Next()
+	case http.StatusUnauthorized:
+		ctx.ResponseError("Unauthorized", code)
+		ctx.StopExecution()
+	default:
+		ctx.ResponseError("Forbidden", code)
+		ctx.StopExecution()
+	}
+}
+
+func (a *authorization) check(subject Subject, item *annotation.Annotation) (code int) {
+	authenticated := subject != nil && subject.IsAuthenticated()
+	code = http.StatusOK
+	switch ann := item.Field.Value.Interface().(type) {
+	case at.RequiresGuest:
+		if authenticated {
+			code = http.StatusForbidden
+		}
+	case at.RequiresAuthentication, at.RequiresUser:
+		code = authenticationCode(authenticated)
+	case at.RequiresRoles:
+		if code = authenticationCode(authenticated); code == http.StatusOK {
+			code = checkAll(ann.AtLogical, values(ann.AtValue, ann.AtValues), subject.HasRole)
+		}
+	case at.RequiresPermissions:
+		if code = authenticationCode(authenticated); code == http.StatusOK {
+			code = checkAll(ann.AtLogical, values(ann.AtValue, ann.AtValues), subject.IsPermitted)
+		}
+	default:
+		log.Warnf("unsupported authorization annotation %v", reflect.TypeOf(ann))
+		code = http.StatusForbidden
+	}
+	return
+}
+
+func authenticationCode(authenticated bool) (code int) {
+	code = http.StatusOK
+	if !authenticated {
+		code = http.StatusUnauthorized
+	}
+	return
+}
+
+func checkAll(logical at.Logical, values []string, has func(string) bool) (code int) {
+	code = http.StatusOK
+	for _, v := range values {
+		if has(v) {
+			if logical == at.OR {
+				return http.StatusOK
+			}
+		} else {
+			code = http.StatusForbidden
+			if logical != at.OR {
+				return
+			}
+		}
+	}
+	return
+}
+
+func values(value string, values []string) (retVal []string) {
+	for _, v := range append(strings.Split(value, ","), values...) {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			retVal = append(retVal, v)
+		}
+	}
+	return
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/at"
+)
+
+type securedController struct {
+	at.RestController
+	at.RequestMapping `value:"/secured"`
+}
+
+func newSecuredController() *securedController {
+	return &securedController{}
+}
+
+func (c *securedController) GetAdmin(_ struct {
+	at.GetMapping    `value:"/admin"`
+	at.RequiresRoles `values:"admin"`
+}) string {
+	return "admin"
+}
+
+func (c *securedController) GetPublic(_ struct {
+	at.GetMapping `value:"/public"`
+}) string {
+	return "public"
+}
+
+func (c *securedController) GetGuest(_ struct {
+	at.GetMapping `value:"/guest"`
+	at.RequiresGuest
+}) string {
+	return "guest"
+}
+
+func TestAuthorizationWithoutAuthorizer(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	testApp := web.NewTestApp(newSecuredController).Run(t)
+
+	t.Run("should deny the annotated method if there is no authorizer", func(t *testing.T) {
+		testApp.Get("/secured/admin").Expect().Status(http.StatusUnauthorized)
+	})
+
+	t.Run("should permit the method that is not annotated", func(t *testing.T) {
+		testApp.Get("/secured/public").Expect().Status(http.StatusOK).Body().Equal("public")
+	})
+
+	t.Run("should permit guests", func(t *testing.T) {
+		testApp.Get("/secured/guest").Expect().Status(http.StatusOK).Body().Equal("guest")
+	})
+}
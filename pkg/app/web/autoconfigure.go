@@ -15,7 +15,8 @@ const Profile = "web"
 type configuration struct {
 	at.AutoConfiguration
 
-	Properties *properties
+	Properties           *properties
+	ManagementProperties *managementProperties
 }
 
 func newWebConfiguration() *configuration {
@@ -15,6 +15,7 @@
 package web
 
 import (
+	"errors"
 	"net/http"
 	"sync"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/hidevopsio/iris"
 
 	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/inject/annotation"
 	"github.com/hidevopsio/hiboot/pkg/model"
 	"github.com/hidevopsio/hiboot/pkg/utils/mapstruct"
 	"github.com/hidevopsio/hiboot/pkg/utils/validator"
@@ -29,7 +31,13 @@ import (
 	"github.com/hidevopsio/iris/middleware/i18n"
 )
 
-const maxResponses = 2
+const (
+	maxResponses       = 2
+	requestIDKey       = "request_id"
+	routeAnnotationKey = "web.routeAnnotations"
+	// requestKey is the key of the request that is derived from the one of iris, e.g. with the deadline
+	requestKey = "web.request"
+)
 
 // Context Create your own custom Context, put any fields you wanna need.
 type Context struct {
@@ -85,6 +93,20 @@ func Handler(h func(context.Context)) iris.Handler {
 //	})
 //}
 
+// Request returns the request, it is the one that is derived with the request id or the deadline if they are set
+func (c *Context) Request() *http.Request {
+	if r, ok := c.Values().Get(requestKey).(*http.Request); ok {
+		return r
+	}
+	return c.Context.Request()
+}
+
+// setRequest sets the derived request that is returned by Request() in all handlers of current request,
+// instead of replacing the *http.Request that is shared with iris
+func setRequest(ctx context.Context, r *http.Request) {
+	ctx.Values().Set(requestKey, r)
+}
+
 // Next The second one important if you will override the Context
 // with an embedded context.Context inside it.
 // Required in order to run the chain of handlers via this "*Context".
@@ -221,19 +243,34 @@ func (c *Context) GetResponse(idx int) (response interface{}) {
 	return
 }
 
-// RequestEx get RequestBody
+// SetRequestID sets the id of current request, it is set by the access log middleware of logging starter,
+// the context of the request carries it as well, see log.RequestIDFromContext
+func (c *Context) SetRequestID(id string) {
+	c.Values().Set(requestIDKey, id)
+	setRequest(c, c.Request().WithContext(log.ContextWithRequestID(c.Request().Context(), id)))
+}
+
+// RequestID returns the id of current request, it can be included in application logs for correlation
+func (c *Context) RequestID() string {
+	return c.Values().GetString(requestIDKey)
+}
+
+// RequestEx get RequestBody, the returned error is an Error with the field errors if validation is failed
 func requestEx(c context.Context, data interface{}, cb func() error) error {
 	if cb != nil {
 		err := cb()
-		if err != nil {
-			c.ResponseError(err.Error(), http.StatusInternalServerError)
+		var httpErr *Error
+		if errors.As(err, &httpErr) {
 			return err
 		}
+		if err != nil {
+			// the request that can not be decoded or bound is malformed
+			return &Error{Status: http.StatusBadRequest, Code: "bad_request", Detail: err.Error(), cause: err}
+		}
 
 		err = validator.Validate.Struct(data)
 		if err != nil {
-			c.ResponseError(err.Error(), http.StatusBadRequest)
-			return err
+			return newValidationError(err)
 		}
 	}
 	return nil
@@ -243,15 +280,16 @@ func requestEx(c context.Context, data interface{}, cb func() error) error {
 func RequestBody(c context.Context, data interface{}) error {
 
 	return requestEx(c, data, func() error {
-		return c.ReadJSON(data)
+		return readBody(c, data, nil)
 	})
 }
 
-// RequestForm get RequestFrom
+// RequestForm get RequestFrom, the uploaded files of multipart form are bound to the fields of
+// *multipart.FileHeader, []*multipart.FileHeader, UploadedFile, *UploadedFile and []*UploadedFile
 func RequestForm(c context.Context, data interface{}) error {
 
 	return requestEx(c, data, func() error {
-		return c.ReadForm(data)
+		return readForm(c, data, defaultMaxMemory, uploadLimit{})
 	})
 }
 
@@ -267,3 +305,23 @@ func RequestParams(c context.Context, data interface{}) error {
 		return nil
 	})
 }
+
+// routeAnnotations holds the annotations of the controller and method that handle current request
+type routeAnnotations struct {
+	controller *annotation.Annotations
+	method     *annotation.Annotations
+}
+
+// RouteAnnotation returns the annotation att of the controller method that handles current request,
+// the method annotation takes precedence over the controller annotation, nil is returned if neither is annotated
+func RouteAnnotation(c context.Context, att interface{}) (ann *annotation.Annotation) {
+	ra, ok := c.Values().Get(routeAnnotationKey).(*routeAnnotations)
+	if !ok {
+		return
+	}
+	ann = annotation.GetAnnotation(ra.method, att)
+	if ann == nil {
+		ann = annotation.GetAnnotation(ra.controller, att)
+	}
+	return
+}
@@ -20,6 +20,8 @@ type ExtendedContext interface {
 	SetResponse(idx int, response interface{})
 	GetResponses() (responses []interface{})
 	GetResponse(idx int) (response interface{})
+	SetRequestID(id string)
+	RequestID() string
 	//StaticResource(system http.FileSystem)
 }
 
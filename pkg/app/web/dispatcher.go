@@ -65,6 +65,22 @@ type Dispatcher struct {
 	ContextPathFormat string `value:"${server.context_path_format}" `
 
 	methodSubscribers []*factory.MetaData
+	authorizer        Authorizer
+	mappings          []*Mapping
+
+	// management is the web app of management endpoints if management.server.port is set
+	management            *webApp
+	managementContextPath string
+}
+
+// Mapping is the request mapping that is registered by Dispatcher
+type Mapping struct {
+	Method         string   `json:"method"`
+	Path           string   `json:"path"`
+	Handler        string   `json:"handler"`
+	Middleware     []string `json:"middleware,omitempty"`
+	PostMiddleware []string `json:"post_middleware,omitempty"`
+	Management     bool     `json:"management,omitempty"`
 }
 
 type requestMapping struct {
@@ -78,6 +94,7 @@ type injectableMethod struct {
 	handler             iris.Handler
 	hasMethodAnnotation bool
 	requestMapping      *requestMapping
+	pathConstraints     map[string]*pathConstraint
 }
 
 type injectableObject struct {
@@ -89,6 +106,9 @@ type injectableObject struct {
 	after       *injectableMethod
 	methods     []*injectableMethod
 	annotations *annotation.Annotations
+	management  bool
+	// pathConstraints are the constraints of the path variables of pathPrefix, e.g. {id:int}
+	pathConstraints map[string]*pathConstraint
 }
 
 type Annotations struct {
@@ -141,6 +161,7 @@ func (d *Dispatcher) parseMiddleware(m *factory.MetaData, mwHdl interface{}) (mi
 	middleware.annotations = annotations
 
 	mwType := mwi.Type()
+	middleware.name = reflector.IndirectType(mwType).String()
 	numOfMethod := mwi.NumMethod()
 	for mi := 0; mi < numOfMethod; mi++ {
 		methodHandler := new(injectableMethod)
@@ -161,7 +182,7 @@ func (d *Dispatcher) parseMiddleware(m *factory.MetaData, mwHdl interface{}) (mi
 	return
 }
 
-func (d *Dispatcher) parseRestController(ctl *factory.MetaData) (restController *injectableObject) {
+func (d *Dispatcher) parseRestController(ctl *factory.MetaData) (restController *injectableObject, err error) {
 
 	restController = new(injectableObject)
 
@@ -181,18 +202,30 @@ func (d *Dispatcher) parseRestController(ctl *factory.MetaData) (restController
 	annotations := annotation.GetAnnotations(controller)
 	restController.annotations = annotations
 
+	// management endpoints are served under management.server.context_path on the management port
+	contextPath := d.SystemServer.ContextPath
+	if d.management != nil && annotation.Contains(controller, at.ManagementEndpoint{}) {
+		restController.management = true
+		contextPath = d.managementContextPath
+	}
+
 	// get context mapping
 	var customizedControllerPath bool
-	pathPrefix := d.SystemServer.ContextPath
+	pathPrefix := contextPath
 	af := annotation.GetAnnotation(controller, at.RequestMapping{})
 	if af != nil {
 		customizedControllerPath = true
 		ann := af.Field.Value.Interface().(at.RequestMapping)
-		// ignore context path
-		if ann.AtNoContextPath {
-			pathPrefix = ann.AtValue
+		// the value may reference properties, e.g. `value:"${actuator.base_path}/health"`
+		mappingPath := ann.AtValue
+		if p, ok := d.configurableFactory.Replace(mappingPath).(string); ok {
+			mappingPath = p
+		}
+		// ignore context path, management endpoints always use management.server.context_path
+		if ann.AtNoContextPath && !restController.management {
+			pathPrefix = mappingPath
 		} else {
-			pathPrefix = path.Join(pathPrefix, ann.AtValue)
+			pathPrefix = path.Join(pathPrefix, mappingPath)
 		}
 	}
 
@@ -217,13 +250,15 @@ func (d *Dispatcher) parseRestController(ctl *factory.MetaData) (restController
 		case app.ContextPathFormatLowerCamel:
 			cn = str.ToLowerCamel(controllerName)
 		}
-		contextPath := d.SystemServer.ContextPath
 		//if contextPath == ContextPathRoot {
 		//	contextPath = ""
 		//}
 		pathPrefix = fmt.Sprintf("%v/%v", contextPath, cn)
 	}
-	restController.pathPrefix = pathPrefix
+	restController.pathPrefix, restController.pathConstraints, err = parsePathConstraints(pathPrefix)
+	if err != nil {
+		return
+	}
 	restController.name = fieldName
 
 	numOfMethod := field.NumMethod()
@@ -292,6 +327,17 @@ func (d *Dispatcher) parseRestController(ctl *factory.MetaData) (restController
 			}
 			reqMap.Value = apiPath
 		}
+		reqMap.Value, restMethod.pathConstraints, err = parsePathConstraints(reqMap.Value)
+		if err != nil {
+			return
+		}
+		// the method fails to register rather than responding 406 to every request
+		if ann := annotation.GetAnnotation(restMethod.annotations, at.Produces{}); ann != nil {
+			if err = checkProduces(ann.Field.Value.Interface().(at.Produces).AtValues); err != nil {
+				err = fmt.Errorf("%w of %v.%v", err, fieldName, methodName)
+				return
+			}
+		}
 		restMethod.requestMapping = reqMap
 
 		hasAnyMethod := reqMap.Method == Any
@@ -352,6 +398,9 @@ func (d *Dispatcher) useMiddleware(mw []*annotation.Annotation, mwMth []*annotat
 func (d *Dispatcher) register(controllers []*factory.MetaData, middleware []*factory.MetaData) (err error) {
 
 	d.methodSubscribers = d.configurableFactory.GetInstances(at.HttpMethodSubscriber{})
+	if authorizer, ok := d.configurableFactory.GetInstance(new(Authorizer)).(Authorizer); ok {
+		d.authorizer = authorizer
+	}
 
 	var mws []*injectableObject
 	var postMws []*injectableObject
@@ -370,16 +419,25 @@ func (d *Dispatcher) register(controllers []*factory.MetaData, middleware []*fac
 	log.Debug("register rest controller")
 	for _, ctl := range controllers {
 		// get and parse all controller methods
-		restController := d.parseRestController(ctl)
+		var restController *injectableObject
+		restController, err = d.parseRestController(ctl)
+		if err != nil {
+			return
+		}
+
+		router := d.webApp
+		if restController.management {
+			router = d.management
+		}
 
 		var party iris.Party
 		if restController.before != nil {
 			hdl := newHandler(d.configurableFactory, restController, restController.before, at.BeforeMethod{})
-			party = d.webApp.Party(restController.pathPrefix, Handler(func(c context.Context) {
+			party = router.Party(restController.pathPrefix, Handler(func(c context.Context) {
 				hdl.call(c)
 			}))
 		} else {
-			party = d.webApp.Party(restController.pathPrefix)
+			party = router.Party(restController.pathPrefix)
 		}
 
 		if restController.after != nil {
@@ -394,6 +452,7 @@ func (d *Dispatcher) register(controllers []*factory.MetaData, middleware []*fac
 		for _, m := range restController.methods {
 			var handlers []iris.Handler
 			var postHandlers []iris.Handler
+			mapping := new(Mapping)
 
 			atCtlMth := annotation.FilterIn(m.annotations, at.UseMiddleware{})
 
@@ -405,17 +464,18 @@ func (d *Dispatcher) register(controllers []*factory.MetaData, middleware []*fac
 			// handlers = append(handlers, middleware...)
 
 			// set matched to true by default
-			handlers = d.appendMiddleware(mws, atCtl, atCtlMth, handlers)
-			postHandlers = d.appendMiddleware(postMws, atCtl, atCtlMth, postHandlers)
+			handlers, mapping.Middleware = d.appendMiddleware(mws, atCtl, atCtlMth, handlers)
+			postHandlers, mapping.PostMiddleware = d.appendMiddleware(postMws, atCtl, atCtlMth, postHandlers)
 
 			// 3. finally, handle all method handlers
-			d.handleControllerMethod(restController, m, party, handlers, postHandlers)
+			d.handleControllerMethod(restController, m, party, handlers, postHandlers, mapping)
 		}
 	}
 	return
 }
 
-func (d *Dispatcher) appendMiddleware(mws []*injectableObject, atCtl []*annotation.Annotation, atCtlMth []*annotation.Annotation, handlers []iris.Handler) []iris.Handler {
+func (d *Dispatcher) appendMiddleware(mws []*injectableObject, atCtl []*annotation.Annotation, atCtlMth []*annotation.Annotation, handlers []iris.Handler) ([]iris.Handler, []string) {
+	var names []string
 	if len(mws) > 0 {
 		for _, mw := range mws {
 			atMw := annotation.FilterIn(mw.annotations, at.UseMiddleware{})
@@ -426,19 +486,27 @@ func (d *Dispatcher) appendMiddleware(mws []*injectableObject, atCtl []*annotati
 				useMiddleware := d.useMiddleware(atMw, atMwMth, atCtl, atCtlMth)
 				if useMiddleware {
 					handlers = append(handlers, mth.handler)
+					names = append(names, mw.name+"."+mth.method.Name)
 				}
 			}
 		}
 	}
-	return handlers
+	return handlers, names
 }
 
-func (d *Dispatcher) handleControllerMethod(restController *injectableObject, m *injectableMethod, party iris.Party, handlers []iris.Handler, postHandlers []iris.Handler) {
+// Mappings returns all request mappings that are registered by Dispatcher
+func (d *Dispatcher) Mappings() []*Mapping {
+	return d.mappings
+}
+
+func (d *Dispatcher) handleControllerMethod(restController *injectableObject, m *injectableMethod, party iris.Party, handlers []iris.Handler, postHandlers []iris.Handler, mapping *Mapping) {
 	// 3. create new handler for rest controller method
 	hdl := newHandler(d.configurableFactory, restController, m, at.HttpMethod{})
 
+	ra := &routeAnnotations{controller: restController.annotations, method: m.annotations}
 	before := Handler(func(c context.Context) {
 		c.SetAnnotations(hdl.annotations)
+		c.Values().Set(routeAnnotationKey, ra)
 		c.Next()
 	})
 
@@ -469,6 +537,15 @@ func (d *Dispatcher) handleControllerMethod(restController *injectableObject, m
 
 	var finalHandlers []iris.Handler
 	finalHandlers = append(finalHandlers, before)
+	// check at.Requires* annotations before calling the controller method
+	authz := newAuthorization(d.authorizer, restController.annotations, m.annotations)
+	if authz != nil {
+		if d.authorizer == nil {
+			log.Warnf("%v.%v is annotated with at.Requires*, but there is no web.Authorizer, the requests are regarded as unauthenticated",
+				restController.name, m.method.Name)
+		}
+		finalHandlers = append(finalHandlers, Handler(authz.Serve))
+	}
 	if len(handlers) != 0 {
 		finalHandlers = append(finalHandlers, handlers...)
 	}
@@ -477,11 +554,24 @@ func (d *Dispatcher) handleControllerMethod(restController *injectableObject, m
 		finalHandlers = append(finalHandlers, postHandlers...)
 	}
 
+	mapping.Handler = fmt.Sprintf("%s/%s.%s", restController.pkgPath, restController.name, m.method.Name)
+	mapping.Management = restController.management
 	if m.requestMapping.Method == Any {
-		party.Any(m.requestMapping.Value, finalHandlers...)
+		for _, route := range party.Any(m.requestMapping.Value, finalHandlers...) {
+			d.mappings = append(d.mappings, &Mapping{
+				Method:         route.Method,
+				Path:           route.Path,
+				Handler:        mapping.Handler,
+				Middleware:     mapping.Middleware,
+				PostMiddleware: mapping.PostMiddleware,
+				Management:     mapping.Management,
+			})
+		}
 	} else {
 		route := party.Handle(m.requestMapping.Method, m.requestMapping.Value, finalHandlers...)
-		route.MainHandlerName = fmt.Sprintf("%s/%s.%s", restController.pkgPath, restController.name, m.method.Name)
+		route.MainHandlerName = mapping.Handler
+		mapping.Method, mapping.Path = route.Method, route.Path
+		d.mappings = append(d.mappings, mapping)
 	}
 
 	// publish to subscriber
@@ -0,0 +1,386 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/model"
+	ctx "github.com/hidevopsio/iris/context"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// ContentTypeJSON is the content type of JSON
+	ContentTypeJSON = "application/json"
+	// ContentTypeXML is the content type of XML
+	ContentTypeXML = "application/xml"
+	// ContentTypeYAML is the content type of YAML
+	ContentTypeYAML = "application/yaml"
+	// ContentTypeCSV is the content type of CSV
+	ContentTypeCSV = "text/csv"
+	// ContentTypeText is the content type of plain text
+	ContentTypeText = "text/plain"
+
+	producesKey = "web.produces"
+)
+
+var (
+	// ErrCSVUnsupported the value can not be encoded in csv
+	ErrCSVUnsupported = errors.New("[web] csv supports struct, slice of struct or [][]string only")
+
+	// ErrTextUnsupported the value can not be encoded in plain text
+	ErrTextUnsupported = errors.New("[web] plain text supports string, []byte or fmt.Stringer only")
+
+	// ErrEncoderNotFound the content types of at.Produces have no registered encoder
+	ErrEncoderNotFound = errors.New("[web] no encoder is registered for the content types of at.Produces")
+)
+
+// Encoder encodes the response body in its content type
+type Encoder interface {
+	ContentType() string
+	Encode(v interface{}) ([]byte, error)
+}
+
+// Decoder decodes the request body in its content type
+type Decoder interface {
+	ContentType() string
+	Decode(data []byte, v interface{}) error
+}
+
+type codec struct {
+	contentType string
+	marshal     func(v interface{}) ([]byte, error)
+	unmarshal   func(data []byte, v interface{}) error
+}
+
+func (c *codec) ContentType() string {
+	return c.contentType
+}
+
+func (c *codec) Encode(v interface{}) ([]byte, error) {
+	return c.marshal(v)
+}
+
+func (c *codec) Decode(data []byte, v interface{}) error {
+	return c.unmarshal(data, v)
+}
+
+var (
+	encoders  []Encoder
+	decoders  = map[string]Decoder{}
+	encoderMu sync.RWMutex
+)
+
+func init() {
+	jsonCodec := &codec{ContentTypeJSON, json.Marshal, json.Unmarshal}
+	xmlCodec := &codec{ContentTypeXML, xml.Marshal, xml.Unmarshal}
+	yamlCodec := &codec{ContentTypeYAML, yaml.Marshal, yaml.Unmarshal}
+	RegisterEncoder(jsonCodec, xmlCodec, yamlCodec,
+		&codec{contentType: ContentTypeCSV, marshal: marshalCSV},
+		&codec{contentType: ContentTypeText, marshal: marshalText})
+	RegisterDecoder(jsonCodec, xmlCodec, yamlCodec,
+		&codec{"text/xml", xml.Marshal, xml.Unmarshal},
+		&codec{"application/x-yaml", yaml.Marshal, yaml.Unmarshal})
+}
+
+// RegisterEncoder registers response encoders, e.g. MessagePack, the encoder replaces the registered one of the same
+// content type, the encoders are negotiated by the accept header unless the method declares its ones in at.Produces,
+// it should be called before the controllers are registered
+func RegisterEncoder(encs ...Encoder) {
+	encoderMu.Lock()
+	defer encoderMu.Unlock()
+	for _, enc := range encs {
+		replaced := false
+		for i, e := range encoders {
+			if e.ContentType() == enc.ContentType() {
+				encoders[i] = enc
+				replaced = true
+			}
+		}
+		if !replaced {
+			encoders = append(encoders, enc)
+		}
+	}
+}
+
+// RegisterDecoder registers request body decoders, the decoder replaces the registered one of the same content type
+func RegisterDecoder(decs ...Decoder) {
+	encoderMu.Lock()
+	defer encoderMu.Unlock()
+	for _, dec := range decs {
+		decoders[dec.ContentType()] = dec
+	}
+}
+
+type mediaRange struct {
+	value string
+	q     float64
+}
+
+func parseAccept(accept string) (ranges []mediaRange) {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			q, _ = strconv.ParseFloat(v, 64)
+		}
+		if q > 0 {
+			ranges = append(ranges, mediaRange{value: mediaType, q: q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+	return
+}
+
+// negotiate returns the encoder of the response among the content types of at.Produces, or all registered encoders if
+// the method does not declare them, the one that is explicitly accepted with the highest quality is used, the wildcards,
+// e.g. */*, accept application/json if it is a candidate, or the first candidate. application/json is the fallback of
+// the method without at.Produces if none of the registered encoders is accepted
+func negotiate(accept string, produces []string) (encoder Encoder, ok bool) {
+	encoderMu.RLock()
+	defer encoderMu.RUnlock()
+
+	candidates := encoders
+	if len(produces) != 0 {
+		candidates = producedEncoders(produces)
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	// the default is application/json if it is produced, otherwise the first produced content type
+	preferred := candidates[0]
+	for _, enc := range candidates {
+		if enc.ContentType() == ContentTypeJSON {
+			preferred = enc
+		}
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return preferred, true
+	}
+	if encoder, ok = accepted(accept, candidates, preferred); !ok && len(produces) == 0 {
+		encoder, ok = findEncoder(ContentTypeJSON)
+	}
+	return
+}
+
+// accepted returns the candidate that is accepted with the highest quality, the wildcards prefer the preferred one
+func accepted(accept string, candidates []Encoder, preferred Encoder) (encoder Encoder, ok bool) {
+	for _, r := range parseAccept(accept) {
+		if !strings.HasSuffix(r.value, "/*") {
+			for _, enc := range candidates {
+				if r.value == enc.ContentType() {
+					return enc, true
+				}
+			}
+			continue
+		}
+		prefix := strings.TrimSuffix(r.value, "*")
+		if r.value == "*/*" || strings.HasPrefix(preferred.ContentType(), prefix) {
+			return preferred, true
+		}
+		for _, enc := range candidates {
+			if strings.HasPrefix(enc.ContentType(), prefix) {
+				return enc, true
+			}
+		}
+	}
+	return
+}
+
+// producedEncoders returns the registered encoders of the produced content types, encoderMu must be held by the caller
+func producedEncoders(produces []string) (candidates []Encoder) {
+	for _, mt := range produces {
+		if enc, found := findEncoder(strings.TrimSpace(mt)); found {
+			candidates = append(candidates, enc)
+		}
+	}
+	return
+}
+
+// checkProduces returns ErrEncoderNotFound if none of the produced content types has the registered encoder
+func checkProduces(produces []string) error {
+	encoderMu.RLock()
+	defer encoderMu.RUnlock()
+	if len(produces) != 0 && len(producedEncoders(produces)) == 0 {
+		return fmt.Errorf("%w: %v", ErrEncoderNotFound, strings.Join(produces, ", "))
+	}
+	return nil
+}
+
+// findEncoder returns the registered encoder of the content type, encoderMu must be held by the caller
+func findEncoder(contentType string) (encoder Encoder, ok bool) {
+	for _, enc := range encoders {
+		if enc.ContentType() == contentType {
+			return enc, true
+		}
+	}
+	return
+}
+
+func containsMediaType(mediaTypes []string, contentType string) bool {
+	for _, mt := range mediaTypes {
+		if strings.TrimSpace(mt) == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// writeResponse writes the response in the content type that is negotiated by the accept header and at.Produces
+func (h *handler) writeResponse(c context.Context, v interface{}) {
+	produces, _ := c.Values().Get(producesKey).([]string)
+	encoder, ok := negotiate(c.GetHeader("Accept"), produces)
+	if !ok {
+		responseError(c, NewError(http.StatusNotAcceptable, "not_acceptable",
+			fmt.Sprintf("supported content types: %v", strings.Join(produces, ", "))), h.problem)
+		return
+	}
+	body, err := encoder.Encode(v)
+	if err != nil && len(produces) == 0 && encoder.ContentType() != ContentTypeJSON {
+		// the value may not be supported by the accepted encoder, e.g. a map in xml, it is responded in json instead
+		encoder, _ = negotiate("", nil)
+		body, err = encoder.Encode(v)
+	}
+	if err != nil {
+		responseError(c, err, h.problem)
+		return
+	}
+	c.ContentType(encoder.ContentType())
+	_, _ = c.Write(body)
+}
+
+// requestBody decodes the request body that is limited by at.Consumes and validates it
+func (h *handler) requestBody(c context.Context, data interface{}) error {
+	return requestEx(c, data, func() error {
+		return readBody(c, data, h.consumes)
+	})
+}
+
+// readBody decodes the request body by the decoder of its content type, application/json is used if it is not set
+func readBody(c context.Context, data interface{}, consumes []string) error {
+	contentType := ContentTypeJSON
+	if header := c.GetHeader("Content-Type"); header != "" {
+		if mediaType, _, err := mime.ParseMediaType(header); err == nil {
+			contentType = mediaType
+		}
+	}
+
+	encoderMu.RLock()
+	decoder, ok := decoders[contentType]
+	encoderMu.RUnlock()
+	if !ok || (len(consumes) != 0 && !containsMediaType(consumes, contentType)) {
+		return NewError(http.StatusUnsupportedMediaType, "unsupported_media_type",
+			fmt.Sprintf("content type %v is not supported", contentType))
+	}
+	return c.UnmarshalBody(data, ctx.UnmarshalerFunc(decoder.Decode))
+}
+
+// marshalText encodes string, []byte or fmt.Stringer in plain text
+func marshalText(v interface{}) (data []byte, err error) {
+	switch t := v.(type) {
+	case string:
+		data = []byte(t)
+	case []byte:
+		data = t
+	case fmt.Stringer:
+		data = []byte(t.String())
+	default:
+		err = ErrTextUnsupported
+	}
+	return
+}
+
+// marshalCSV encodes struct, slice of struct or [][]string in csv, model.Response is encoded as its data,
+// the header of struct is the json name of its fields
+func marshalCSV(v interface{}) (data []byte, err error) {
+	if r, ok := v.(model.Response); ok {
+		v = r.GetData()
+	}
+	if records, ok := v.([][]string); ok {
+		return writeCSV(records)
+	}
+
+	val := reflect.Indirect(reflect.ValueOf(v))
+	var rows []reflect.Value
+	switch val.Kind() {
+	case reflect.Struct:
+		rows = append(rows, val)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			row := reflect.Indirect(val.Index(i))
+			if row.Kind() != reflect.Struct {
+				return nil, ErrCSVUnsupported
+			}
+			rows = append(rows, row)
+		}
+	default:
+		return nil, ErrCSVUnsupported
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	typ := rows[0].Type()
+	var header []string
+	var fields []int
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.PkgPath != "" || name == "-" || f.Anonymous {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+	records := [][]string{header}
+	for _, row := range rows {
+		record := make([]string, len(fields))
+		for i, idx := range fields {
+			record[i] = fmt.Sprint(row.Field(idx).Interface())
+		}
+		records = append(records, record)
+	}
+	return writeCSV(records)
+}
+
+func writeCSV(records [][]string) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	err := w.WriteAll(records)
+	return buf.Bytes(), err
+}
@@ -0,0 +1,211 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/app/web/server"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/stretchr/testify/assert"
+)
+
+// browserAccept is the default accept header of browsers
+const browserAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+type book struct {
+	Title  string `json:"title" xml:"title" yaml:"title"`
+	Author string `json:"author" xml:"author" yaml:"author"`
+	Pages  int    `json:"pages" xml:"pages" yaml:"pages"`
+}
+
+type bookRequest struct {
+	at.RequestBody
+
+	Title  string `json:"title" xml:"title" yaml:"title" validate:"required"`
+	Author string `json:"author" xml:"author" yaml:"author"`
+}
+
+type bookController struct {
+	at.RestController
+}
+
+func newBookController() *bookController {
+	return &bookController{}
+}
+
+func (c *bookController) Get() *book {
+	return &book{Title: "Go", Author: "Gopher", Pages: 300}
+}
+
+func (c *bookController) GetDetail(_ struct {
+	at.GetMapping `value:"/detail"`
+	at.Produces   `values:"application/xml,application/json,application/yaml"`
+}) *book {
+	return &book{Title: "Go", Author: "Gopher", Pages: 300}
+}
+
+func (c *bookController) GetTags(_ struct {
+	at.GetMapping `value:"/tags"`
+}) map[string]int {
+	return map[string]int{"go": 1}
+}
+
+func (c *bookController) GetList(_ struct {
+	at.GetMapping `value:"/list"`
+	at.Produces   `values:"application/json,text/csv"`
+}) []*book {
+	return []*book{
+		{Title: "Go", Author: "Gopher", Pages: 300},
+		{Title: "Rust", Author: "Ferris", Pages: 500},
+	}
+}
+
+func (c *bookController) Post(_ struct {
+	at.PostMapping `value:"/"`
+	at.Consumes    `values:"application/json,application/yaml"`
+}, request *bookRequest) string {
+	return request.Title + " by " + request.Author
+}
+
+func TestContentNegotiation(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	testApp := web.NewTestApp(newBookController).Run(t)
+
+	t.Run("should respond json by default", func(t *testing.T) {
+		testApp.Get("/book").Expect().Status(http.StatusOK).
+			ContentType(web.ContentTypeJSON).
+			JSON().Object().ValueEqual("title", "Go")
+	})
+
+	t.Run("should negotiate all registered encoders if the method does not declare at.Produces", func(t *testing.T) {
+		testApp.Get("/book").WithHeader("Accept", "application/xml").
+			Expect().Status(http.StatusOK).ContentType(web.ContentTypeXML)
+		testApp.Get("/book").WithHeader("Accept", "application/yaml, application/json;q=0.5").
+			Expect().Status(http.StatusOK).ContentType(web.ContentTypeYAML)
+		testApp.Get("/book").WithHeader("Accept", "*/*").
+			Expect().Status(http.StatusOK).ContentType(web.ContentTypeJSON)
+	})
+
+	t.Run("should fall back to json if the method does not declare at.Produces", func(t *testing.T) {
+		testApp.Get("/book").WithHeader("Accept", "text/html").
+			Expect().Status(http.StatusOK).ContentType(web.ContentTypeJSON)
+		testApp.Get("/book").WithHeader("Accept", "image/png").
+			Expect().Status(http.StatusOK).ContentType(web.ContentTypeJSON)
+		// a map is not supported by xml
+		testApp.Get("/book/tags").WithHeader("Accept", "application/xml").
+			Expect().Status(http.StatusOK).ContentType(web.ContentTypeJSON).
+			JSON().Object().ValueEqual("go", 1)
+	})
+
+	t.Run("should respond the explicitly accepted xml to the accept header of browsers", func(t *testing.T) {
+		testApp.Get("/book").WithHeader("Accept", browserAccept).
+			Expect().Status(http.StatusOK).ContentType(web.ContentTypeXML)
+	})
+
+	t.Run("should respond json to any content type if it is produced", func(t *testing.T) {
+		testApp.Get("/book/detail").WithHeader("Accept", "*/*").
+			Expect().Status(http.StatusOK).ContentType(web.ContentTypeJSON)
+		testApp.Get("/book/detail").WithHeader("Accept", "application/*").
+			Expect().Status(http.StatusOK).ContentType(web.ContentTypeJSON)
+		testApp.Get("/book/detail").
+			Expect().Status(http.StatusOK).ContentType(web.ContentTypeJSON)
+	})
+
+	t.Run("should respond xml", func(t *testing.T) {
+		testApp.Get("/book/detail").WithHeader("Accept", "application/xml").
+			Expect().Status(http.StatusOK).ContentType(web.ContentTypeXML).
+			Body().Equal("<book><title>Go</title><author>Gopher</author><pages>300</pages></book>")
+	})
+
+	t.Run("should respond yaml by the preferred accept", func(t *testing.T) {
+		testApp.Get("/book/detail").WithHeader("Accept", "application/xml;q=0.5, application/yaml").
+			Expect().Status(http.StatusOK).ContentType(web.ContentTypeYAML).
+			Body().Equal("title: Go\nauthor: Gopher\npages: 300\n")
+	})
+
+	t.Run("should respond csv", func(t *testing.T) {
+		testApp.Get("/book/list").WithHeader("Accept", "text/*").
+			Expect().Status(http.StatusOK).ContentType(web.ContentTypeCSV).
+			Body().Equal("title,author,pages\nGo,Gopher,300\nRust,Ferris,500\n")
+	})
+
+	t.Run("should respond 406 if the content type is not produced", func(t *testing.T) {
+		testApp.Get("/book/list").WithHeader("Accept", "application/xml").
+			Expect().Status(http.StatusNotAcceptable)
+		testApp.Get("/book/detail").WithHeader("Accept", "image/png").
+			Expect().Status(http.StatusNotAcceptable)
+	})
+
+	t.Run("should decode yaml request body", func(t *testing.T) {
+		testApp.Post("/book").WithHeader("Content-Type", "application/yaml").
+			WithBytes([]byte("title: Go\nauthor: Gopher\n")).
+			Expect().Status(http.StatusOK).Body().Equal("Go by Gopher")
+	})
+
+	t.Run("should respond 415 if the content type is not consumed", func(t *testing.T) {
+		testApp.Post("/book").WithHeader("Content-Type", "application/xml").
+			WithBytes([]byte("<book><title>Go</title></book>")).
+			Expect().Status(http.StatusUnsupportedMediaType)
+		testApp.Post("/book").WithHeader("Content-Type", "application/msgpack").
+			WithBytes([]byte{0x80}).
+			Expect().Status(http.StatusUnsupportedMediaType)
+	})
+
+	t.Run("should respond 400 if the request body is malformed", func(t *testing.T) {
+		testApp.Post("/book").WithHeader("Content-Type", "application/json").
+			WithBytes([]byte("{")).
+			Expect().Status(http.StatusBadRequest)
+	})
+
+	t.Run("should validate decoded request body", func(t *testing.T) {
+		testApp.Post("/book").WithHeader("Content-Type", "application/yaml").
+			WithBytes([]byte("author: Gopher\n")).
+			Expect().Status(http.StatusBadRequest)
+	})
+}
+
+type msgpackBookController struct {
+	at.RestController
+	at.RequestMapping `value:"/msgpack-book"`
+}
+
+func newMsgpackBookController() *msgpackBookController {
+	return &msgpackBookController{}
+}
+
+func (c *msgpackBookController) Get(_ struct {
+	at.GetMapping `value:"/"`
+	at.Produces   `values:"application/msgpack"`
+}) *book {
+	return &book{Title: "Go", Author: "Gopher", Pages: 300}
+}
+
+func TestProducesWithoutEncoder(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	testApp := web.NewApplication(newMsgpackBookController).
+		SetProperty(server.Port, 0).
+		SetProperty(app.BannerDisabled, true)
+	event, done := runApplication(t, testApp)
+	<-done
+	assert.Equal(t, (*app.ServerStartedEvent)(nil), event.ServerStartedEvent)
+}
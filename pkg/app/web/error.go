@@ -0,0 +1,206 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+)
+
+const (
+	// ErrorFormatProblem is the value of web.error.format that responds errors in RFC 7807 application/problem+json
+	ErrorFormatProblem = "problem"
+
+	problemContentType = "application/problem+json"
+	aboutBlank         = "about:blank"
+)
+
+// FieldError is the validation error of a request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error is the typed http error, it is responded with its status code instead of http.StatusInternalServerError
+type Error struct {
+	// Status is the http status code
+	Status int
+	// Code is the application specific error code, e.g. user_not_found
+	Code string
+	// Detail is the human readable explanation of the error
+	Detail string
+	// Type is the URI reference that identifies the problem type, about:blank by default
+	Type string
+	// Errors are the validation errors of request fields
+	Errors []FieldError
+
+	cause error
+}
+
+// NewError creates the typed http error
+func NewError(status int, code, detail string) *Error {
+	return &Error{
+		Status: status,
+		Code:   code,
+		Detail: detail,
+	}
+}
+
+// Error returns the detail of the error
+func (e *Error) Error() string {
+	if e.Detail != "" {
+		return e.Detail
+	}
+	return http.StatusText(e.Status)
+}
+
+// Unwrap returns the application error that is mapped to this error
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Problem is the RFC 7807 problem details
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+type errorMapping struct {
+	target      error
+	typ         reflect.Type
+	status      int
+	problemType string
+}
+
+var (
+	errorMappings []*errorMapping
+	errorMu       sync.RWMutex
+)
+
+// RegisterError maps the application error to the http status code and problem type.
+// target is either an error value that is matched by errors.Is,
+// or a typed nil pointer, e.g. (*NotFoundError)(nil), that is matched by its type
+func RegisterError(target error, status int, problemType string) {
+	m := &errorMapping{target: target, status: status, problemType: problemType}
+	if v := reflect.ValueOf(target); v.Kind() == reflect.Ptr && v.IsNil() {
+		m.typ = v.Type()
+	}
+	errorMu.Lock()
+	errorMappings = append(errorMappings, m)
+	errorMu.Unlock()
+}
+
+func init() {
+	// the downstream call is cancelled by the deadline of the request timeout
+	RegisterError(stdcontext.DeadlineExceeded, http.StatusGatewayTimeout, "")
+}
+
+func (m *errorMapping) match(err error) bool {
+	if m.typ == nil {
+		return errors.Is(err, m.target)
+	}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if reflect.TypeOf(e) == m.typ {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveError returns the typed http error of err, ok is false if err is neither an Error nor a registered error
+func resolveError(err error) (httpErr *Error, ok bool) {
+	if errors.As(err, &httpErr) {
+		ok = true
+		return
+	}
+
+	errorMu.RLock()
+	defer errorMu.RUnlock()
+	for _, m := range errorMappings {
+		if m.match(err) {
+			httpErr = &Error{Status: m.status, Detail: err.Error(), Type: m.problemType, cause: err}
+			ok = true
+			return
+		}
+	}
+	httpErr = &Error{Status: http.StatusInternalServerError, Detail: err.Error(), cause: err}
+	return
+}
+
+// newValidationError converts the validation errors of the request to Error with field errors
+func newValidationError(err error) *Error {
+	httpErr := &Error{Status: http.StatusBadRequest, Code: "validation_failed", Detail: err.Error(), cause: err}
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
+		for _, fe := range ve {
+			httpErr.Errors = append(httpErr.Errors, FieldError{
+				Field:   fe.Namespace(),
+				Tag:     fe.Tag(),
+				Message: fe.Error(),
+			})
+		}
+	}
+	return httpErr
+}
+
+// responseError responds the error with its status code, in application/problem+json if problem is true
+func responseError(ctx context.Context, err error, problem bool) {
+	httpErr, _ := resolveError(err)
+	if !problem {
+		ctx.ResponseError(httpErr.Error(), httpErr.Status)
+		return
+	}
+
+	body, e := json.Marshal(newProblem(ctx, httpErr))
+	if e != nil {
+		ctx.ResponseError(httpErr.Error(), httpErr.Status)
+		return
+	}
+	ctx.ContentType(problemContentType)
+	ctx.StatusCode(httpErr.Status)
+	_, _ = ctx.Write(body)
+}
+
+// newProblem returns the problem details of the error of the request
+func newProblem(ctx context.Context, httpErr *Error) *Problem {
+	p := &Problem{
+		Type:      httpErr.Type,
+		Title:     http.StatusText(httpErr.Status),
+		Status:    httpErr.Status,
+		Detail:    ctx.Translate(httpErr.Error()),
+		Instance:  ctx.Path(),
+		Code:      httpErr.Code,
+		RequestID: ctx.RequestID(),
+		Errors:    httpErr.Errors,
+	}
+	if p.Type == "" {
+		p.Type = aboutBlank
+	}
+	return p
+}
@@ -0,0 +1,147 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/model"
+	"github.com/hidevopsio/httpexpect"
+	"github.com/stretchr/testify/assert"
+)
+
+var errOrderConflict = errors.New("order already exists")
+
+type orderNotFoundError struct {
+	id string
+}
+
+func (e *orderNotFoundError) Error() string {
+	return fmt.Sprintf("order %v is not found", e.id)
+}
+
+func init() {
+	web.RegisterError(errOrderConflict, http.StatusConflict, "https://example.com/problems/conflict")
+	web.RegisterError((*orderNotFoundError)(nil), http.StatusNotFound, "https://example.com/problems/not-found")
+}
+
+type orderRequest struct {
+	at.RequestBody
+
+	Name     string `json:"name" validate:"required"`
+	Quantity int    `json:"quantity" validate:"gt=0"`
+}
+
+type orderController struct {
+	at.RestController
+}
+
+func newOrderController() *orderController {
+	return &orderController{}
+}
+
+func (c *orderController) GetByID(id string) (string, error) {
+	if id == "forbidden" {
+		return "", web.NewError(http.StatusForbidden, "order_forbidden", "order is not accessible")
+	}
+	return "", fmt.Errorf("get order: %w", &orderNotFoundError{id: id})
+}
+
+func (c *orderController) Post(request *orderRequest) (model.Response, error) {
+	return new(model.BaseResponse), fmt.Errorf("create order: %w", errOrderConflict)
+}
+
+func (c *orderController) Delete() error {
+	return errors.New("unexpected error")
+}
+
+func TestErrorResponse(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	testApp := web.NewTestApp(newOrderController).Run(t)
+
+	t.Run("should respond typed error with its status", func(t *testing.T) {
+		testApp.Get("/order/id/forbidden").Expect().Status(http.StatusForbidden).
+			JSON().Object().ValueEqual("code", http.StatusForbidden).ValueEqual("message", "order is not accessible")
+	})
+
+	t.Run("should respond registered error type with its status", func(t *testing.T) {
+		testApp.Get("/order/id/1").Expect().Status(http.StatusNotFound)
+	})
+
+	t.Run("should respond registered error value in the response with its status", func(t *testing.T) {
+		testApp.Post("/order").WithJSON(&orderRequest{Name: "book", Quantity: 1}).
+			Expect().Status(http.StatusConflict).
+			JSON().Object().ValueEqual("code", http.StatusConflict)
+	})
+
+	t.Run("should respond unknown error with http.StatusInternalServerError", func(t *testing.T) {
+		testApp.Delete("/order").Expect().Status(http.StatusInternalServerError)
+	})
+}
+
+func problemOf(t *testing.T, resp *httpexpect.Response) *httpexpect.Object {
+	resp.ContentType("application/problem+json")
+	problem := map[string]interface{}{}
+	assert.Equal(t, nil, json.Unmarshal([]byte(resp.Body().Raw()), &problem))
+	return httpexpect.NewObject(t, problem)
+}
+
+func TestProblemResponse(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	testApp := web.NewTestApp(newOrderController).
+		SetProperty(web.ErrorFormat, web.ErrorFormatProblem).
+		Run(t)
+
+	t.Run("should respond typed error in problem details", func(t *testing.T) {
+		problem := problemOf(t, testApp.Get("/order/id/forbidden").Expect().Status(http.StatusForbidden))
+		problem.ValueEqual("type", "about:blank")
+		problem.ValueEqual("title", "Forbidden")
+		problem.ValueEqual("status", http.StatusForbidden)
+		problem.ValueEqual("detail", "order is not accessible")
+		problem.ValueEqual("code", "order_forbidden")
+		problem.ValueEqual("instance", "/order/id/forbidden")
+	})
+
+	t.Run("should respond registered error with its problem type", func(t *testing.T) {
+		problemOf(t, testApp.Get("/order/id/1").Expect().Status(http.StatusNotFound)).
+			ValueEqual("type", "https://example.com/problems/not-found").
+			ValueEqual("detail", "get order: order 1 is not found")
+	})
+
+	t.Run("should respond problem details instead of the response", func(t *testing.T) {
+		problemOf(t, testApp.Post("/order").WithJSON(&orderRequest{Name: "book", Quantity: 1}).
+			Expect().Status(http.StatusConflict)).ValueEqual("type", "https://example.com/problems/conflict")
+	})
+
+	t.Run("should respond validation errors", func(t *testing.T) {
+		problem := problemOf(t, testApp.Post("/order").WithJSON(&orderRequest{}).
+			Expect().Status(http.StatusBadRequest))
+		problem.ValueEqual("code", "validation_failed")
+		errs := problem.Value("errors").Array()
+		errs.Length().Equal(2)
+		errs.Element(0).Object().ValueEqual("field", "orderRequest.Name").ValueEqual("tag", "required")
+		errs.Element(1).Object().ValueEqual("field", "orderRequest.Quantity").ValueEqual("tag", "gt")
+	})
+}
@@ -15,11 +15,15 @@
 package web
 
 import (
+	stdcontext "context"
 	"errors"
+	"io"
 	"net/http"
 	"reflect"
 	"strings"
+	"time"
 
+	"github.com/hidevopsio/hiboot/pkg/aop"
 	"github.com/hidevopsio/hiboot/pkg/app/web/context"
 	"github.com/hidevopsio/hiboot/pkg/at"
 	"github.com/hidevopsio/hiboot/pkg/factory"
@@ -29,7 +33,6 @@ import (
 	"github.com/hidevopsio/hiboot/pkg/utils/mapstruct"
 	"github.com/hidevopsio/hiboot/pkg/utils/reflector"
 	"github.com/hidevopsio/hiboot/pkg/utils/replacer"
-	"github.com/hidevopsio/hiboot/pkg/utils/str"
 )
 
 const (
@@ -43,18 +46,20 @@ var (
 )
 
 type request struct {
-	typeName     string
-	name         string
-	fullName     string
-	kind         reflect.Kind
-	genKind      reflect.Kind // e.g. convert int16 to int
-	typ          reflect.Type
-	iTyp         reflect.Type
-	obj          interface{}
-	iVal         reflect.Value
-	pathIdx      int
-	callback     func(ctx context.Context, data interface{}) error
-	isAnnotation bool
+	typeName string
+	name     string
+	fullName string
+	kind     reflect.Kind
+	genKind  reflect.Kind // e.g. convert int16 to int
+	typ      reflect.Type
+	iTyp     reflect.Type
+	obj      interface{}
+	iVal     reflect.Value
+	pathIdx  int
+	// isPathVariable is true if the parameter is bound to the path variable of its name
+	isPathVariable bool
+	callback       func(ctx context.Context, data interface{}) error
+	isAnnotation   bool
 }
 
 type response struct {
@@ -79,6 +84,7 @@ type handler struct {
 	requests        []request
 	responses       []response
 	lenOfPathParams int
+	pathConstraints map[string]*pathConstraint
 	factory         factory.ConfigurableFactory
 	runtimeInstance factory.InstanceContainer
 	contextName     string
@@ -87,6 +93,21 @@ type handler struct {
 	injectableObject *injectableObject
 	restMethod       *injectableMethod
 	annotations      interface{}
+	// problem is true if errors are responded in application/problem+json
+	problem bool
+	// produces and consumes are the content types of at.Produces and at.Consumes
+	produces []string
+	consumes []string
+	// heartbeat is the interval of heartbeats of server-sent events
+	heartbeat time.Duration
+	// maxMemory and uploadLimit are the limits of multipart forms
+	maxMemory   int64
+	uploadLimit uploadLimit
+	// timeout is the deadline of the controller method, writesContext is true if it takes the hiboot context.Context
+	timeout       time.Duration
+	writesContext bool
+	// interceptors are the ones that are bound to the annotations of the controller method
+	interceptors []aop.Interceptor
 }
 
 type requestSet struct {
@@ -96,15 +117,22 @@ type requestSet struct {
 
 var requestSets []requestSet
 
+var (
+	requestBodyName  = newRequestTypeName(new(at.RequestBody))
+	requestFormName  = newRequestTypeName(new(at.RequestForm))
+	pathVariableName = newRequestTypeName(new(at.PathVariable))
+)
+
 func newRequestTypeName(in interface{}) string {
 	return reflector.GetName(in)
 }
 
 func init() {
 	requestSets = []requestSet{
-		{newRequestTypeName(new(at.RequestForm)), RequestForm},
+		{requestFormName, RequestForm},
 		{newRequestTypeName(new(at.RequestParams)), RequestParams},
-		{newRequestTypeName(new(at.RequestBody)), RequestBody},
+		{requestBodyName, RequestBody},
+		{pathVariableName, nil},
 	}
 }
 
@@ -114,9 +142,24 @@ func newHandler(factory factory.ConfigurableFactory, injectableObject *injectabl
 		factory:          factory,
 		injectableObject: injectableObject,
 		restMethod:       restMethod,
+		maxMemory:        defaultMaxMemory,
+	}
+	if props, ok := factory.GetInstance(properties{}).(*properties); ok {
+		hdl.problem = props.Error.Format == ErrorFormatProblem
+		hdl.heartbeat = time.Duration(props.SSE.Heartbeat) * time.Second
+		hdl.maxMemory = props.Upload.MaxMemory * megabytes
+		hdl.uploadLimit = uploadLimit{
+			maxSize:        props.Upload.MaxFileSize * megabytes,
+			types:          props.Upload.AllowedTypes,
+			maxRequestSize: props.Upload.MaxRequestSize * megabytes,
+		}
 	}
 
 	hdl.parseMethod(injectableObject, restMethod, atType)
+	if _, ok := atType.(at.HttpMethod); ok {
+		hdl.timeout = hdl.parseTimeout()
+		hdl.interceptors = aop.Match(factory.GetInstances(at.Interceptor{}), hdl.object, *hdl.method)
+	}
 	return hdl
 }
 
@@ -216,11 +259,12 @@ func (h *handler) parseMethod(injectableObject *injectableObject, injectableMeth
 		}
 
 		// parse path variable
-		if pathIdx < lenOfPathParams {
+		if pathIdx < lenOfPathParams && isPathVariableType(typ) {
 			for idx, pv := range pps {
 				if pv == pp[pathIdx][0] {
 					h.requests[i].name = pp[pathIdx][1]
 					h.requests[i].pathIdx = idx
+					h.requests[i].isPathVariable = true
 					pathIdx = pathIdx + 1
 					break
 				}
@@ -233,12 +277,20 @@ func (h *handler) parseMethod(injectableObject *injectableObject, injectableMeth
 				//h.requests[i].obj = h.requests[i].iVal.Interface()
 				h.requests[i].iVal = reflect.MakeSlice(typ, 0, 0)
 				h.requests[i].typeName = "RequestBody"
-				h.requests[i].callback = RequestBody
+				h.requests[i].callback = h.requestBody
 			} else {
 				for _, tn := range requestSets {
 					if field, ok := iTyp.FieldByName(tn.name); ok && field.Anonymous {
 						h.requests[i].typeName = tn.name
 						h.requests[i].callback = tn.callback
+						switch tn.name {
+						case requestBodyName:
+							h.requests[i].callback = h.requestBody
+						case requestFormName:
+							h.requests[i].callback = h.requestForm
+						case pathVariableName:
+							h.requests[i].callback = h.requestPathVariable
+						}
 						break
 					}
 				}
@@ -247,6 +299,24 @@ func (h *handler) parseMethod(injectableObject *injectableObject, injectableMeth
 
 	}
 	h.lenOfPathParams = lenOfPathParams
+	for _, constraints := range []map[string]*pathConstraint{injectableObject.pathConstraints, injectableMethod.pathConstraints} {
+		for name, c := range constraints {
+			if h.pathConstraints == nil {
+				h.pathConstraints = make(map[string]*pathConstraint)
+			}
+			h.pathConstraints[name] = c
+		}
+	}
+
+	// content types for content negotiation
+	if h.annotations != nil {
+		if ann := annotation.GetAnnotation(h.annotations, at.Produces{}); ann != nil {
+			h.produces = ann.Field.Value.Interface().(at.Produces).AtValues
+		}
+		if ann := annotation.GetAnnotation(h.annotations, at.Consumes{}); ann != nil {
+			h.consumes = ann.Field.Value.Interface().(at.Consumes).AtValues
+		}
+	}
 
 	// parse response
 	h.responses = make([]response, h.numOut)
@@ -305,7 +375,7 @@ func (h *handler) responseData(ctx context.Context, numOut int, results []reflec
 						res.SetMessage(ctx.Translate(success))
 					}
 				} else {
-					h.setErrorResponseCode(ctx, res)
+					h.setErrorResponseCode(ctx, res, respErr)
 					// TODO: output error message directly? how about i18n
 					res.SetMessage(ctx.Translate(respErr.Error()))
 				}
@@ -328,13 +398,17 @@ func (h *handler) finalizeResponse(ctx context.Context) {
 			//log.Debugf("%v / %v", idx, size)
 			ctx.Values().Set("done", "true")
 			res := ctx.GetResponse(0)
-			if res != nil {
+			respErr, _ := ctx.GetResponse(1).(error)
+			// the error replaces the response body unless the response carries the error code and message itself
+			_, isResponseInfo := res.(model.ResponseInfo)
+			if respErr != nil && (h.problem || !isResponseInfo) {
+				responseError(ctx, respErr, h.problem)
+			} else if res != nil {
 				switch res.(type) {
 				case string:
 					ctx.ResponseString(res.(string))
 				case error:
-					respErr := res.(error)
-					ctx.ResponseError(respErr.Error(), http.StatusInternalServerError)
+					responseError(ctx, res.(error), h.problem)
 				case model.Response:
 					r := res.(model.Response)
 					if r.GetCode() == 0 {
@@ -344,7 +418,7 @@ func (h *handler) finalizeResponse(ctx context.Context) {
 						r.SetMessage(ctx.Translate(success))
 					}
 					ctx.StatusCode(r.GetCode())
-					_, _ = ctx.JSON(r)
+					h.writeResponse(ctx, r)
 				case model.ResponseInfo:
 					r := res.(model.ResponseInfo)
 					if r.GetCode() == 0 {
@@ -354,23 +428,24 @@ func (h *handler) finalizeResponse(ctx context.Context) {
 						r.SetMessage(ctx.Translate(success))
 					}
 					ctx.StatusCode(r.GetCode())
-					_, _ = ctx.JSON(r)
-				case map[string]interface{}:
-					_, _ = ctx.JSON(res)
+					h.writeResponse(ctx, r)
+				case *EventStream:
+					h.streamEvents(ctx, reflect.ValueOf(res))
+				case io.Reader:
+					h.streamBody(ctx, res.(io.Reader))
 				default:
-					_, _ = ctx.JSON(res)
-				}
-			} else {
-				e := ctx.GetResponse(1)
-				switch e.(type) {
-				case error:
-					err := e.(error)
-					ctx.ResponseError(err.Error(), http.StatusInternalServerError)
+					if v := reflect.ValueOf(res); isStream(v.Type()) {
+						h.streamEvents(ctx, v)
+					} else {
+						h.writeResponse(ctx, res)
+					}
 				}
 			}
 			// To clear current context and init for next request to prevent garbage response
 			ctx.InitResponses()
 		}
+		// the deadline is kept until the streamed response is finished
+		releaseTimeout(ctx)
 	}
 	return
 }
@@ -396,19 +471,19 @@ func (h *handler) responseWithError(ctx context.Context, numOut int, results []r
 				res.SetMessage(ctx.Translate(success))
 			}
 		} else {
-			h.setErrorResponseCode(ctx, res)
+			h.setErrorResponseCode(ctx, res, respErr)
 			// TODO: output error message directly? how about i18n
 			res.SetMessage(ctx.Translate(respErr.Error()))
-
-			// TODO: configurable status code in application.yml
 		}
 	}
 }
 
-func (h *handler) setErrorResponseCode(ctx context.Context, response model.ResponseInfo) {
+func (h *handler) setErrorResponseCode(ctx context.Context, response model.ResponseInfo, err error) {
 	if response.GetCode() == 0 {
 		prevStatusCode := ctx.GetStatusCode()
-		if prevStatusCode == http.StatusOK || prevStatusCode == 0 {
+		if httpErr, ok := resolveError(err); ok {
+			response.SetCode(httpErr.Status)
+		} else if prevStatusCode == http.StatusOK || prevStatusCode == 0 {
 			response.SetCode(http.StatusInternalServerError)
 		} else {
 			response.SetCode(prevStatusCode)
@@ -420,8 +495,6 @@ func (h *handler) call(ctx context.Context) {
 
 	var input interface{}
 	var reqErr error
-	var path string
-	var pvs []string
 	var runtimeInstance factory.InstanceContainer
 
 	// init responses
@@ -431,10 +504,6 @@ func (h *handler) call(ctx context.Context) {
 		ctx.InitResponses()
 	}
 
-	if h.lenOfPathParams != 0 {
-		path = ctx.Path()
-		pvs = strings.SplitN(path, "/", -1)
-	}
 	var results []reflect.Value
 	if len(h.dependencies) > 0 {
 		runtimeInstance, reqErr = h.factory.InjectScopedObjects(ctx, h.dependencies, nil)
@@ -447,13 +516,18 @@ func (h *handler) call(ctx context.Context) {
 		}
 	}
 	if reqErr == nil {
+		var deadline stdcontext.Context
+		if h.timeout > 0 {
+			deadline = withTimeout(ctx, h.timeout)
+		}
+
 		inputs := make([]reflect.Value, h.numIn)
 		if h.numIn != 0 {
 			inputs[0] = h.objVal
 		}
 
-		lenOfPathParams := h.lenOfPathParams
-		for i := 1; i < h.numIn; i++ {
+		reqErr = h.checkPathVariables(ctx)
+		for i := 1; i < h.numIn && reqErr == nil; i++ {
 			req := h.requests[i]
 			input = reflect.New(req.iTyp).Interface()
 
@@ -461,8 +535,8 @@ func (h *handler) call(ctx context.Context) {
 			//log.Debugf("%v, %v", i, h.requests[i].iVal.Type())
 			if req.kind == reflect.Slice {
 				var res reflect.Value
-				if lenOfPathParams != 0 {
-					res = h.decodePathVariable(&lenOfPathParams, pvs, req, req.kind)
+				if req.isPathVariable {
+					res, reqErr = h.decodePathVariable(ctx, req)
 				} else {
 					res, reqErr = h.decodeSlice(ctx, h.requests[i].iTyp, h.requests[i].iVal)
 				}
@@ -471,14 +545,14 @@ func (h *handler) call(ctx context.Context) {
 				_ = h.factory.InjectDefaultValue(input) // support default value injection for request body/params/form
 				reqErr = req.callback(ctx, input)
 				inputs[i] = reflect.ValueOf(input)
+			} else if req.kind == reflect.Interface && req.iTyp == stdContextType {
+				// the request context is cancelled once the client is disconnected or the timeout expires
+				inputs[i] = reflect.ValueOf(ctx.Request().Context())
 			} else if req.kind == reflect.Interface && model.Context == req.typeName {
 				input = ctx
 				inputs[i] = reflect.ValueOf(input)
-			} else if lenOfPathParams != 0 && !req.isAnnotation {
-				// allow inject other dependencies after number of lenOfPathParams
-				res := h.decodePathVariable(&lenOfPathParams, pvs, req, req.kind)
-
-				inputs[i] = res
+			} else if req.isPathVariable {
+				inputs[i], reqErr = h.decodePathVariable(ctx, req)
 			} else {
 				// inject instances
 				var inst interface{}
@@ -496,8 +570,19 @@ func (h *handler) call(ctx context.Context) {
 			}
 		}
 		if reqErr == nil {
+			// the response is finalized by the last handler, which may be a post middleware
+			if len(h.produces) != 0 {
+				ctx.Values().Set(producesKey, h.produces)
+			}
 			// call controller method
-			results = h.method.Func.Call(inputs)
+			results, reqErr = h.invoke(ctx, deadline, inputs)
+			if reqErr != nil {
+				// the problem response is always used for the timeout
+				responseError(ctx, reqErr, true)
+			}
+		} else {
+			// failed to read or validate the request
+			responseError(ctx, reqErr, h.problem)
 		}
 	}
 
@@ -509,17 +594,9 @@ func (h *handler) call(ctx context.Context) {
 	h.finalizeResponse(ctx)
 }
 
-func (h *handler) decodePathVariable(lenOfPathParams *int, pvs []string, req request, kind reflect.Kind) reflect.Value {
-	*lenOfPathParams = *lenOfPathParams - 1
-	strVal := pvs[req.pathIdx]
-	val := str.Convert(strVal, kind)
-	res := reflect.ValueOf(val)
-	return res
-}
-
 func (h *handler) decodeSlice(ctx context.Context, iTyp reflect.Type, input reflect.Value) (retVal reflect.Value, err error) {
 	var m []interface{}
-	err = ctx.ReadJSON(&m)
+	err = readBody(ctx, &m, h.consumes)
 	for _, v := range m {
 		item := reflect.New(iTyp).Interface()
 		// TODO: Known issue - time.Time is not decoded
@@ -46,6 +46,10 @@ func (f *fakeFactory) GetInstance(params ...interface{}) (retVal interface{}) {
 	return nil
 }
 
+func (f *fakeFactory) GetInstances(params ...interface{}) (retVal []*factory.MetaData) {
+	return nil
+}
+
 func TestParse(t *testing.T) {
 	restCtl := new(injectableObject)
 	restCtl.object = new(fooController)
@@ -92,4 +96,18 @@ func TestParse(t *testing.T) {
 		assert.Equal(t, "/", p)
 	})
 
+	t.Run("should parse the constraints of path variables", func(t *testing.T) {
+		p, constraints, err := parsePathConstraints("/foo/{id:int}/{code:regex([A-Z]{3})}/{name}")
+		assert.Equal(t, nil, err)
+		assert.Equal(t, "/foo/{id}/{code}/{name}", p)
+		assert.Equal(t, 2, len(constraints))
+		assert.Equal(t, true, constraints["code"].check("ABC"))
+		assert.Equal(t, false, constraints["code"].check("ABCD"))
+	})
+
+	t.Run("should report the unknown constraint of path variable", func(t *testing.T) {
+		_, _, err := parsePathConstraints("/foo/{id:integer}")
+		assert.NotEqual(t, nil, err)
+	})
+
 }
@@ -0,0 +1,253 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+)
+
+var (
+	uuidRegExp  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	alphaRegExp = regexp.MustCompile(`^[a-zA-Z]+$`)
+)
+
+// pathConstraint is the constraint of the path variable, e.g. {id:int}, {uuid:uuid} or {slug:regex(^[a-z-]+$)}
+type pathConstraint struct {
+	name  string
+	check func(value string) bool
+}
+
+func newPathConstraint(name string) (c *pathConstraint, err error) {
+	c = &pathConstraint{name: name}
+	switch name {
+	case "", "string":
+		return nil, nil
+	case "int", "long":
+		c.check = func(v string) bool { _, e := strconv.ParseInt(v, 10, 64); return e == nil }
+	case "uint":
+		c.check = func(v string) bool { _, e := strconv.ParseUint(v, 10, 64); return e == nil }
+	case "float":
+		c.check = func(v string) bool { _, e := strconv.ParseFloat(v, 64); return e == nil }
+	case "bool", "boolean":
+		c.check = func(v string) bool { _, e := strconv.ParseBool(v); return e == nil }
+	case "alpha", "alphabetical":
+		c.check = alphaRegExp.MatchString
+	case "uuid":
+		c.check = uuidRegExp.MatchString
+	default:
+		expr := ""
+		for _, fn := range []string{"regex(", "regexp("} {
+			if strings.HasPrefix(name, fn) && strings.HasSuffix(name, ")") {
+				expr = name[len(fn) : len(name)-1]
+			}
+		}
+		if expr == "" {
+			return nil, fmt.Errorf("[web] unknown path variable constraint %v", name)
+		}
+		var re *regexp.Regexp
+		// the whole value must match the expression
+		re, err = regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, err
+		}
+		c.name = "regex"
+		c.check = re.MatchString
+	}
+	return
+}
+
+// parsePathConstraints strips the constraints from the path variables of the path, e.g. /user/{id:int} to /user/{id},
+// the braces of the regular expressions are allowed, e.g. {code:regex([A-Z]{3})}
+func parsePathConstraints(p string) (cleaned string, constraints map[string]*pathConstraint, err error) {
+	var sb strings.Builder
+	for i := 0; i < len(p); i++ {
+		if p[i] != '{' {
+			sb.WriteByte(p[i])
+			continue
+		}
+		// find the closing brace of the path variable
+		depth, end := 0, -1
+		for j := i; j < len(p) && end < 0; j++ {
+			switch p[j] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					end = j
+				}
+			}
+		}
+		if end < 0 {
+			sb.WriteString(p[i:])
+			break
+		}
+		variable := p[i+1 : end]
+		name, constraint := variable, ""
+		if idx := strings.Index(variable, ":"); idx >= 0 {
+			name, constraint = variable[:idx], strings.TrimSpace(variable[idx+1:])
+		}
+		var c *pathConstraint
+		c, err = newPathConstraint(constraint)
+		if err != nil {
+			err = fmt.Errorf("%w in path %v", err, p)
+			return
+		}
+		if c != nil {
+			if constraints == nil {
+				constraints = make(map[string]*pathConstraint)
+			}
+			constraints[name] = c
+		}
+		sb.WriteString("{" + name + "}")
+		i = end
+	}
+	cleaned = sb.String()
+	return
+}
+
+// StripPathConstraints returns the path without the constraints of path variables, e.g. /user/{id} for /user/{id:int}
+func StripPathConstraints(p string) string {
+	cleaned, _, _ := parsePathConstraints(p)
+	return cleaned
+}
+
+func newPathVariableError(name, tag, value string) *Error {
+	detail := fmt.Sprintf("invalid path variable %v: %v", name, value)
+	return &Error{
+		Status: http.StatusBadRequest,
+		Code:   "invalid_path_variable",
+		Detail: detail,
+		Errors: []FieldError{{Field: name, Tag: tag, Message: detail}},
+	}
+}
+
+// checkPathVariables checks the path variables of current request against their constraints
+func (h *handler) checkPathVariables(ctx context.Context) error {
+	for name, c := range h.pathConstraints {
+		value := ctx.Params().Get(name)
+		if !c.check(value) {
+			return newPathVariableError(name, c.name, value)
+		}
+	}
+	return nil
+}
+
+// convertPathVariable converts the value of the path variable to typ, the comma separated value is converted to slice
+func convertPathVariable(value string, typ reflect.Type) (v reflect.Value, err error) {
+	v = reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(value, 10, typ.Bits()); err == nil {
+			v.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		if n, err = strconv.ParseUint(value, 10, typ.Bits()); err == nil {
+			v.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(value, typ.Bits()); err == nil {
+			v.SetFloat(f)
+		}
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(value); err == nil {
+			v.SetBool(b)
+		}
+	case reflect.Slice:
+		items := strings.Split(value, ",")
+		v = reflect.MakeSlice(typ, 0, len(items))
+		for _, item := range items {
+			var iv reflect.Value
+			if iv, err = convertPathVariable(item, typ.Elem()); err != nil {
+				return
+			}
+			v = reflect.Append(v, iv)
+		}
+	case reflect.Ptr:
+		var ev reflect.Value
+		if ev, err = convertPathVariable(value, typ.Elem()); err == nil {
+			v = reflect.New(typ.Elem())
+			v.Elem().Set(ev)
+		}
+	default:
+		err = fmt.Errorf("[web] unsupported path variable type %v", typ)
+	}
+	return
+}
+
+// decodePathVariable converts the named path variable of current request to the type of the method parameter
+func (h *handler) decodePathVariable(ctx context.Context, req request) (v reflect.Value, err error) {
+	value := ctx.Params().Get(req.name)
+	v, err = convertPathVariable(value, req.typ)
+	if err != nil {
+		err = newPathVariableError(req.name, req.typ.Kind().String(), value)
+	}
+	return
+}
+
+// isPathVariableType returns true if the parameter of type typ can be bound to the path variable
+func isPathVariableType(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice:
+		return isPathVariableType(typ.Elem())
+	case reflect.Struct, reflect.Interface, reflect.Map, reflect.Func, reflect.Chan:
+		return false
+	}
+	return true
+}
+
+// requestPathVariable binds the path variables to the fields of the struct that embeds at.PathVariable,
+// the field is matched by its json tag or its name case-insensitively
+func (h *handler) requestPathVariable(ctx context.Context, data interface{}) error {
+	return requestEx(ctx, data, func() error {
+		dv := reflect.Indirect(reflect.ValueOf(data))
+		dt := dv.Type()
+		for i := 0; i < dt.NumField(); i++ {
+			field := dt.Field(i)
+			if field.Anonymous || field.PkgPath != "" {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" {
+				name = field.Name
+			}
+			for _, pv := range h.pathVariable {
+				if strings.EqualFold(pv, name) {
+					value := ctx.Params().Get(pv)
+					v, err := convertPathVariable(value, field.Type)
+					if err != nil {
+						return newPathVariableError(pv, field.Type.Kind().String(), value)
+					}
+					dv.Field(i).Set(v)
+					break
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,105 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/at"
+)
+
+type articlePath struct {
+	at.PathVariable
+	Tenant string `json:"tenant"`
+	ID     int    `json:"id"`
+	Slug   string
+}
+
+// the path variables are bound to the method parameters in order, including the ones of the controller mapping
+type articleController struct {
+	at.RestController
+	at.RequestMapping `value:"/tenant/{tenant:alpha}/article"`
+}
+
+func newArticleController() *articleController {
+	return &articleController{}
+}
+
+func (c *articleController) Get(_ struct {
+	at.GetMapping `value:"/{id:int}"`
+}, tenant string, id int) int {
+	return id * 2
+}
+
+func (c *articleController) GetBySlug(_ struct {
+	at.GetMapping `value:"/{id:int}/{slug:regex([a-z]{2}-[a-z0-9-]+)}"`
+}, path *articlePath) string {
+	return path.Tenant + ":" + path.Slug
+}
+
+func (c *articleController) GetByUUID(_ struct {
+	at.GetMapping `value:"/uuid/{uuid:uuid}"`
+}, tenant, uuid string) string {
+	return uuid
+}
+
+func (c *articleController) GetByVersion(_ struct {
+	at.GetMapping `value:"/version/{version}"`
+}, tenant string, version uint8) uint8 {
+	return version
+}
+
+func TestPathVariable(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	testApp := web.NewTestApp(newArticleController).
+		SetProperty(app.ProfilesInclude, web.Profile).
+		SetProperty(web.ErrorFormat, web.ErrorFormatProblem).
+		Run(t)
+
+	t.Run("should convert the path variable", func(t *testing.T) {
+		testApp.Get("/tenant/acme/article/21").Expect().Status(http.StatusOK).Body().Equal("42")
+	})
+
+	t.Run("should respond 400 with the name of the path variable that violates its constraint", func(t *testing.T) {
+		p := problemOf(t, testApp.Get("/tenant/acme/article/abc").Expect().Status(http.StatusBadRequest))
+		p.Value("code").Equal("invalid_path_variable")
+		p.Value("errors").Array().Element(0).Object().ValueEqual("field", "id").ValueEqual("tag", "int")
+
+		p = problemOf(t, testApp.Get("/tenant/123/article/1").Expect().Status(http.StatusBadRequest))
+		p.Value("errors").Array().Element(0).Object().ValueEqual("field", "tenant").ValueEqual("tag", "alpha")
+	})
+
+	t.Run("should respond 400 if the path variable overflows its type", func(t *testing.T) {
+		p := problemOf(t, testApp.Get("/tenant/acme/article/version/256").Expect().Status(http.StatusBadRequest))
+		p.Value("errors").Array().Element(0).Object().ValueEqual("field", "version")
+		testApp.Get("/tenant/acme/article/version/255").Expect().Status(http.StatusOK).Body().Equal("255")
+	})
+
+	t.Run("should match the regular expression with braces", func(t *testing.T) {
+		testApp.Get("/tenant/acme/article/1/en-hello-world").Expect().Status(http.StatusOK).Body().Equal("acme:en-hello-world")
+		testApp.Get("/tenant/acme/article/1/english-hello").Expect().Status(http.StatusBadRequest)
+	})
+
+	t.Run("should match the uuid", func(t *testing.T) {
+		testApp.Get("/tenant/acme/article/uuid/0b5b5d4e-6c2f-4f3a-9d7e-2b1c8a9e0f11").Expect().Status(http.StatusOK)
+		problemOf(t, testApp.Get("/tenant/acme/article/uuid/0b5b5d4e").Expect().Status(http.StatusBadRequest)).
+			Value("errors").Array().Element(0).Object().ValueEqual("field", "uuid").ValueEqual("tag", "uuid")
+	})
+}
@@ -16,6 +16,18 @@ const (
 	ResourcePath = "web.view.resourcePath"
 	// Extension is the property for setting extension
 	Extension = "web.view.extension"
+	// ErrorFormat is the property for setting the response format of errors
+	ErrorFormat = "web.error.format"
+	// SSEHeartbeat is the property for setting the seconds between heartbeats of server-sent events
+	SSEHeartbeat = "web.sse.heartbeat"
+	// UploadMaxMemory is the property for setting the megabytes of multipart form that are stored in memory
+	UploadMaxMemory = "web.upload.max_memory"
+	// UploadMaxFileSize is the property for setting the max megabytes of each uploaded file
+	UploadMaxFileSize = "web.upload.max_file_size"
+	// UploadAllowedTypes is the property for setting the allowed content types of uploaded files
+	UploadAllowedTypes = "web.upload.allowed_types"
+	// UploadMaxRequestSize is the property for setting the max megabytes of the multipart request body
+	UploadMaxRequestSize = "web.upload.max_request_size"
 )
 
 type view struct {
@@ -31,14 +43,61 @@ type view struct {
 	Extension string `json:"extension" default:".html"`
 }
 
+type errorProperties struct {
+	// Format is the response format of errors, the default response or problem for RFC 7807 application/problem+json
+	Format string `json:"format" default:"default"`
+}
+
+type sseProperties struct {
+	// Heartbeat is the seconds between heartbeats that keep idle event streams alive, 0 disables heartbeats
+	Heartbeat int64 `json:"heartbeat" default:"15"`
+}
+
+type uploadProperties struct {
+	// MaxMemory is the megabytes of multipart form that are stored in memory, the rest is stored in temporary files
+	MaxMemory int64 `json:"max_memory" default:"32"`
+	// MaxFileSize is the max megabytes of each uploaded file, 0 means unlimited
+	MaxFileSize int64 `json:"max_file_size"`
+	// AllowedTypes are the allowed content types of uploaded files, e.g. image/png or image/*, empty means any
+	AllowedTypes []string `json:"allowed_types"`
+	// MaxRequestSize is the max megabytes of the multipart request body, 0 means it is derived from max_memory plus
+	// the max size of each file field times its max files, the body is unlimited if the max size of any file field
+	// is unlimited or a slice of files has no max_files in its file tag
+	MaxRequestSize int64 `json:"max_request_size"`
+}
+
+// managementServer is served with TLS as well if server.tls_cert and server.tls_key are set
+type managementServer struct {
+	// Port is the port of management endpoints, they are served on server.port if it is empty or the same as server.port
+	Port string `json:"port"`
+	// Address is the network address that the management server binds to
+	Address string `json:"address"`
+	// ContextPath is the context path of management endpoints
+	ContextPath string `json:"context_path" default:"/"`
+}
+
+type managementProperties struct {
+	at.ConfigurationProperties `value:"management"`
+	at.AutoWired
+
+	// Server is the properties for setting the management server
+	Server managementServer `json:"server"`
+}
+
 type properties struct {
 	at.ConfigurationProperties `value:"web"`
 	at.AutoWired
 
 	// View is the properties for setting web view
 	View view `json:"view"`
+	// Error is the properties for setting error responses
+	Error errorProperties `json:"error"`
+	// SSE is the properties for setting server-sent events
+	SSE sseProperties `json:"sse"`
+	// Upload is the properties for setting the limits of uploaded files
+	Upload uploadProperties `json:"upload"`
 }
 
 func init() {
-	app.Register(new(properties))
+	app.Register(new(properties), new(managementProperties))
 }
@@ -0,0 +1,47 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/system"
+)
+
+// newServer creates the http server with the timeouts, header limit and protocols of server.* properties
+func newServer(conf *system.Server, addr string, handler http.Handler) (server *http.Server) {
+	server = &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       seconds(conf.ReadTimeout),
+		ReadHeaderTimeout: seconds(conf.ReadHeaderTimeout),
+		WriteTimeout:      seconds(conf.WriteTimeout),
+		IdleTimeout:       seconds(conf.IdleTimeout),
+		MaxHeaderBytes:    conf.MaxHeaderBytes,
+	}
+	server.SetKeepAlivesEnabled(conf.KeepAlive)
+
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(conf.Http2)
+	protocols.SetUnencryptedHTTP2(conf.H2c)
+	server.Protocols = protocols
+	return
+}
+
+func seconds(s int64) time.Duration {
+	return time.Duration(s) * time.Second
+}
@@ -12,4 +12,49 @@ const (
 
 	// Schemes
 	Schemes = "server.schemes"
+
+	// ShutdownTimeout
+	ShutdownTimeout = "server.shutdown_timeout"
+
+	// ReadTimeout
+	ReadTimeout = "server.read_timeout"
+
+	// ReadHeaderTimeout
+	ReadHeaderTimeout = "server.read_header_timeout"
+
+	// WriteTimeout
+	WriteTimeout = "server.write_timeout"
+
+	// RequestTimeout
+	RequestTimeout = "server.request_timeout"
+
+	// IdleTimeout
+	IdleTimeout = "server.idle_timeout"
+
+	// MaxHeaderBytes
+	MaxHeaderBytes = "server.max_header_bytes"
+
+	// KeepAlive
+	KeepAlive = "server.keep_alive"
+
+	// Http2
+	Http2 = "server.http2"
+
+	// H2c
+	H2c = "server.h2c"
+
+	// TlsCert is the certificate file of https, it is used by the management server as well
+	TlsCert = "server.tls_cert"
+
+	// TlsKey is the private key file of https
+	TlsKey = "server.tls_key"
+
+	// ManagementPort is the port of management endpoints
+	ManagementPort = "management.server.port"
+
+	// ManagementAddress is the network address that the management server binds to
+	ManagementAddress = "management.server.address"
+
+	// ManagementContextPath is the context path of management endpoints
+	ManagementContextPath = "management.server.context_path"
 )
@@ -0,0 +1,78 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/system"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewServer(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	})
+
+	t.Run("should apply timeouts and header limit", func(t *testing.T) {
+		conf := &system.Server{
+			ReadTimeout:       60,
+			ReadHeaderTimeout: 10,
+			WriteTimeout:      30,
+			IdleTimeout:       120,
+			MaxHeaderBytes:    4096,
+			KeepAlive:         true,
+			Http2:             true,
+		}
+		server := newServer(conf, ":8080", handler)
+		assert.Equal(t, ":8080", server.Addr)
+		assert.Equal(t, time.Minute, server.ReadTimeout)
+		assert.Equal(t, 10*time.Second, server.ReadHeaderTimeout)
+		assert.Equal(t, 30*time.Second, server.WriteTimeout)
+		assert.Equal(t, 2*time.Minute, server.IdleTimeout)
+		assert.Equal(t, 4096, server.MaxHeaderBytes)
+		assert.Equal(t, true, server.Protocols.HTTP1())
+		assert.Equal(t, true, server.Protocols.HTTP2())
+		assert.Equal(t, false, server.Protocols.UnencryptedHTTP2())
+	})
+
+	for _, h2c := range []bool{true, false} {
+		name := "should serve h2c"
+		if !h2c {
+			name = "should not serve h2c if it is disabled"
+		}
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewUnstartedServer(handler)
+			ts.Config = newServer(&system.Server{KeepAlive: true, H2c: h2c}, "", handler)
+			ts.Start()
+			defer ts.Close()
+
+			protocols := new(http.Protocols)
+			protocols.SetUnencryptedHTTP2(true)
+			client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+			resp, err := client.Get(ts.URL)
+			if h2c {
+				assert.Equal(t, nil, err)
+				assert.Equal(t, 2, resp.ProtoMajor)
+				_ = resp.Body.Close()
+			} else {
+				assert.NotEqual(t, nil, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"bytes"
+	stdcontext "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+const (
+	// ContentTypeEventStream is the content type of server-sent events
+	ContentTypeEventStream = "text/event-stream"
+	// ContentTypeOctetStream is the default content type of streamed body
+	ContentTypeOctetStream = "application/octet-stream"
+)
+
+var (
+	// ErrStreamClosed the event stream is closed or the client is disconnected
+	ErrStreamClosed = errors.New("[web] event stream is closed")
+
+	stdContextType = reflect.TypeOf((*stdcontext.Context)(nil)).Elem()
+)
+
+// Event is the server-sent event, Data is written as is if it is a string or []byte, otherwise it is encoded in json
+type Event struct {
+	ID    string
+	Event string
+	Retry time.Duration
+	Data  interface{}
+}
+
+// EventStream is the stream of server-sent events that is returned by a controller method
+//
+//	func (c *fooController) GetEvents() *web.EventStream {
+//		stream := web.NewEventStream()
+//		go func() {
+//			defer stream.Close()
+//			for i := 0; ; i++ {
+//				if err := stream.Send(&web.Event{ID: strconv.Itoa(i), Data: i}); err != nil {
+//					return
+//				}
+//				time.Sleep(time.Second)
+//			}
+//		}()
+//		return stream
+//	}
+type EventStream struct {
+	events    chan *Event
+	done      chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+	doneOnce  sync.Once
+}
+
+// NewEventStream creates the stream of server-sent events
+func NewEventStream() *EventStream {
+	return &EventStream{
+		events: make(chan *Event),
+		done:   make(chan struct{}),
+		closed: make(chan struct{}),
+	}
+}
+
+// Send sends the event to the client, it returns ErrStreamClosed if the stream is closed or the client is disconnected
+func (s *EventStream) Send(event *Event) error {
+	select {
+	case <-s.closed:
+		return ErrStreamClosed
+	case <-s.done:
+		return ErrStreamClosed
+	default:
+	}
+	select {
+	case s.events <- event:
+		return nil
+	case <-s.closed:
+		return ErrStreamClosed
+	case <-s.done:
+		return ErrStreamClosed
+	}
+}
+
+// Close ends the stream
+func (s *EventStream) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+	})
+}
+
+// Done returns the channel that is closed once the client is disconnected
+func (s *EventStream) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *EventStream) disconnect() {
+	s.doneOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// Download is the streamed body that is downloaded as an attachment
+type Download struct {
+	io.Reader
+	// Filename is the filename of content-disposition
+	Filename string
+	// ContentType is the content type of the body, application/octet-stream by default
+	ContentType string
+}
+
+// NewDownload creates the streamed body that is downloaded as filename
+func NewDownload(r io.Reader, filename string) *Download {
+	return &Download{Reader: r, Filename: filename}
+}
+
+// isStream returns true if the controller method returns <-chan T, iter.Seq[T] or *EventStream
+func isStream(typ reflect.Type) bool {
+	if typ == reflect.TypeOf((*EventStream)(nil)) {
+		return true
+	}
+	if typ.Kind() == reflect.Chan {
+		return typ.ChanDir()&reflect.RecvDir != 0
+	}
+	return isSeq(typ)
+}
+
+// isSeq returns true if typ is iter.Seq[T], i.e. func(yield func(T) bool)
+func isSeq(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Func || typ.NumIn() != 1 || typ.NumOut() != 0 {
+		return false
+	}
+	yield := typ.In(0)
+	return yield.Kind() == reflect.Func && yield.NumIn() == 1 && yield.NumOut() == 1 && yield.Out(0).Kind() == reflect.Bool
+}
+
+// events returns the channel of the stream, the producer of iter.Seq is stopped once done is closed
+func events(stream reflect.Value, done <-chan struct{}) reflect.Value {
+	if es, ok := stream.Interface().(*EventStream); ok {
+		ch := make(chan *Event)
+		go func() {
+			defer close(ch)
+			for {
+				select {
+				case e := <-es.events:
+					select {
+					case ch <- e:
+					case <-done:
+						return
+					}
+				case <-es.closed:
+					return
+				case <-done:
+					es.disconnect()
+					return
+				}
+			}
+		}()
+		return reflect.ValueOf((<-chan *Event)(ch))
+	}
+	if stream.Kind() == reflect.Chan {
+		return stream
+	}
+
+	elemTyp := stream.Type().In(0).In(0)
+	ch := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, elemTyp), 0)
+	yield := reflect.MakeFunc(stream.Type().In(0), func(args []reflect.Value) []reflect.Value {
+		chosen, _, _ := reflect.Select([]reflect.SelectCase{
+			{Dir: reflect.SelectSend, Chan: ch, Send: args[0]},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(done)},
+		})
+		return []reflect.Value{reflect.ValueOf(chosen == 0)}
+	})
+	go func() {
+		defer ch.Close()
+		stream.Call([]reflect.Value{yield})
+	}()
+	return ch
+}
+
+// clearDeadlines clears the read and write deadlines of the connection as the stream lasts longer than
+// server.read_timeout and server.write_timeout, net/http cancels the request context once the read deadline is exceeded
+func clearDeadlines(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+}
+
+// streamEvents writes server-sent events until the stream is ended or the client is disconnected
+func (h *handler) streamEvents(ctx context.Context, stream reflect.Value) {
+	w := ctx.ResponseWriter()
+	clearDeadlines(w.Naive())
+
+	header := w.Header()
+	header.Set("Content-Type", ContentTypeEventStream)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+
+	done := ctx.Request().Context().Done()
+	stop := make(chan struct{})
+	defer close(stop)
+	ch := events(stream, stop)
+
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: ch},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(done)},
+	}
+	if h.heartbeat > 0 {
+		ticker := time.NewTicker(h.heartbeat)
+		defer ticker.Stop()
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ticker.C)})
+	}
+
+	buf := new(bytes.Buffer)
+	for {
+		chosen, val, ok := reflect.Select(cases)
+		buf.Reset()
+		switch chosen {
+		case 0:
+			if !ok {
+				return
+			}
+			if err := encodeEvent(buf, val.Interface()); err != nil {
+				log.Warnf("[web] failed to encode event: %v", err)
+				continue
+			}
+		case 1:
+			if es, isEventStream := stream.Interface().(*EventStream); isEventStream {
+				es.disconnect()
+			}
+			return
+		default:
+			buf.WriteString(": heartbeat\n\n")
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return
+		}
+		w.Flush()
+	}
+}
+
+// encodeEvent encodes the event in text/event-stream format
+func encodeEvent(buf *bytes.Buffer, v interface{}) (err error) {
+	var event *Event
+	switch e := v.(type) {
+	case *Event:
+		event = e
+	case Event:
+		event = &e
+	default:
+		event = &Event{Data: v}
+	}
+	if event == nil {
+		return
+	}
+
+	var data []byte
+	switch d := event.Data.(type) {
+	case string:
+		data = []byte(d)
+	case []byte:
+		data = d
+	default:
+		data, err = json.Marshal(d)
+		if err != nil {
+			return
+		}
+	}
+
+	if event.ID != "" {
+		fmt.Fprintf(buf, "id: %s\n", event.ID)
+	}
+	if event.Event != "" {
+		fmt.Fprintf(buf, "event: %s\n", event.Event)
+	}
+	if event.Retry > 0 {
+		fmt.Fprintf(buf, "retry: %d\n", event.Retry.Milliseconds())
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fmt.Fprintf(buf, "data: %s\n", line)
+	}
+	buf.WriteString("\n")
+	return
+}
+
+// streamBody writes the reader in chunks, it is downloaded as an attachment if it is a Download with filename
+func (h *handler) streamBody(ctx context.Context, r io.Reader) {
+	if c, ok := r.(io.Closer); ok {
+		defer c.Close()
+	}
+	w := ctx.ResponseWriter()
+	clearDeadlines(w.Naive())
+
+	contentType := ContentTypeOctetStream
+	if d, ok := r.(*Download); ok {
+		if d.ContentType != "" {
+			contentType = d.ContentType
+		}
+		if d.Filename != "" {
+			w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": d.Filename}))
+		}
+		if c, ok := d.Reader.(io.Closer); ok {
+			defer c.Close()
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, e := w.Write(buf[:n]); e != nil {
+				return
+			}
+			w.Flush()
+		}
+		if err != nil {
+			if err != io.EOF {
+				log.Warnf("[web] failed to stream body: %v", err)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web_test
+
+import (
+	"bufio"
+	stdcontext "context"
+	"iter"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/app/web/server"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/stretchr/testify/assert"
+)
+
+type eventController struct {
+	at.RestController
+
+	cancelled chan struct{}
+}
+
+func newEventController() *eventController {
+	return &eventController{cancelled: make(chan struct{})}
+}
+
+func (c *eventController) GetChan() <-chan *web.Event {
+	ch := make(chan *web.Event)
+	go func() {
+		defer close(ch)
+		ch <- &web.Event{ID: "1", Event: "greeting", Data: "hello"}
+		ch <- &web.Event{ID: "2", Event: "greeting", Data: "multi\nline"}
+	}()
+	return ch
+}
+
+func (c *eventController) GetSeq() iter.Seq[map[string]int] {
+	return func(yield func(map[string]int) bool) {
+		for i := 1; i <= 3; i++ {
+			if !yield(map[string]int{"n": i}) {
+				return
+			}
+		}
+	}
+}
+
+func (c *eventController) GetStream() *web.EventStream {
+	stream := web.NewEventStream()
+	go func() {
+		defer stream.Close()
+		_ = stream.Send(&web.Event{ID: "1", Retry: 3 * time.Second, Data: "first"})
+		time.Sleep(1500 * time.Millisecond)
+		_ = stream.Send(&web.Event{ID: "2", Data: "second"})
+	}()
+	return stream
+}
+
+func (c *eventController) GetDownload() *web.Download {
+	return web.NewDownload(strings.NewReader("name,age\njohn,18\n"), "users.csv")
+}
+
+func (c *eventController) GetTicks(ctx stdcontext.Context) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 0; ; i++ {
+			select {
+			case ch <- i:
+				time.Sleep(10 * time.Millisecond)
+			case <-ctx.Done():
+				close(c.cancelled)
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func TestStream(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	testApp := web.NewTestApp(newEventController).
+		SetProperty(web.SSEHeartbeat, 1).
+		Run(t)
+
+	t.Run("should stream events from channel", func(t *testing.T) {
+		testApp.Get("/event/chan").Expect().Status(http.StatusOK).
+			ContentType(web.ContentTypeEventStream, "").
+			Body().Equal("id: 1\nevent: greeting\ndata: hello\n\nid: 2\nevent: greeting\ndata: multi\ndata: line\n\n")
+	})
+
+	t.Run("should stream events from iter.Seq", func(t *testing.T) {
+		testApp.Get("/event/seq").Expect().Status(http.StatusOK).
+			Body().Equal("data: {\"n\":1}\n\ndata: {\"n\":2}\n\ndata: {\"n\":3}\n\n")
+	})
+
+	t.Run("should stream events with heartbeats from event stream", func(t *testing.T) {
+		testApp.Get("/event/stream").Expect().Status(http.StatusOK).
+			Body().Equal("id: 1\nretry: 3000\ndata: first\n\n: heartbeat\n\nid: 2\ndata: second\n\n")
+	})
+
+	t.Run("should download the streamed body", func(t *testing.T) {
+		resp := testApp.Get("/event/download").Expect().Status(http.StatusOK)
+		resp.Header("Content-Disposition").Equal("attachment; filename=users.csv")
+		resp.Body().Equal("name,age\njohn,18\n")
+	})
+}
+
+func TestStreamClientDisconnect(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	ctrl := newEventController()
+	testApp := web.NewApplication(func() *eventController { return ctrl }).
+		SetProperty(server.Port, 0).
+		SetProperty(server.ShutdownTimeout, 1).
+		SetProperty(app.BannerDisabled, true)
+	event, done := runApplication(t, testApp)
+	defer func() {
+		_ = testApp.Shutdown()
+		<-done
+	}()
+
+	ctx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+event.Address+"/event/ticks", nil)
+	resp, err := http.DefaultClient.Do(req)
+	assert.Equal(t, nil, err)
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "data: 0\n", line)
+
+	cancel()
+	_ = resp.Body.Close()
+	select {
+	case <-ctrl.cancelled:
+	case <-time.After(3 * time.Second):
+		t.Error("the context of the controller method is not cancelled")
+	}
+}
+
+func TestStreamLongerThanReadTimeout(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	testApp := web.NewApplication(newEventController).
+		SetProperty(server.Port, 0).
+		SetProperty(server.ReadTimeout, 1).
+		SetProperty(server.ShutdownTimeout, 1).
+		SetProperty(app.BannerDisabled, true)
+	event, done := runApplication(t, testApp)
+	defer func() {
+		_ = testApp.Shutdown()
+		<-done
+	}()
+
+	ctx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+event.Address+"/event/ticks", nil)
+	resp, err := http.DefaultClient.Do(req)
+	assert.Equal(t, nil, err)
+	defer resp.Body.Close()
+
+	// the stream should not be ended once server.read_timeout is exceeded
+	r := bufio.NewReader(resp.Body)
+	deadline := time.Now().Add(1500 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		_, err = r.ReadString('\n')
+		if err != nil {
+			break
+		}
+	}
+	assert.Equal(t, nil, err)
+}
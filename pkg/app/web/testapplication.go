@@ -36,12 +36,14 @@ type TestApplication interface {
 	Delete(path string, pathargs ...interface{}) *httpexpect.Request
 	Patch(path string, pathargs ...interface{}) *httpexpect.Request
 	Options(path string, pathargs ...interface{}) *httpexpect.Request
+	ManagementRequest(method, path string, pathargs ...interface{}) *httpexpect.Request
 }
 
 // TestApplication the test web application for unit test only
 type testApplication struct {
 	application
-	expect *httpexpect.Expect
+	expect           *httpexpect.Expect
+	managementExpect *httpexpect.Expect
 }
 
 // RunTestApplication returns the new test application
@@ -79,6 +81,10 @@ func (a *testApplication) Run(t *testing.T) TestApplication {
 		log.Error(err)
 	}
 	a.expect = httptest.New(t, a.webApp.Application)
+	a.managementExpect = a.expect
+	if a.management != nil {
+		a.managementExpect = httptest.New(t, a.management.Application)
+	}
 	// Reset log level as httptest.New disabled log as default
 	log.SetLevel(a.SystemConfig().Logging.Level)
 	return a
@@ -118,3 +124,8 @@ func (a *testApplication) Delete(path string, pathargs ...interface{}) *httpexpe
 func (a *testApplication) Options(path string, pathargs ...interface{}) *httpexpect.Request {
 	return a.expect.Request(http.MethodOptions, path, pathargs...)
 }
+
+// ManagementRequest request management endpoints, they are served by the application itself if management.server.port is not set
+func (a *testApplication) ManagementRequest(method, path string, pathargs ...interface{}) *httpexpect.Request {
+	return a.managementExpect.Request(method, path, pathargs...)
+}
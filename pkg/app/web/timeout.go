@@ -0,0 +1,233 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/aop"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/inject/annotation"
+	"github.com/hidevopsio/hiboot/pkg/model"
+	"github.com/hidevopsio/hiboot/pkg/system"
+	ictx "github.com/hidevopsio/iris/context"
+)
+
+// gracePeriod is the time that the method is waited for after the deadline,
+// so that the error of the method that honors the deadline, e.g. context.DeadlineExceeded, is responded
+const gracePeriod = 100 * time.Millisecond
+
+// cancelKey is the key of the func that releases the deadline
+const cancelKey = "web.cancelTimeout"
+
+// ErrRequestTimeout is responded if the controller method does not return before its timeout
+var ErrRequestTimeout = NewError(http.StatusServiceUnavailable, "request_timeout", "request timeout")
+
+type invocation struct {
+	results   []reflect.Value
+	recovered interface{}
+}
+
+// parseTimeout returns the timeout of at.Timeout, or server.request_timeout if the method is not annotated
+func (h *handler) parseTimeout() (timeout time.Duration) {
+	if conf, ok := h.factory.GetInstance(system.Configuration{}).(*system.Configuration); ok && conf.Server != nil {
+		timeout = time.Duration(conf.Server.RequestTimeout) * time.Second
+	}
+	if h.annotations != nil {
+		if ann := annotation.GetAnnotation(h.annotations, at.Timeout{}); ann != nil {
+			timeout = time.Duration(ann.Field.Value.Interface().(at.Timeout).AtValue) * time.Second
+		}
+	}
+	for _, req := range h.requests {
+		if req.kind == reflect.Interface && req.iTyp != stdContextType && req.typeName == model.Context {
+			h.writesContext = true
+		}
+	}
+	return
+}
+
+// withTimeout derives the request whose context has the deadline of the timeout, the derived request is returned by
+// Request() of the hiboot context in all handlers of the request instead of replacing the shared *http.Request,
+// the deadline is released by releaseTimeout once the response is finalized
+func withTimeout(ctx context.Context, timeout time.Duration) stdcontext.Context {
+	deadline, cancel := stdcontext.WithTimeout(ctx.Request().Context(), timeout)
+	setRequest(ctx, ctx.Request().WithContext(deadline))
+	ctx.Values().Set(cancelKey, cancel)
+	return deadline
+}
+
+// releaseTimeout cancels the deadline of the request once its response, including the streamed one, is finalized
+func releaseTimeout(ctx context.Context) {
+	if cancel, ok := ctx.Values().Get(cancelKey).(stdcontext.CancelFunc); ok {
+		ctx.Values().Remove(cancelKey)
+		cancel()
+	}
+}
+
+// callMethod calls the controller method through its interceptors
+func (h *handler) callMethod(inputs []reflect.Value) []reflect.Value {
+	if len(h.interceptors) > 0 {
+		return aop.Invoke(h.interceptors, *h.method, inputs)
+	}
+	return h.method.Func.Call(inputs)
+}
+
+// invoke calls the controller method and waits for it until the deadline, 503 is responded once the deadline expires
+// unless the method has written the response. The method receives the deadline through its stdcontext.Context argument
+// and ctx.Request().Context() of the hiboot context, it must return once the deadline is done, as it is not stopped.
+// The method that takes the hiboot context.Context or the request scoped dependencies writes the response through a
+// guarded writer, the timeout response is written through it and the request waits for the method to return before
+// the pooled context is released, the results of the timed out method are discarded
+func (h *handler) invoke(ctx context.Context, deadline stdcontext.Context, inputs []reflect.Value) (results []reflect.Value, err error) {
+	if deadline == nil {
+		results = h.callMethod(inputs)
+		return
+	}
+
+	var tw *timeoutWriter
+	var timeoutBody []byte
+	if h.writesContext || len(h.dependencies) > 0 {
+		// the timeout response is rendered before the method runs, the context is owned by the method afterwards
+		timeoutBody, _ = json.Marshal(newProblem(ctx, ErrRequestTimeout))
+		tw = &timeoutWriter{ResponseWriter: ctx.ResponseWriter()}
+		ctx.ResetResponseWriter(tw)
+	}
+
+	done := make(chan invocation, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- invocation{recovered: r}
+			}
+		}()
+		done <- invocation{results: h.callMethod(inputs)}
+	}()
+	var inv invocation
+	select {
+	case inv = <-done:
+	case <-deadline.Done():
+		select {
+		case inv = <-done:
+		case <-time.After(gracePeriod):
+			if tw == nil {
+				err = ErrRequestTimeout
+				return
+			}
+			timedOut := tw.timeout(timeoutBody)
+			inv = <-done
+			if timedOut {
+				// keep the guarded writer, so that the writes of the handlers afterwards are discarded as well
+				return
+			}
+		}
+	}
+	if tw != nil {
+		ctx.ResetResponseWriter(tw.ResponseWriter)
+	}
+	if inv.recovered != nil {
+		// let the recover middleware of the request goroutine handle it
+		panic(inv.recovered)
+	}
+	results = inv.results
+	if tw != nil && deadline.Err() == stdcontext.DeadlineExceeded && ctx.ResponseWriter().Written() <= 0 {
+		// the method returns once the deadline is done without writing the response
+		results, err = nil, ErrRequestTimeout
+	}
+	return
+}
+
+// timeoutWriter guards the response writer of the method that takes the hiboot context.Context, the writes of the
+// method are discarded once the timeout response is written
+type timeoutWriter struct {
+	ictx.ResponseWriter
+	mu       sync.Mutex
+	timedOut bool
+}
+
+// timeout writes the timeout response unless the method has written the response, it returns true if it is written
+func (w *timeoutWriter) timeout(body []byte) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.ResponseWriter.Written() != ictx.NoWritten {
+		return false
+	}
+	w.timedOut = true
+	header := w.ResponseWriter.Header()
+	header.Set(ictx.ContentTypeHeaderKey, problemContentType)
+	// the client receives the whole response before the method returns
+	if header.Get("Content-Encoding") == "" {
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	w.ResponseWriter.WriteHeader(ErrRequestTimeout.Status)
+	_, _ = w.ResponseWriter.Write(body)
+	w.ResponseWriter.Flush()
+	return true
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return http.Header{}
+	}
+	return w.ResponseWriter.Header()
+}
+
+func (w *timeoutWriter) WriteHeader(statusCode int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.timedOut {
+		w.ResponseWriter.WriteHeader(statusCode)
+	}
+}
+
+func (w *timeoutWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutWriter) Writef(format string, a ...interface{}) (int, error) {
+	return fmt.Fprintf(w, format, a...)
+}
+
+func (w *timeoutWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.timedOut {
+		w.ResponseWriter.Flush()
+	}
+}
+
+func (w *timeoutWriter) Written() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.ResponseWriter.Written()
+}
@@ -0,0 +1,185 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web_test
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/app/web/server"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/stretchr/testify/assert"
+)
+
+type reportController struct {
+	at.RestController
+	at.RequestMapping `value:"/report"`
+}
+
+func newReportController() *reportController {
+	return &reportController{}
+}
+
+// GetHang does not honor the deadline, it uses server.request_timeout
+func (c *reportController) GetHang(_ struct {
+	at.GetMapping `value:"/hang"`
+}) string {
+	time.Sleep(3 * time.Second)
+	return "hang"
+}
+
+func (c *reportController) GetFast(_ struct {
+	at.GetMapping `value:"/fast"`
+	at.Timeout    `value:"2"`
+}, ctx stdcontext.Context) string {
+	_, ok := ctx.Deadline()
+	if !ok {
+		return "no deadline"
+	}
+	return "fast"
+}
+
+func (c *reportController) GetUnlimited(_ struct {
+	at.GetMapping `value:"/unlimited"`
+	at.Timeout    `value:"0"`
+}) string {
+	time.Sleep(1500 * time.Millisecond)
+	return "unlimited"
+}
+
+// GetDownstream returns the error of the downstream call that is cancelled by the deadline
+func (c *reportController) GetDownstream(_ struct {
+	at.GetMapping `value:"/downstream"`
+}, ctx stdcontext.Context) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+// GetContext takes the hiboot context and returns once the deadline of its request is done
+func (c *reportController) GetContext(_ struct {
+	at.GetMapping `value:"/context"`
+}, ctx context.Context) string {
+	select {
+	case <-ctx.Request().Context().Done():
+	case <-time.After(3 * time.Second):
+	}
+	return "context"
+}
+
+// GetStubborn takes the hiboot context and ignores the deadline, its response is discarded once the deadline expires
+func (c *reportController) GetStubborn(_ struct {
+	at.GetMapping `value:"/stubborn"`
+}, ctx context.Context) string {
+	time.Sleep(2 * time.Second)
+	_, _ = ctx.WriteString("late")
+	return "stubborn"
+}
+
+// GetTicks streams the ticks after the method returns, the deadline is kept until the response is finished
+func (c *reportController) GetTicks(_ struct {
+	at.GetMapping `value:"/ticks"`
+}, ctx stdcontext.Context) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 1; i <= 3; i++ {
+			select {
+			case ch <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func TestRequestTimeout(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	testApp := web.NewTestApp(newReportController).
+		SetProperty(app.ProfilesInclude, web.Profile).
+		SetProperty(server.RequestTimeout, 1).
+		Run(t)
+
+	t.Run("should respond 503 if the method does not return before the deadline", func(t *testing.T) {
+		start := time.Now()
+		resp := testApp.Get("/report/hang").Expect().Status(http.StatusServiceUnavailable)
+		assert.Less(t, int64(time.Since(start)), int64(2*time.Second))
+		p := problemOf(t, resp)
+		p.Value("code").Equal("request_timeout")
+		p.Value("instance").Equal("/report/hang")
+	})
+
+	t.Run("should pass the deadline to the method", func(t *testing.T) {
+		testApp.Get("/report/fast").Expect().Status(http.StatusOK).Body().Equal("fast")
+	})
+
+	t.Run("should not time out if at.Timeout is 0", func(t *testing.T) {
+		testApp.Get("/report/unlimited").Expect().Status(http.StatusOK).Body().Equal("unlimited")
+	})
+
+	t.Run("should respond 504 if the downstream call exceeds the deadline", func(t *testing.T) {
+		testApp.Get("/report/downstream").Expect().Status(http.StatusGatewayTimeout)
+	})
+
+	t.Run("should keep the deadline until the streamed response is finished", func(t *testing.T) {
+		testApp.Get("/report/ticks").Expect().Status(http.StatusOK).
+			Body().Equal("data: 1\n\ndata: 2\n\ndata: 3\n\n")
+	})
+
+	t.Run("should respond 503 if the method that takes the hiboot context expires", func(t *testing.T) {
+		problemOf(t, testApp.Get("/report/context").Expect().Status(http.StatusServiceUnavailable)).
+			Value("status").Equal(http.StatusServiceUnavailable)
+	})
+}
+
+func TestRequestTimeoutOfContext(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	testApp := web.NewApplication(newReportController).
+		SetProperty(server.Port, 0).
+		SetProperty(server.RequestTimeout, 1).
+		SetProperty(server.ShutdownTimeout, 3).
+		SetProperty(app.BannerDisabled, true)
+	event, done := runApplication(t, testApp)
+	assert.NotEqual(t, nil, event.ServerStartedEvent)
+
+	t.Run("should respond 503 before the method that takes the hiboot context returns", func(t *testing.T) {
+		client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+		start := time.Now()
+		resp, err := client.Get("http://" + event.Address + "/report/stubborn")
+		assert.Equal(t, nil, err)
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		assert.Equal(t, nil, err)
+		var p map[string]interface{}
+		assert.Equal(t, nil, json.Unmarshal(body, &p))
+		assert.Less(t, int64(time.Since(start)), int64(1500*time.Millisecond))
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "request_timeout", p["code"])
+	})
+
+	assert.Equal(t, nil, testApp.Shutdown())
+	<-done
+}
@@ -0,0 +1,329 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/utils/reflector"
+)
+
+const (
+	defaultMaxMemory = 32 << 20
+	megabytes        = 1 << 20
+)
+
+var (
+	fileHeaderType   = reflect.TypeOf((*multipart.FileHeader)(nil))
+	uploadedFileType = reflect.TypeOf(UploadedFile{})
+)
+
+// UploadedFile is the uploaded file of multipart form, it can be bound to the field of at.RequestForm
+//
+//	type uploadRequest struct {
+//		at.RequestForm
+//
+//		Name   string             `form:"name"`
+//		Avatar *web.UploadedFile  `form:"avatar" file:"max_size=2MB,types=image/png|image/jpeg"`
+//		Photos []*web.UploadedFile `form:"photos" file:"max_size=10MB,max_files=5,types=image/*"`
+//	}
+//
+// max_files is the max number of the files of the slice field, it bounds the size of the request body with max_size
+type UploadedFile struct {
+	*multipart.FileHeader
+}
+
+// ContentType returns the content type of the uploaded file
+func (f *UploadedFile) ContentType() string {
+	return f.Header.Get("Content-Type")
+}
+
+// Bytes reads the content of the uploaded file
+func (f *UploadedFile) Bytes() (data []byte, err error) {
+	file, err := f.Open()
+	if err == nil {
+		defer file.Close()
+		data, err = io.ReadAll(file)
+	}
+	return
+}
+
+// SaveTo saves the uploaded file to the file of path
+func (f *UploadedFile) SaveTo(path string) (err error) {
+	src, err := f.Open()
+	if err != nil {
+		return
+	}
+	defer src.Close()
+	dst, err := os.Create(path)
+	if err != nil {
+		return
+	}
+	defer dst.Close()
+	_, err = io.Copy(dst, src)
+	return
+}
+
+// uploadLimit is the limit of uploaded files, the file tag of the field overrides web.upload.* properties
+type uploadLimit struct {
+	maxSize        int64
+	maxFiles       int
+	types          []string
+	maxRequestSize int64
+}
+
+// parseFileTag parses file tag, e.g. `file:"max_size=2MB,max_files=5,types=image/png|image/jpeg"`
+func parseFileTag(tag string, limit uploadLimit) (uploadLimit, error) {
+	for _, opt := range strings.Split(tag, ",") {
+		kv := strings.SplitN(strings.TrimSpace(opt), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "max_size":
+			size, err := parseSize(kv[1])
+			if err != nil {
+				return limit, err
+			}
+			limit.maxSize = size
+		case "max_files":
+			n, err := strconv.Atoi(kv[1])
+			if err != nil {
+				return limit, err
+			}
+			limit.maxFiles = n
+		case "types":
+			limit.types = strings.Split(kv[1], "|")
+		}
+	}
+	return limit, nil
+}
+
+// parseSize parses the size in bytes, KB, MB or GB
+func parseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	unit := int64(1)
+	for suffix, u := range map[string]int64{"KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30} {
+		if strings.HasSuffix(s, suffix) {
+			unit, s = u, strings.TrimSuffix(s, suffix)
+			break
+		}
+	}
+	n, err := strconv.ParseInt(strings.TrimSuffix(s, "B"), 10, 64)
+	return n * unit, err
+}
+
+// checkCount checks the number of the files of the field against max files
+func (l uploadLimit) checkCount(field string, count int) *Error {
+	if l.maxFiles > 0 && count > l.maxFiles {
+		return &Error{
+			Status: http.StatusRequestEntityTooLarge,
+			Code:   "too_many_files",
+			Detail: fmt.Sprintf("%v files of %v exceed the max files of %v", count, field, l.maxFiles),
+			Errors: []FieldError{{Field: field, Tag: "max_files", Message: fmt.Sprintf("max files is %v", l.maxFiles)}},
+		}
+	}
+	return nil
+}
+
+func (l uploadLimit) check(field string, fh *multipart.FileHeader) *Error {
+	if l.maxSize > 0 && fh.Size > l.maxSize {
+		return &Error{
+			Status: http.StatusRequestEntityTooLarge,
+			Code:   "file_too_large",
+			Detail: fmt.Sprintf("file %v exceeds the max size of %v bytes", fh.Filename, l.maxSize),
+			Errors: []FieldError{{Field: field, Tag: "max_size", Message: fmt.Sprintf("max size is %v bytes", l.maxSize)}},
+		}
+	}
+	if len(l.types) != 0 {
+		contentType := fh.Header.Get("Content-Type")
+		for _, t := range l.types {
+			if t == contentType || (strings.HasSuffix(t, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(t, "*"))) {
+				return nil
+			}
+		}
+		return &Error{
+			Status: http.StatusUnsupportedMediaType,
+			Code:   "unsupported_media_type",
+			Detail: fmt.Sprintf("content type %v of file %v is not allowed", contentType, fh.Filename),
+			Errors: []FieldError{{Field: field, Tag: "types", Message: "allowed types are " + strings.Join(l.types, ", ")}},
+		}
+	}
+	return nil
+}
+
+// requestSize returns the max bytes of the multipart request body that binds data, it is web.upload.max_request_size
+// if it is set, otherwise it is maxMemory plus the sum of max size times max files of the file fields, a single file
+// field counts one file, 0 means unlimited
+func (l uploadLimit) requestSize(data interface{}, maxMemory int64) (size int64, err error) {
+	if l.maxRequestSize > 0 {
+		return l.maxRequestSize, nil
+	}
+	typ := reflector.IndirectType(reflect.TypeOf(data))
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+	var filesSize int64
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" || !isFileType(field.Type) {
+			continue
+		}
+		var fl uploadLimit
+		fl, err = parseFileTag(field.Tag.Get("file"), l)
+		if err != nil || fl.maxSize == 0 {
+			return 0, err
+		}
+		files := 1
+		if field.Type.Kind() == reflect.Slice {
+			if fl.maxFiles == 0 {
+				return 0, nil
+			}
+			files = fl.maxFiles
+		}
+		filesSize += fl.maxSize * int64(files)
+	}
+	return maxMemory + filesSize, nil
+}
+
+// isFileType returns true if typ can be bound from uploaded files
+func isFileType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	return typ == fileHeaderType || reflector.IndirectType(typ) == uploadedFileType
+}
+
+// bindFiles binds the uploaded files of multipart form into the file fields of data
+func bindFiles(c context.Context, data interface{}, limit uploadLimit) error {
+	form := formRequest(c).MultipartForm
+	if form == nil || len(form.File) == 0 {
+		return nil
+	}
+
+	val := reflect.Indirect(reflect.ValueOf(data))
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" || !isFileType(field.Type) {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("form"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		headers := form.File[name]
+		if len(headers) == 0 {
+			continue
+		}
+
+		l, err := parseFileTag(field.Tag.Get("file"), limit)
+		if err != nil {
+			return err
+		}
+		if e := l.checkCount(name, len(headers)); e != nil {
+			return e
+		}
+		for _, fh := range headers {
+			if e := l.check(name, fh); e != nil {
+				return e
+			}
+		}
+
+		fv := val.Field(i)
+		if field.Type.Kind() == reflect.Slice {
+			files := reflect.MakeSlice(field.Type, 0, len(headers))
+			for _, fh := range headers {
+				files = reflect.Append(files, fileValue(field.Type.Elem(), fh))
+			}
+			fv.Set(files)
+		} else {
+			fv.Set(fileValue(field.Type, headers[0]))
+		}
+	}
+	return nil
+}
+
+func fileValue(typ reflect.Type, fh *multipart.FileHeader) reflect.Value {
+	switch typ {
+	case fileHeaderType:
+		return reflect.ValueOf(fh)
+	case uploadedFileType:
+		return reflect.ValueOf(UploadedFile{FileHeader: fh})
+	default:
+		return reflect.ValueOf(&UploadedFile{FileHeader: fh})
+	}
+}
+
+// formRequest returns the request that the form is parsed into, it is the one that iris reads the form values from
+// rather than the one derived with the deadline
+func formRequest(c context.Context) *http.Request {
+	if ctx, ok := c.(*Context); ok {
+		return ctx.Context.Request()
+	}
+	return c.Request()
+}
+
+// readForm parses the multipart form within maxMemory bytes, then binds the form values and the uploaded files,
+// 413 is responded if the request body exceeds the max request size
+func readForm(c context.Context, data interface{}, maxMemory int64, limit uploadLimit) error {
+	if strings.HasPrefix(c.GetHeader("Content-Type"), "multipart/form-data") {
+		req := formRequest(c)
+		size, err := limit.requestSize(data, maxMemory)
+		if err != nil {
+			return err
+		}
+		if size > 0 {
+			req.Body = http.MaxBytesReader(c.ResponseWriter(), req.Body, size)
+		}
+		err = req.ParseMultipartForm(maxMemory)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return &Error{
+				Status: http.StatusRequestEntityTooLarge,
+				Code:   "request_too_large",
+				Detail: fmt.Sprintf("request body exceeds the max size of %v bytes", tooLarge.Limit),
+				cause:  err,
+			}
+		}
+		if err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return &Error{Status: http.StatusBadRequest, Detail: err.Error(), cause: err}
+		}
+	}
+	err := c.ReadForm(data)
+	if err == nil {
+		err = bindFiles(c, data, limit)
+	}
+	return err
+}
+
+// requestForm binds the form with the limits of web.upload.* properties and validates it
+func (h *handler) requestForm(c context.Context, data interface{}) error {
+	return requestEx(c, data, func() error {
+		return readForm(c, data, h.maxMemory, h.uploadLimit)
+	})
+}
@@ -0,0 +1,200 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web_test
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/httpexpect"
+	"github.com/stretchr/testify/assert"
+)
+
+type uploadRequest struct {
+	at.RequestForm
+
+	Name        string                  `form:"name" validate:"required"`
+	Avatar      *web.UploadedFile       `form:"avatar" file:"max_size=16B,types=image/png|image/jpeg"`
+	Photos      []*web.UploadedFile     `form:"photos" file:"types=image/*"`
+	Attachment  *multipart.FileHeader   `form:"attachment"`
+	Attachments []*multipart.FileHeader `form:"attachments"`
+}
+
+type uploadController struct {
+	at.RestController
+}
+
+func newUploadController() *uploadController {
+	return &uploadController{}
+}
+
+type photosRequest struct {
+	at.RequestForm
+
+	Photos []*web.UploadedFile `form:"photos" file:"max_size=1MB,max_files=3"`
+}
+
+func (c *uploadController) PostPhotos(request *photosRequest) string {
+	return fmt.Sprintf("photos:%v", len(request.Photos))
+}
+
+func (c *uploadController) Post(request *uploadRequest) string {
+	var result []string
+	result = append(result, request.Name)
+	if request.Avatar != nil {
+		data, _ := request.Avatar.Bytes()
+		result = append(result, fmt.Sprintf("avatar:%v:%v:%v", request.Avatar.Filename, request.Avatar.ContentType(), string(data)))
+	}
+	for _, p := range request.Photos {
+		result = append(result, "photo:"+p.Filename)
+	}
+	if request.Attachment != nil {
+		result = append(result, fmt.Sprintf("attachment:%v:%v", request.Attachment.Filename, request.Attachment.Size))
+	}
+	result = append(result, fmt.Sprintf("attachments:%v", len(request.Attachments)))
+	return strings.Join(result, ",")
+}
+
+type part struct {
+	field, filename, contentType, content string
+}
+
+func multipartBody(t *testing.T, values map[string]string, parts ...part) (string, []byte) {
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	for k, v := range values {
+		assert.Equal(t, nil, w.WriteField(k, v))
+	}
+	for _, p := range parts {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, p.field, p.filename))
+		h.Set("Content-Type", p.contentType)
+		pw, err := w.CreatePart(h)
+		assert.Equal(t, nil, err)
+		_, _ = pw.Write([]byte(p.content))
+	}
+	assert.Equal(t, nil, w.Close())
+	return w.FormDataContentType(), body.Bytes()
+}
+
+func TestUpload(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	testApp := web.NewTestApp(newUploadController).
+		SetProperty(web.UploadMaxFileSize, 1).
+		Run(t)
+
+	upload := func(values map[string]string, parts ...part) *httpexpect.Response {
+		contentType, body := multipartBody(t, values, parts...)
+		return testApp.Post("/upload").WithHeader("Content-Type", contentType).WithBytes(body).Expect()
+	}
+
+	t.Run("should bind uploaded files", func(t *testing.T) {
+		upload(map[string]string{"name": "john"},
+			part{"avatar", "me.png", "image/png", "png"},
+			part{"photos", "a.jpg", "image/jpeg", "a"},
+			part{"photos", "b.gif", "image/gif", "b"},
+			part{"attachment", "doc.txt", "text/plain", "hello"},
+			part{"attachments", "1.txt", "text/plain", "1"},
+			part{"attachments", "2.txt", "text/plain", "2"},
+		).Status(http.StatusOK).
+			Body().Equal("john,avatar:me.png:image/png:png,photo:a.jpg,photo:b.gif,attachment:doc.txt:5,attachments:2")
+	})
+
+	t.Run("should reject file that exceeds the max size of file tag", func(t *testing.T) {
+		upload(map[string]string{"name": "john"},
+			part{"avatar", "me.png", "image/png", strings.Repeat("x", 17)},
+		).Status(http.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("should reject file that exceeds web.upload.max_file_size", func(t *testing.T) {
+		upload(map[string]string{"name": "john"},
+			part{"attachment", "big.bin", "application/octet-stream", strings.Repeat("x", 1<<20+1)},
+		).Status(http.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("should reject file that is not allowed", func(t *testing.T) {
+		upload(map[string]string{"name": "john"},
+			part{"photos", "a.txt", "text/plain", "a"},
+		).Status(http.StatusUnsupportedMediaType)
+	})
+
+	t.Run("should validate form values", func(t *testing.T) {
+		upload(nil, part{"attachment", "doc.txt", "text/plain", "hello"}).
+			Status(http.StatusBadRequest)
+	})
+}
+
+func TestUploadMaxRequestSize(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	testApp := web.NewTestApp(newUploadController).
+		SetProperty(web.UploadMaxRequestSize, 1).
+		Run(t)
+
+	upload := func(parts ...part) *httpexpect.Response {
+		contentType, body := multipartBody(t, map[string]string{"name": "john"}, parts...)
+		return testApp.Post("/upload").WithHeader("Content-Type", contentType).WithBytes(body).Expect()
+	}
+
+	t.Run("should bind the files within the max request size", func(t *testing.T) {
+		upload(part{"attachment", "a.bin", "application/octet-stream", strings.Repeat("x", 600<<10)}).
+			Status(http.StatusOK)
+	})
+
+	t.Run("should reject the request body that exceeds the max request size", func(t *testing.T) {
+		resp := upload(
+			part{"attachments", "a.bin", "application/octet-stream", strings.Repeat("x", 600<<10)},
+			part{"attachments", "b.bin", "application/octet-stream", strings.Repeat("x", 600<<10)},
+		).Status(http.StatusRequestEntityTooLarge)
+		assert.Contains(t, resp.Body().Raw(), "request body exceeds the max size")
+	})
+}
+
+func TestUploadMultipleFiles(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	testApp := web.NewTestApp(newUploadController).
+		SetProperty(web.UploadMaxMemory, 1).
+		Run(t)
+
+	upload := func(n int) *httpexpect.Response {
+		var parts []part
+		for i := 0; i < n; i++ {
+			parts = append(parts, part{"photos", fmt.Sprintf("%v.jpg", i), "image/jpeg", strings.Repeat("x", 900<<10)})
+		}
+		contentType, body := multipartBody(t, nil, parts...)
+		return testApp.Post("/upload/photos").WithHeader("Content-Type", contentType).WithBytes(body).Expect()
+	}
+
+	t.Run("should bind the files within max_size times max_files", func(t *testing.T) {
+		upload(3).Status(http.StatusOK).Body().Equal("photos:3")
+	})
+
+	t.Run("should reject the files that exceed max_files", func(t *testing.T) {
+		resp := upload(4).Status(http.StatusRequestEntityTooLarge)
+		assert.Contains(t, resp.Body().Raw(), "exceed the max files of 3")
+	})
+}
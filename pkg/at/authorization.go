@@ -30,6 +30,9 @@ type RequiresRoles struct {
 	Annotation
 
 	RequiresLogical
+
+	// AtValues hold the role values as an array,  e.g. `values:"admin,user"`
+	AtValues []string `at:"values" json:"-"`
 }
 
 
@@ -0,0 +1,69 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package at
+
+// Cacheable is the annotation that caches the result of the method, the method is not called
+// if the result of the same key is cached, e.g.
+//
+//	func (s *userService) Get(_ struct{ at.Cacheable `name:"users" key:"user:{0}"` }, id int) (*User, error)
+//
+// the key expression refers to the arguments of the method by index, the annotation parameters and the contexts are not counted,
+// and to their fields or map values by name, e.g. {0.ID}, all arguments are the key if it is not specified
+type Cacheable struct {
+	Annotation
+
+	InterceptorBinding
+
+	// AtName is the name of the cache
+	AtName string `at:"name" json:"-"`
+
+	// AtKey is the key expression
+	AtKey string `at:"key" json:"-"`
+}
+
+// CachePut is the annotation that always calls the method and caches its result, e.g.
+//
+//	func (s *userService) Update(_ struct{ at.CachePut `name:"users" key:"user:{0.ID}"` }, user *User) (*User, error)
+type CachePut struct {
+	Annotation
+
+	InterceptorBinding
+
+	// AtName is the name of the cache
+	AtName string `at:"name" json:"-"`
+
+	// AtKey is the key expression
+	AtKey string `at:"key" json:"-"`
+}
+
+// CacheEvict is the annotation that evicts the cached result of the key, or all entries of the cache,
+// once the method succeeds, e.g.
+//
+//	func (s *userService) Delete(_ struct{ at.CacheEvict `name:"users" key:"user:{0}"` }, id int) error
+//	func (s *userService) Reload(_ struct{ at.CacheEvict `name:"users" allEntries:"true"` }) error
+type CacheEvict struct {
+	Annotation
+
+	InterceptorBinding
+
+	// AtName is the name of the cache
+	AtName string `at:"name" json:"-"`
+
+	// AtKey is the key expression
+	AtKey string `at:"key" json:"-"`
+
+	// AtAllEntries evicts all entries of the cache
+	AtAllEntries bool `at:"allEntries" json:"-"`
+}
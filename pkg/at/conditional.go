@@ -26,3 +26,55 @@ type ConditionalOnField struct {
 
 	BaseAnnotation
 }
+
+// ConditionalOnProperty annotation registers the component or the auto configuration only if the property
+// has the expected value, havingValue is optional, the property matches if it is present and not false by default,
+// matchIfMissing means that the condition matches if the property is not set
+//
+//	type configuration struct {
+//	  at.AutoConfiguration
+//
+//	  at.ConditionalOnProperty `name:"cache.enabled" havingValue:"true" matchIfMissing:"true"`
+//	}
+type ConditionalOnProperty struct {
+	Annotation
+
+	BaseAnnotation
+}
+
+// ConditionalOnBean annotation registers the component or the auto configuration only if all of the
+// given beans are registered, the bean names are separated by comma, e.g.
+//
+//	func (c *configuration) Cache(_ struct{ at.ConditionalOnBean `value:"redis.client"` }) Cache {
+//	  ...
+//	}
+type ConditionalOnBean struct {
+	Annotation
+
+	BaseAnnotation
+}
+
+// ConditionalOnMissingBean annotation registers the component or the auto configuration only if none of the
+// given beans is registered, the bean name of the component itself is used if value is not specified,
+// so that the starter backs off once the user supplies the bean, e.g.
+//
+//	func (c *configuration) Store(_ struct{ at.ConditionalOnMissingBean }) Store {
+//	  return NewMemoryStore()
+//	}
+type ConditionalOnMissingBean struct {
+	Annotation
+
+	BaseAnnotation
+}
+
+// ConditionalOnProfile annotation registers the component or the auto configuration only if one of the given
+// profiles is active, the profiles are separated by comma, the profile that starts with ! matches if it is not active
+//
+//	type devService struct {
+//	  at.ConditionalOnProfile `value:"dev,local"`
+//	}
+type ConditionalOnProfile struct {
+	Annotation
+
+	BaseAnnotation
+}
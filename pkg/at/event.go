@@ -0,0 +1,35 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package at
+
+// EventListener is the annotation of the method that listens to the application event, the event is typed by the
+// other parameter of the method, e.g.
+//
+//	func (s *orderService) OnOrderCreated(_ struct{ at.EventListener `async:"true" order:"1"` }, event *OrderCreatedEvent) {
+//	  ...
+//	}
+//
+// the listeners are called in order, the lower order is called first, the asynchronous listener is called by the worker pool
+type EventListener struct {
+	Annotation
+
+	BaseAnnotation
+
+	// AtAsync delivers the event to the listener by the worker pool
+	AtAsync bool `at:"async" json:"-"`
+
+	// AtOrder is the order of the listener
+	AtOrder int `at:"order" json:"-"`
+}
@@ -27,3 +27,15 @@ type AfterInit struct {
 
 	BaseAnnotation
 }
+
+// PreDestroy annotation in hiboot is used to mark a method that is called before the application is shut down,
+// singleton components are destroyed in the reverse order of their dependencies
+//
+//	func (s *Example) Destroy(_ struct{ at.PreDestroy }) {
+//	  ...
+//	}
+type PreDestroy struct {
+	Annotation `json:"-"`
+
+	BaseAnnotation
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package at
+
+// Interceptor is the annotation of the component that implements aop.Interceptor, it intercepts the methods
+// that are annotated with the interceptor binding annotations it embeds, the interceptors are sorted by at.Order, e.g.
+//
+//	type timedInterceptor struct {
+//	  at.Interceptor
+//	  Timed
+//	}
+type Interceptor struct {
+	Annotation
+
+	BaseAnnotation
+}
+
+// InterceptorBinding is embedded by the custom annotation that binds the interceptors to the methods,
+// the annotation is declared on the method parameter, or on the component for all of its methods,
+// or only for the methods in its tag methods, e.g.
+//
+//	type Timed struct {
+//	  at.Annotation
+//
+//	  at.InterceptorBinding
+//	}
+//
+//	func (c *fooController) Get(_ struct{ at.GetMapping `value:"/"`; Timed }) string
+//
+//	type fooService struct {
+//	  Timed `methods:"Get,Update"`
+//	}
+type InterceptorBinding struct {
+	Annotation
+
+	BaseAnnotation
+}
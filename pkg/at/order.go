@@ -0,0 +1,39 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package at
+
+// Order is the annotation that sorts the implementations of the interface that are injected as a slice or a map,
+// the lower value has the higher precedence, the implementations without at.Order are sorted at last, e.g.
+//
+//	type fooService struct {
+//	  at.Order `value:"1"`
+//	}
+type Order struct {
+	Annotation
+
+	BaseAnnotation
+}
+
+// Primary is the annotation that marks the implementation that is injected if there are more than one
+// implementations of the interface that is requested
+//
+//	type fooService struct {
+//	  at.Primary
+//	}
+type Primary struct {
+	Annotation
+
+	BaseAnnotation
+}
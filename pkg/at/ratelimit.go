@@ -0,0 +1,41 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package at
+
+// RateLimit is the annotation that limits the request rate of the controller or method, e.g.
+//
+//	at.RateLimit `limit:"10" period:"60" algorithm:"sliding_window" key:"jwt"`
+//
+// the values that are not specified fall back to the properties ratelimit.*
+type RateLimit struct {
+	Annotation
+
+	UseMiddleware
+
+	// AtLimit is the max number of requests in the period
+	AtLimit int `at:"limit" json:"-"`
+
+	// AtPeriod is the period in seconds
+	AtPeriod int64 `at:"period" json:"-"`
+
+	// AtBurst is the capacity of the token bucket, it is the same as limit by default
+	AtBurst int `at:"burst" json:"-"`
+
+	// AtAlgorithm is the limiter algorithm, token_bucket or sliding_window
+	AtAlgorithm string `at:"algorithm" json:"-"`
+
+	// AtKey is the name of the key function that identifies the client, ip, jwt or the registered custom key function
+	AtKey string `at:"key" json:"-"`
+}
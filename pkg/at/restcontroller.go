@@ -36,6 +36,14 @@ type HttpMethodSubscriber struct{
 	BaseAnnotation
 }
 
+// ManagementEndpoint is the annotation that declares the controller as a management endpoint,
+// it is served on management.server.port instead of server.port if the management port is set
+type ManagementEndpoint struct {
+	Annotation
+
+	BaseAnnotation
+}
+
 // ContextPath is the annotation that set the context path of a controller
 type ContextPath struct {
 	Annotation
@@ -150,4 +158,13 @@ type FileServer struct {
 	Annotation
 
 	RequestMapping
-}
\ No newline at end of file
+}
+// Timeout is the annotation that sets the timeout in seconds of the controller method, it overrides server.request_timeout,
+// 0 means no timeout, e.g. at.Timeout `value:"5"`. The streamed response is also bounded by the timeout.
+// The method receives the deadline through its context argument, it must return once the deadline is done,
+// as it keeps running after 503 is responded.
+type Timeout struct {
+	Annotation
+
+	AtValue int64 `at:"value" json:"-"`
+}
@@ -35,3 +35,18 @@ type ContextAware struct {
 
 	Scope `value:"request"`
 }
+
+// RefreshScope is the annotation of the component that is rebuilt lazily after the configuration
+// properties are refreshed at runtime, it is injected into the method of Rest Controller like at.Scope "request",
+// and the same instance is reused until next refresh. Only the RefreshScope components see the refreshed
+// configuration properties, the singleton ones are not changed.
+//
+//	type Example struct {
+//	  at.RefreshScope
+//	  ...
+//	}
+type RefreshScope struct {
+	Annotation
+
+	Scope `value:"refresh"`
+}
@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/hidevopsio/hiboot/pkg/aop"
 	"github.com/hidevopsio/hiboot/pkg/at"
 	"github.com/hidevopsio/hiboot/pkg/factory"
 	"github.com/hidevopsio/hiboot/pkg/inject/annotation"
@@ -295,6 +296,10 @@ func (f *configurableFactory) build(cfgContainer []*factory.MetaData) {
 				continue
 			}
 		}
+		if !f.MatchConditions(item) {
+			log.Debugf("Auto configuration %v is skipped as the conditions do not match.", name)
+			continue
+		}
 		log.Debugf("Auto configuration %v is configured on %v.", item.PkgName, item.Type)
 
 		err = f.initProperties(config)
@@ -382,34 +387,65 @@ func (f *configurableFactory) StartSchedulers(schedulerServices []*factory.MetaD
 			schedulers = append(schedulers, sch)
 		}
 	}
-	return nil
+	return
 }
 
 func (f *configurableFactory) runTaskEx(schAnn at.Scheduled, sch *scheduler.Scheduler, svc interface{}, method reflect.Method, ann *annotation.Annotation) {
+	interceptors := aop.Match(f.GetInstances(at.Interceptor{}), svc, method)
 	if schAnn.AtCron != nil {
 		sch.RunWithExpr(schAnn.AtTag, schAnn.AtCron,
 			func() {
-				f.runTask(svc, method, ann, sch)
+				f.runTask(svc, method, ann, sch, interceptors)
 			},
 		)
 	} else {
 		sch.Run(schAnn.AtTag, schAnn.AtLimit, schAnn.AtEvery, schAnn.AtUnit, schAnn.AtTime, schAnn.AtDelay, schAnn.AtSync,
 			func() {
-				f.runTask(svc, method, ann, sch)
+				f.runTask(svc, method, ann, sch, interceptors)
 			},
 		)
 	}
 }
 
-func (f *configurableFactory) runTask(svc interface{}, method reflect.Method, ann *annotation.Annotation, sch *scheduler.Scheduler) {
-	result, err := reflector.CallMethodByName(svc, method.Name, ann.Parent.Value.Interface())
+func (f *configurableFactory) runTask(svc interface{}, method reflect.Method, ann *annotation.Annotation, sch *scheduler.Scheduler, interceptors []aop.Interceptor) {
+	job := jobName(svc, method, ann)
+	failed := true
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("scheduled job %v panic: %v", job, r)
+		}
+		scheduler.RecordRun(job, failed)
+	}()
+
+	var result interface{}
+	var err error
+	if len(interceptors) > 0 {
+		results := aop.Invoke(interceptors, method, []reflect.Value{reflect.ValueOf(svc), reflect.ValueOf(ann.Parent.Value.Interface())})
+		if len(results) != 0 {
+			result = results[0].Interface()
+		}
+	} else {
+		result, err = reflector.CallMethodByName(svc, method.Name, ann.Parent.Value.Interface())
+	}
 	if err == nil {
+		failed = false
 		switch result.(type) {
 		case bool:
 			res := result.(bool)
 			if res {
 				sch.Stop()
 			}
+		case error:
+			failed = true
+			log.Errorf("scheduled job %v: %v", job, result)
 		}
 	}
 }
+
+// jobName returns the tag of the scheduled job, or the method name if the tag is not specified
+func jobName(svc interface{}, method reflect.Method, ann *annotation.Annotation) string {
+	if schAnn, ok := ann.Field.Value.Interface().(at.Scheduled); ok && schAnn.AtTag != nil && *schAnn.AtTag != "" {
+		return *schAnn.AtTag
+	}
+	return reflector.IndirectType(reflect.TypeOf(svc)).String() + "." + method.Name
+}
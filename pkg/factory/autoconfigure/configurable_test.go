@@ -28,6 +28,7 @@ import (
 	"github.com/hidevopsio/hiboot/pkg/factory/autoconfigure"
 	"github.com/hidevopsio/hiboot/pkg/factory/instantiate"
 	"github.com/hidevopsio/hiboot/pkg/log"
+	"github.com/hidevopsio/hiboot/pkg/system/scheduler"
 	"github.com/hidevopsio/hiboot/pkg/utils/cmap"
 	"github.com/hidevopsio/hiboot/pkg/utils/io"
 	"github.com/stretchr/testify/assert"
@@ -377,6 +378,26 @@ func newVnsConfig() *vnsConfig {
 	return &vnsConfig{}
 }
 
+type conditionalConfiguration struct {
+	at.AutoConfiguration `value:"mars"`
+
+	at.ConditionalOnProperty `name:"mars.enabled" havingValue:"true"`
+}
+
+func newConditionalConfiguration() *conditionalConfiguration {
+	return &conditionalConfiguration{}
+}
+
+type matchIfMissingConfiguration struct {
+	at.AutoConfiguration `value:"jupiter"`
+
+	at.ConditionalOnProperty `name:"jupiter.enabled" havingValue:"true" matchIfMissing:"true"`
+}
+
+func newMatchIfMissingConfiguration() *matchIfMissingConfiguration {
+	return &matchIfMissingConfiguration{}
+}
+
 func setFactory(t *testing.T, configDir string, customProperties cmap.ConcurrentMap) factory.ConfigurableFactory {
 	io.ChangeWorkDir(os.TempDir())
 
@@ -526,6 +547,32 @@ func TestConfigurableFactory(t *testing.T) {
 	})
 }
 
+func TestConditionalConfiguration(t *testing.T) {
+	f := setFactory(t, "mercury", cmap.New())
+	_, err := f.BuildProperties()
+	assert.Equal(t, nil, err)
+
+	f.Build([]*factory.MetaData{
+		factory.NewMetaData(newConditionalConfiguration),
+		factory.NewMetaData(newMatchIfMissingConfiguration),
+	})
+
+	t.Run("should skip the configuration that the condition does not match", func(t *testing.T) {
+		assert.Equal(t, nil, f.Configuration("mars"))
+	})
+
+	t.Run("should build the configuration that matches if the property is missing", func(t *testing.T) {
+		assert.NotEqual(t, nil, f.Configuration("jupiter"))
+	})
+
+	t.Run("should report the condition outcomes", func(t *testing.T) {
+		report := f.ConditionReport()
+		assert.Equal(t, 2, len(report))
+		assert.Equal(t, false, report[0].Matched)
+		assert.Equal(t, true, report[1].Matched)
+	})
+}
+
 func TestReplacer(t *testing.T) {
 	customProperties := cmap.New()
 	customProperties.Set("app.profiles.filter", true)
@@ -611,4 +658,10 @@ func TestScheduler(t *testing.T) {
 	})
 
 	log.Infof("scheduler is done: %v", <-doneSch)
+
+	t.Run("should record scheduled job runs", func(t *testing.T) {
+		stats := scheduler.Stats()["autoconfigure_test.myService.Task1"]
+		assert.Equal(t, true, stats.Runs >= 10)
+		assert.Equal(t, uint64(0), stats.Failures)
+	})
 }
@@ -0,0 +1,105 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoconfigure
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/log"
+	"github.com/hidevopsio/hiboot/pkg/system"
+)
+
+// ErrRefreshNotSupported means that the property builder is not able to reload the config files
+var ErrRefreshNotSupported = errors.New("[factory] refresh is not supported by the property builder")
+
+var refreshMutex sync.Mutex
+
+// Refresh reloads the config files and binds the configuration properties into new instances, then drops the
+// at.RefreshScope instances so that they are rebuilt lazily with the new ones, and notifies the factory.RefreshListener
+// components. The singleton instances are not changed, as they are read concurrently without synchronization
+func (f *configurableFactory) Refresh() (changedKeys []string, err error) {
+	reloader, ok := f.builder.(system.Reloader)
+	if !ok {
+		err = ErrRefreshNotSupported
+		return
+	}
+
+	refreshMutex.Lock()
+	defer refreshMutex.Unlock()
+
+	changedKeys, err = reloader.Reload()
+	if err != nil || len(changedKeys) == 0 {
+		return
+	}
+
+	var properties []interface{}
+	rebound := make(map[reflect.Type]bool)
+	for _, md := range f.GetInstances(at.ConfigurationProperties{}) {
+		obj := md.MetaObject
+		if md.Instance != nil {
+			obj = md.Instance
+		}
+		typ := reflect.TypeOf(obj)
+		if typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct || rebound[typ] {
+			continue
+		}
+		rebound[typ] = true
+		if p := f.rebind(typ.Elem()); p != nil {
+			properties = append(properties, p)
+		}
+	}
+
+	f.ResetRefreshScope(properties...)
+
+	event := &factory.RefreshEvent{Keys: changedKeys, Properties: properties}
+	notified := make(map[uintptr]bool)
+	for _, item := range f.Items() {
+		inst := item
+		if md := factory.CastMetaData(item); md != nil {
+			inst = md.Instance
+		}
+		listener, ok := inst.(factory.RefreshListener)
+		if !ok {
+			continue
+		}
+		if iv := reflect.ValueOf(inst); iv.Kind() == reflect.Ptr {
+			if notified[iv.Pointer()] {
+				continue
+			}
+			notified[iv.Pointer()] = true
+		}
+		listener.OnRefresh(event)
+	}
+	log.Infof("configuration properties are refreshed, changed keys: %v", changedKeys)
+	return
+}
+
+// rebind binds the reloaded properties into the new instance of typ, it returns nil if it is failed to bind
+func (f *configurableFactory) rebind(typ reflect.Type) (properties interface{}) {
+	newObj := reflect.New(typ).Interface()
+	if err := f.InjectDefaultValue(newObj); err != nil {
+		log.Warn(err)
+		return
+	}
+	if err := f.builder.Load(newObj); err != nil {
+		log.Warn(err)
+		return
+	}
+	return newObj
+}
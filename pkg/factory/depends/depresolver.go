@@ -16,9 +16,12 @@
 package depends
 
 import (
+	"github.com/hidevopsio/hiboot/pkg/at"
 	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/inject/annotation"
 	"github.com/hidevopsio/hiboot/pkg/log"
 	"github.com/hidevopsio/hiboot/pkg/system/types"
+	"github.com/hidevopsio/hiboot/pkg/utils/reflector"
 	"github.com/hidevopsio/hiboot/pkg/utils/str"
 	"reflect"
 )
@@ -70,10 +73,62 @@ func (s depResolver) findDependencyIndex(depName string) int {
 	return -1
 }
 
+// findImplementations finds the indexes of the implementations of the interface that are injected,
+// they are all implementations for the slice or map, otherwise it is the one that is annotated with at.Primary
+// or the only one
+func (s depResolver) findImplementations(item *factory.MetaData, iface reflect.Type, collection bool) (indexes []int) {
+	primary := -1
+	for i, candidate := range s {
+		if candidate == item || !implements(candidate.Type, iface) {
+			continue
+		}
+		indexes = append(indexes, i)
+		if primary < 0 && isPrimary(candidate) {
+			primary = i
+		}
+	}
+	if collection {
+		return
+	}
+	if primary >= 0 {
+		return []int{primary}
+	}
+	if len(indexes) > 1 {
+		// none of them is injected as it is ambiguous
+		return nil
+	}
+	return
+}
+
 func (s depResolver) findDependencies(item *factory.MetaData) (dep []*Node, ok bool) {
+	// the dependencies that are resolved by the interface type depend on the implementations of the interface
+	resolvedByType := make(map[string]bool)
+	for _, depTyp := range item.DepTypes {
+		iface, collection, _ := factory.ParseInterfaceDep(depTyp)
+		ifaceName := reflector.GetLowerCamelFullNameByType(iface)
+		if !collection && s.findDependencyIndex(ifaceName) >= 0 {
+			continue
+		}
+		indexes := s.findImplementations(item, iface, collection)
+		if !collection && len(indexes) != 0 {
+			resolvedByType[ifaceName] = true
+			resolvedByType[str.ToLowerCamel(iface.Name())] = true
+		}
+		for _, i := range indexes {
+			if !containsNode(dep, i) {
+				item.DepMetaData = append(item.DepMetaData, s[i])
+				dep = append(dep, NewNode(i, s[i]))
+				ok = true
+			}
+		}
+	}
+
 	// iterate dependencies
 	if len(item.DepNames) > 0 {
 		for _, dp := range item.DepNames {
+			if resolvedByType[dp] {
+				continue
+			}
 			depIdx := s.findDependencyIndex(dp)
 			if depIdx >= 0 {
 				depMetaData := s[depIdx]
@@ -93,6 +148,31 @@ func (s depResolver) findDependencies(item *factory.MetaData) (dep []*Node, ok b
 	return
 }
 
+// isPrimary returns true if the component or the method that creates it is annotated with at.Primary
+func isPrimary(md *factory.MetaData) bool {
+	if md.Type != nil && annotation.Contains(md.Type, at.Primary{}) {
+		return true
+	}
+	method, ok := md.MetaObject.(reflect.Method)
+	return ok && annotation.FindMethodAnnotation(method, at.Primary{}) != nil
+}
+
+func implements(typ, iface reflect.Type) bool {
+	if typ == nil {
+		return false
+	}
+	return typ.Implements(iface) || (typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Interface && reflect.PtrTo(typ).Implements(iface))
+}
+
+func containsNode(nodes []*Node, index int) bool {
+	for _, n := range nodes {
+		if n.index == index {
+			return true
+		}
+	}
+	return false
+}
+
 // Resolve resolve dependencies
 func Resolve(data []*factory.MetaData) (result []*factory.MetaData, err error) {
 	if len(data) != 0 {
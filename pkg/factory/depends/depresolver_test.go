@@ -16,6 +16,7 @@ package depends_test
 
 import (
 	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/at"
 	"github.com/hidevopsio/hiboot/pkg/factory"
 	"github.com/hidevopsio/hiboot/pkg/factory/depends"
 	"github.com/hidevopsio/hiboot/pkg/factory/depends/bar"
@@ -318,3 +319,82 @@ func TestSort(t *testing.T) {
 		})
 	}
 }
+
+type greeter interface {
+	Greet() string
+}
+
+type englishGreeter struct {
+	at.Primary
+}
+
+func (g *englishGreeter) Greet() string {
+	return "hello"
+}
+
+type chineseGreeter struct {
+	service *greetingService
+}
+
+func (g *chineseGreeter) Greet() string {
+	return "ni hao"
+}
+
+type greetingService struct {
+	greeter greeter
+}
+
+type greetingsService struct {
+	greeters []greeter
+}
+
+func newEnglishGreeter() *englishGreeter {
+	return &englishGreeter{}
+}
+
+// newChineseGreeter depends on the greeting service that is injected with the primary greeter
+func newChineseGreeter(service *greetingService) *chineseGreeter {
+	return &chineseGreeter{service: service}
+}
+
+func newGreetingService(g greeter) *greetingService {
+	return &greetingService{greeter: g}
+}
+
+func newGreetingsService(greeters []greeter) *greetingsService {
+	return &greetingsService{greeters: greeters}
+}
+
+func TestResolveInterfaceDependencies(t *testing.T) {
+	indexOf := func(result []*factory.MetaData, object interface{}) int {
+		for i, item := range result {
+			if reflect.ValueOf(item.MetaObject).Pointer() == reflect.ValueOf(object).Pointer() {
+				return i
+			}
+		}
+		return -1
+	}
+
+	t.Run("should only depend on the primary implementation of the interface", func(t *testing.T) {
+		result, err := depends.Resolve([]*factory.MetaData{
+			factory.NewMetaData(newChineseGreeter),
+			factory.NewMetaData(newGreetingService),
+			factory.NewMetaData(newEnglishGreeter),
+		})
+		assert.Equal(t, nil, err)
+		assert.Less(t, indexOf(result, newEnglishGreeter), indexOf(result, newGreetingService))
+		assert.Less(t, indexOf(result, newGreetingService), indexOf(result, newChineseGreeter))
+	})
+
+	t.Run("should depend on all implementations of the interface for the slice", func(t *testing.T) {
+		result, err := depends.Resolve([]*factory.MetaData{
+			factory.NewMetaData(newGreetingsService),
+			factory.NewMetaData(newGreetingService),
+			factory.NewMetaData(newChineseGreeter),
+			factory.NewMetaData(newEnglishGreeter),
+		})
+		assert.Equal(t, nil, err)
+		assert.Less(t, indexOf(result, newEnglishGreeter), indexOf(result, newGreetingsService))
+		assert.Less(t, indexOf(result, newChineseGreeter), indexOf(result, newGreetingsService))
+	})
+}
@@ -40,6 +40,36 @@ type InstanceContainer interface {
 	Items() map[string]interface{}
 }
 
+// Closer is the interface of the component that should be closed when the application is shut down
+type Closer interface {
+	Close() error
+}
+
+// RefreshEvent is published to the RefreshListener components once the configuration properties are refreshed at runtime
+type RefreshEvent struct {
+	// Keys are the keys of the changed properties
+	Keys []string
+	// Properties are the configuration properties that are bound again, the singleton ones are not changed
+	Properties []interface{}
+}
+
+// RefreshListener is the interface of the component that is notified once the configuration properties are refreshed
+type RefreshListener interface {
+	OnRefresh(event *RefreshEvent)
+}
+
+// ConditionOutcome is the outcome of the condition annotation evaluated on a component or an auto configuration
+type ConditionOutcome struct {
+	// Name is the name of the component or the auto configuration
+	Name string
+	// Condition is the name of the condition annotation, e.g. ConditionalOnProperty
+	Condition string
+	// Matched reports if the condition is matched
+	Matched bool
+	// Message describes why the condition is matched or not
+	Message string
+}
+
 // InstantiateFactory instantiate factory interface
 type InstantiateFactory interface {
 	Initialized() bool
@@ -50,6 +80,7 @@ type InstantiateFactory interface {
 	Append(i ...interface{})
 	AppendComponent(c ...interface{})
 	BuildComponents() (err error)
+	DestroyComponents()
 	Builder() (builder system.Builder)
 	GetProperty(name string) interface{}
 	SetProperty(name string, value interface{}) InstantiateFactory
@@ -63,6 +94,9 @@ type InstantiateFactory interface {
 	Replace(name string) interface{}
 	InjectScopedObjects(ctx context.Context, dependencies []*MetaData, ic InstanceContainer) (instanceContainer InstanceContainer, err error)
 	InjectScopedDependencies(instanceContainer InstanceContainer, dependencies []*MetaData) (err error)
+	ResetRefreshScope(properties ...interface{})
+	MatchConditions(item *MetaData) (matched bool)
+	ConditionReport() (outcomes []*ConditionOutcome)
 }
 
 // ConfigurableFactory configurable factory interface
@@ -73,6 +107,7 @@ type ConfigurableFactory interface {
 	BuildProperties() (systemConfig *system.Configuration, err error)
 	StartSchedulers(schedulerServices []*MetaData) (schedulers []*scheduler.Scheduler)
 	Build(configs []*MetaData)
+	Refresh() (changedKeys []string, err error)
 }
 
 // Configuration configuration interface
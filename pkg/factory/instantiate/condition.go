@@ -0,0 +1,265 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instantiate
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/inject/annotation"
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+const (
+	appProfilesActive  = "app.profiles.active"
+	defaultProfileName = "default"
+)
+
+type matchFunc func(f *instantiateFactory, item *factory.MetaData, tag reflect.StructTag) (matched bool, message string)
+
+type condition struct {
+	name  string
+	att   interface{}
+	match matchFunc
+}
+
+// conditions are evaluated in order, the bean conditions are evaluated at last
+var conditions = []*condition{
+	{name: "ConditionalOnProfile", att: at.ConditionalOnProfile{}, match: matchProfile},
+	{name: "ConditionalOnProperty", att: at.ConditionalOnProperty{}, match: matchProperty},
+	{name: "ConditionalOnBean", att: at.ConditionalOnBean{}, match: matchBean},
+	{name: "ConditionalOnMissingBean", att: at.ConditionalOnMissingBean{}, match: matchMissingBean},
+}
+
+// findCondition find the condition annotation that is embedded in the object, the type that the func or method returns,
+// or the annotation parameter of the method, e.g. func (c *configuration) Store(_ struct{ at.ConditionalOnMissingBean }) Store
+func findCondition(object interface{}, att interface{}) (ann *annotation.Annotation) {
+	ann = annotation.GetAnnotation(object, att)
+	if ann == nil {
+		method, ok := object.(reflect.Method)
+		if ok {
+			ann = annotation.FindMethodAnnotation(method, att)
+		}
+	}
+	return
+}
+
+func splitValues(value string) (values []string) {
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return
+}
+
+func matchProfile(f *instantiateFactory, item *factory.MetaData, tag reflect.StructTag) (matched bool, message string) {
+	active := defaultProfileName
+	if p := f.builder.GetProperty(appProfilesActive); p != nil && fmt.Sprint(p) != "" {
+		active = fmt.Sprint(p)
+	}
+	activeProfiles := splitValues(active)
+	isActive := func(profile string) bool {
+		for _, a := range activeProfiles {
+			if a == profile {
+				return true
+			}
+		}
+		return false
+	}
+	for _, profile := range splitValues(tag.Get("value")) {
+		if strings.HasPrefix(profile, "!") {
+			matched = !isActive(profile[1:])
+		} else {
+			matched = isActive(profile)
+		}
+		if matched {
+			break
+		}
+	}
+	message = fmt.Sprintf("active profile %v, expected %v", active, tag.Get("value"))
+	return
+}
+
+func matchProperty(f *instantiateFactory, item *factory.MetaData, tag reflect.StructTag) (matched bool, message string) {
+	name, ok := tag.Lookup("name")
+	if !ok {
+		name = tag.Get("value")
+	}
+	havingValue := tag.Get("havingValue")
+	value := f.builder.GetProperty(name)
+	if value == nil {
+		matched = tag.Get("matchIfMissing") == "true"
+		message = fmt.Sprintf("property %v is missing", name)
+		return
+	}
+	actual := fmt.Sprint(value)
+	if havingValue == "" {
+		matched = !strings.EqualFold(actual, "false")
+	} else {
+		matched = strings.EqualFold(actual, havingValue)
+	}
+	message = fmt.Sprintf("property %v=%v, expected %v", name, actual, havingValue)
+	return
+}
+
+func matchBean(f *instantiateFactory, item *factory.MetaData, tag reflect.StructTag) (matched bool, message string) {
+	names := splitValues(tag.Get("value"))
+	matched = true
+	for _, name := range names {
+		if !f.hasBean(name, item) {
+			matched = false
+			message = fmt.Sprintf("bean %v is not found", name)
+			return
+		}
+	}
+	message = fmt.Sprintf("found beans %v", strings.Join(names, ","))
+	return
+}
+
+// matchMissingBean matches if none of the beans of value is found, the bean of the same name as the item or of the type
+// that the item produces is looked up if value is not specified
+func matchMissingBean(f *instantiateFactory, item *factory.MetaData, tag reflect.StructTag) (matched bool, message string) {
+	names := splitValues(tag.Get("value"))
+	if len(names) == 0 {
+		if c := f.findBeanOfType(item.Type, item); c != nil {
+			return false, fmt.Sprintf("found bean %v of type %v", c.Name, item.Type)
+		}
+		names = []string{item.Name}
+	}
+	matched = true
+	for _, name := range names {
+		if f.hasBean(name, item) {
+			matched = false
+			message = fmt.Sprintf("found bean %v", name)
+			return
+		}
+	}
+	message = fmt.Sprintf("did not find beans %v", strings.Join(names, ","))
+	return
+}
+
+// hasBean check if the bean is registered by other components that are not filtered out,
+// the name is either the full name or the short name, e.g. github.com/foo/bar.baz or bar.baz
+func (f *instantiateFactory) hasBean(name string, self *factory.MetaData) bool {
+	f.mutex.Lock()
+	for _, c := range f.components {
+		if c == self || f.unmatched[c] {
+			continue
+		}
+		if c.Name == name || path.Base(c.Name) == name {
+			f.mutex.Unlock()
+			return true
+		}
+	}
+	f.mutex.Unlock()
+	if self != nil && (self.Name == name || path.Base(self.Name) == name) {
+		return false
+	}
+	return f.instanceContainer.Get(name) != nil
+}
+
+// findBeanOfType returns the component that is not filtered out and produces typ, or implements typ if it is an interface,
+// the type of the component is the struct rather than the pointer, e.g. redisStore of func newRedisStore() *redisStore
+func (f *instantiateFactory) findBeanOfType(typ reflect.Type, self *factory.MetaData) *factory.MetaData {
+	if typ == nil || (typ.Kind() == reflect.Interface && typ.NumMethod() == 0) {
+		return nil
+	}
+	produces := func(t reflect.Type) bool {
+		return t == typ || (typ.Kind() == reflect.Interface && t.Implements(typ))
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, c := range f.components {
+		if c == self || f.unmatched[c] || c.Type == nil {
+			continue
+		}
+		if produces(c.Type) || (c.Type.Kind() == reflect.Struct && produces(reflect.PointerTo(c.Type))) {
+			return c
+		}
+	}
+	return nil
+}
+
+// MatchConditions evaluate the condition annotations of the component or the auto configuration,
+// the outcomes are recorded to the condition report
+func (f *instantiateFactory) MatchConditions(item *factory.MetaData) (matched bool) {
+	matched = true
+	if item == nil {
+		return
+	}
+	for _, c := range conditions {
+		ann := findCondition(item.MetaObject, c.att)
+		if ann == nil {
+			continue
+		}
+		ok, message := c.match(f, item, ann.Field.StructField.Tag)
+		f.mutex.Lock()
+		f.outcomes = append(f.outcomes, &factory.ConditionOutcome{
+			Name:      item.Name,
+			Condition: c.name,
+			Matched:   ok,
+			Message:   message,
+		})
+		if !ok {
+			f.unmatched[item] = true
+		}
+		f.mutex.Unlock()
+		if !ok {
+			matched = false
+			break
+		}
+	}
+	return
+}
+
+// ConditionReport returns the outcomes of the evaluated conditions
+func (f *instantiateFactory) ConditionReport() (outcomes []*factory.ConditionOutcome) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	outcomes = append(outcomes, f.outcomes...)
+	return
+}
+
+// matchComponents filter out the components that the conditions do not match
+func (f *instantiateFactory) matchComponents() (components []*factory.MetaData) {
+	for _, item := range f.components {
+		if f.MatchConditions(item) {
+			components = append(components, item)
+		}
+	}
+	f.logConditionReport()
+	return
+}
+
+func (f *instantiateFactory) logConditionReport() {
+	outcomes := f.ConditionReport()
+	if len(outcomes) == 0 {
+		return
+	}
+	log.Info("Conditions evaluation report:")
+	for _, o := range outcomes {
+		result := "matched"
+		if !o.Matched {
+			result = "did not match"
+		}
+		log.Infof("  %v %v %v: %v", o.Name, result, o.Condition, o.Message)
+	}
+}
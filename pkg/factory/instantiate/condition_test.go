@@ -0,0 +1,197 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instantiate_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/factory/instantiate"
+	"github.com/hidevopsio/hiboot/pkg/utils/cmap"
+	"github.com/stretchr/testify/assert"
+)
+
+type Greeter interface {
+	Greet() string
+}
+
+type defaultGreeter struct{}
+
+func (g *defaultGreeter) Greet() string {
+	return "default"
+}
+
+type userGreeter struct{}
+
+func (g *userGreeter) Greet() string {
+	return "user"
+}
+
+func newUserGreeter() Greeter {
+	return &userGreeter{}
+}
+
+type redisGreeter struct{}
+
+func (g *redisGreeter) Greet() string {
+	return "redis"
+}
+
+func newRedisGreeter() *redisGreeter {
+	return &redisGreeter{}
+}
+
+type greeterConfiguration struct{}
+
+func (c *greeterConfiguration) Greeter(_ struct{ at.ConditionalOnMissingBean }) Greeter {
+	return &defaultGreeter{}
+}
+
+type enabledService struct {
+	at.ConditionalOnProperty `name:"feature.enabled" havingValue:"true"`
+}
+
+type disabledService struct {
+	at.ConditionalOnProperty `name:"feature.unknown"`
+}
+
+type defaultEnabledService struct {
+	at.ConditionalOnProperty `name:"feature.unknown" matchIfMissing:"true"`
+}
+
+type devService struct {
+	at.ConditionalOnProfile `value:"dev,local"`
+}
+
+type nonDevService struct {
+	at.ConditionalOnProfile `value:"!dev"`
+}
+
+type auditService struct {
+	at.ConditionalOnBean `value:"instantiate_test.greeter"`
+}
+
+type metricsService struct {
+	at.ConditionalOnBean `value:"instantiate_test.metricsRegistry"`
+}
+
+func newConditionalFactory(components ...*factory.MetaData) factory.InstantiateFactory {
+	f := instantiate.NewInstantiateFactory(cmap.New(), components, cmap.New())
+	f.SetProperty("feature.enabled", "true")
+	f.SetProperty("app.profiles.active", "dev")
+	return f
+}
+
+func greeterMethod() reflect.Method {
+	method, _ := reflect.TypeOf(&greeterConfiguration{}).MethodByName("Greeter")
+	return method
+}
+
+func TestConditionalOnProperty(t *testing.T) {
+	f := newConditionalFactory(
+		factory.NewMetaData(new(enabledService)),
+		factory.NewMetaData(new(disabledService)),
+		factory.NewMetaData(new(defaultEnabledService)),
+	)
+	err := f.BuildComponents()
+	assert.Equal(t, nil, err)
+
+	t.Run("should register the component that the property has the expected value", func(t *testing.T) {
+		assert.NotEqual(t, nil, f.GetInstance(enabledService{}))
+	})
+
+	t.Run("should not register the component that the property is missing", func(t *testing.T) {
+		assert.Equal(t, nil, f.GetInstance(disabledService{}))
+	})
+
+	t.Run("should register the component that matches if the property is missing", func(t *testing.T) {
+		assert.NotEqual(t, nil, f.GetInstance(defaultEnabledService{}))
+	})
+}
+
+func TestConditionalOnProfile(t *testing.T) {
+	f := newConditionalFactory(
+		factory.NewMetaData(new(devService)),
+		factory.NewMetaData(new(nonDevService)),
+	)
+	err := f.BuildComponents()
+	assert.Equal(t, nil, err)
+	assert.NotEqual(t, nil, f.GetInstance(devService{}))
+	assert.Equal(t, nil, f.GetInstance(nonDevService{}))
+}
+
+func TestConditionalOnBean(t *testing.T) {
+	owner := &greeterConfiguration{}
+
+	t.Run("should back off once the user supplies the bean", func(t *testing.T) {
+		f := newConditionalFactory(
+			factory.NewMetaData(newUserGreeter),
+			factory.NewMetaData(new(auditService)),
+			factory.NewMetaData(new(metricsService)),
+		)
+		f.AppendComponent(owner, greeterMethod())
+		err := f.BuildComponents()
+		assert.Equal(t, nil, err)
+
+		greeter := f.GetInstance(new(Greeter))
+		assert.IsType(t, &userGreeter{}, greeter)
+		assert.NotEqual(t, nil, f.GetInstance(auditService{}))
+		assert.Equal(t, nil, f.GetInstance(metricsService{}))
+	})
+
+	t.Run("should back off once the user supplies the implementation of another name", func(t *testing.T) {
+		f := newConditionalFactory(
+			factory.NewMetaData(newRedisGreeter),
+		)
+		f.AppendComponent(owner, greeterMethod())
+		err := f.BuildComponents()
+		assert.Equal(t, nil, err)
+
+		assert.NotEqual(t, nil, f.GetInstance(redisGreeter{}))
+		report := f.ConditionReport()
+		assert.Equal(t, 1, len(report))
+		assert.Equal(t, false, report[0].Matched)
+		assert.Contains(t, report[0].Message, "redisGreeter")
+	})
+
+	t.Run("should register the default bean if it is missing", func(t *testing.T) {
+		f := newConditionalFactory(
+			factory.NewMetaData(new(auditService)),
+		)
+		f.AppendComponent(owner, greeterMethod())
+		err := f.BuildComponents()
+		assert.Equal(t, nil, err)
+
+		greeter := f.GetInstance(new(Greeter))
+		assert.IsType(t, &defaultGreeter{}, greeter)
+		assert.NotEqual(t, nil, f.GetInstance(auditService{}))
+	})
+
+	t.Run("should report the condition outcomes", func(t *testing.T) {
+		f := newConditionalFactory(
+			factory.NewMetaData(newUserGreeter),
+		)
+		f.AppendComponent(owner, greeterMethod())
+		err := f.BuildComponents()
+		assert.Equal(t, nil, err)
+
+		report := f.ConditionReport()
+		assert.Equal(t, 1, len(report))
+		assert.Equal(t, "ConditionalOnMissingBean", report[0].Condition)
+		assert.Equal(t, false, report[0].Matched)
+	})
+}
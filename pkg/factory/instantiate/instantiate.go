@@ -18,8 +18,10 @@ package instantiate
 import (
 	"errors"
 	"path/filepath"
+	"reflect"
 	"sync"
 
+	"github.com/hidevopsio/hiboot/pkg/aop"
 	"github.com/hidevopsio/hiboot/pkg/app/web/context"
 	"github.com/hidevopsio/hiboot/pkg/at"
 	"github.com/hidevopsio/hiboot/pkg/factory"
@@ -40,6 +42,8 @@ var (
 
 	// ErrInvalidObjectType invalid object type
 	ErrInvalidObjectType = errors.New("[factory] invalid object type")
+
+	interceptorsType = reflect.TypeOf([]aop.Interceptor{})
 )
 
 const (
@@ -61,6 +65,11 @@ type instantiateFactory struct {
 	inject                  inject.Inject
 	builder                 system.Builder
 	mutex                   sync.Mutex
+	refreshScoped           cmap.ConcurrentMap
+	refreshed               []interface{}
+	refreshMutex            sync.RWMutex
+	unmatched               map[*factory.MetaData]bool
+	outcomes                []*factory.ConditionOutcome
 }
 
 // NewInstantiateFactory the constructor of instantiateFactory
@@ -74,6 +83,8 @@ func NewInstantiateFactory(instanceMap cmap.ConcurrentMap, components []*factory
 		components:        components,
 		defaultProperties: defaultProperties,
 		categorized:       make(map[string][]*factory.MetaData),
+		refreshScoped:     cmap.New(),
+		unmatched:         make(map[*factory.MetaData]bool),
 	}
 	f.inject = inject.NewInject(f)
 
@@ -178,6 +189,13 @@ func (f *instantiateFactory) injectDependency(instanceContainer factory.Instance
 		}
 
 		if name != "" {
+			// wrap the component that is injected as the interface by its proxy
+			if item.Type != nil && item.Type.Kind() == reflect.Interface {
+				if proxy, ok := aop.NewProxy(item.Type, inst, f.GetInstances(at.Interceptor{})); ok {
+					log.Debugf("proxy %v by the interceptors", name)
+					inst = proxy
+				}
+			}
 			// save object
 			item.Instance = inst
 			// set item
@@ -187,6 +205,24 @@ func (f *instantiateFactory) injectDependency(instanceContainer factory.Instance
 	return
 }
 
+// proxyDependencies makes the components that are proxied by their interfaces depend on all the interceptors,
+// so that the interceptors are built before they are applied to the components
+func proxyDependencies(components []*factory.MetaData) []*factory.MetaData {
+	for _, item := range components {
+		if item.Type == nil || item.Type.Kind() != reflect.Interface || !aop.HasProxy(item.Type) {
+			continue
+		}
+		found := false
+		for _, depTyp := range item.DepTypes {
+			found = found || depTyp == interceptorsType
+		}
+		if !found {
+			item.DepTypes = append(item.DepTypes, interceptorsType)
+		}
+	}
+	return components
+}
+
 // InjectDependency inject dependency
 func (f *instantiateFactory) InjectDependency(instanceContainer factory.InstanceContainer, object interface{}) (err error) {
 	return f.injectDependency(instanceContainer, factory.CastMetaData(object))
@@ -197,7 +233,7 @@ func (f *instantiateFactory) BuildComponents() (err error) {
 	// first resolve the dependency graph
 	var resolved []*factory.MetaData
 	log.Debugf("Resolving dependencies")
-	resolved, err = depends.Resolve(f.components)
+	resolved, err = depends.Resolve(proxyDependencies(f.matchComponents()))
 	f.resolved = resolved
 	log.Debugf("Injecting dependencies")
 	// then build components
@@ -242,6 +278,51 @@ func (f *instantiateFactory) injectItem(item *factory.MetaData) (err error) {
 	return err
 }
 
+// DestroyComponents call the at.PreDestroy methods and Close() of all singleton components in reverse dependency order
+func (f *instantiateFactory) DestroyComponents() {
+	destroyed := make(map[uintptr]bool)
+	for i := len(f.resolved) - 1; i >= 0; i-- {
+		item := f.resolved[i]
+		if item.Scope != "" || item.Instance == nil {
+			continue
+		}
+		iv := reflect.ValueOf(item.Instance)
+		if iv.Kind() == reflect.Ptr {
+			if destroyed[iv.Pointer()] {
+				continue
+			}
+			destroyed[iv.Pointer()] = true
+		}
+		f.destroyItem(item)
+	}
+}
+
+func (f *instantiateFactory) destroyItem(item *factory.MetaData) {
+	inst := item.Instance
+	methods, annotations := annotation.FindAnnotatedMethods(inst, at.PreDestroy{})
+	for i, method := range methods {
+		log.Debugf("destroy: %v.%v()", item.Name, method.Name)
+		inputs := []reflect.Value{reflect.ValueOf(inst)}
+		if method.Type.NumIn() > 1 {
+			inputs = append(inputs, annotations[i].Parent.Value)
+		}
+		results := method.Func.Call(inputs)
+		if len(results) != 0 {
+			if err, ok := results[len(results)-1].Interface().(error); ok && err != nil {
+				log.Errorf("failed to destroy %v: %v", item.Name, err)
+			}
+		}
+	}
+
+	closer, ok := inst.(factory.Closer)
+	if ok {
+		log.Debugf("close: %v", item.Name)
+		if err := closer.Close(); err != nil {
+			log.Errorf("failed to close %v: %v", item.Name, err)
+		}
+	}
+}
+
 // SetInstance save instanceContainer
 func (f *instantiateFactory) SetInstance(params ...interface{}) (err error) {
 	f.mutex.Lock()
@@ -376,6 +457,13 @@ func (f *instantiateFactory) InjectScopedDependencies(instanceContainer factory.
 				return
 			}
 		}
+		if d.Scope == factory.ScopeRefresh {
+			err = f.injectRefreshScoped(instanceContainer, d)
+			if err != nil {
+				return
+			}
+			continue
+		}
 		if d.Scope != "" {
 			// making sure that the scoped instanceContainer does not exist before the dependency injection
 			if instanceContainer.Get(d.Name) == nil || d.Scope == factory.ScopePrototype {
@@ -390,6 +478,73 @@ func (f *instantiateFactory) InjectScopedDependencies(instanceContainer factory.
 	return
 }
 
+// injectRefreshScoped reuses the at.RefreshScope instance that is built after last refresh, or builds a new one.
+// As the instance outlives the request, it is built from the singletons and the refreshed configuration properties
+// rather than the request scoped instances
+func (f *instantiateFactory) injectRefreshScoped(instanceContainer factory.InstanceContainer, d *factory.MetaData) (err error) {
+	f.refreshMutex.RLock()
+	defer f.refreshMutex.RUnlock()
+
+	inst, ok := f.refreshScoped.Get(d.Name)
+	if !ok {
+		ic := newInstanceContainer(nil)
+		for _, p := range f.refreshed {
+			_ = ic.Set(p)
+		}
+		for name, item := range f.refreshScoped.Items() {
+			_ = ic.Set(name, item)
+		}
+		item := factory.CloneMetaData(d)
+		if item.Kind == types.Method {
+			item.ObjectOwner = withProperties(ic, item.ObjectOwner)
+		}
+		err = f.InjectDependency(ic, item)
+		if err != nil {
+			return
+		}
+		if inst = ic.Get(d.Name); inst == nil {
+			return
+		}
+		// reuse the instance that is built by the concurrent request if there is
+		f.refreshScoped.SetIfAbsent(d.Name, inst)
+		inst, _ = f.refreshScoped.Get(d.Name)
+	}
+	return instanceContainer.Set(d.Name, inst)
+}
+
+// withProperties returns the copy of the owner of the method whose configuration properties are replaced by
+// the refreshed ones, the owner itself is not changed as it may be used concurrently
+func withProperties(ic factory.InstanceContainer, owner interface{}) interface{} {
+	ov := reflect.ValueOf(owner)
+	if ov.Kind() != reflect.Ptr || ov.Elem().Kind() != reflect.Struct {
+		return owner
+	}
+	clone := reflect.New(ov.Elem().Type())
+	clone.Elem().Set(ov.Elem())
+	for _, field := range reflector.DeepFields(ov.Elem().Type()) {
+		if !annotation.Contains(field.Type, at.ConfigurationProperties{}) {
+			continue
+		}
+		fv := clone.Elem().FieldByName(field.Name)
+		if p := ic.Get(field.Type); p != nil && fv.CanSet() && reflect.TypeOf(p).AssignableTo(fv.Type()) {
+			fv.Set(reflect.ValueOf(p))
+		}
+	}
+	return clone.Interface()
+}
+
+// ResetRefreshScope drops the at.RefreshScope instances, so that they are rebuilt with the refreshed configuration
+// properties on next injection
+func (f *instantiateFactory) ResetRefreshScope(properties ...interface{}) {
+	f.refreshMutex.Lock()
+	defer f.refreshMutex.Unlock()
+
+	f.refreshed = properties
+	for _, name := range f.refreshScoped.Keys() {
+		f.refreshScoped.Remove(name)
+	}
+}
+
 // InjectScopedObjects inject context aware objects
 func (f *instantiateFactory) InjectScopedObjects(ctx context.Context, dps []*factory.MetaData, ic factory.InstanceContainer) (instanceContainer factory.InstanceContainer, err error) {
 	log.Debugf(">>> InjectScopedObjects(%x) ...", &ctx)
@@ -424,3 +424,43 @@ func TestRuntimeInstance(t *testing.T) {
 		assert.NotEqual(t, nil, ri.Get(scopedMethodObject{}))
 	}
 }
+
+var destroyed []string
+
+type destroyableRepository struct {
+}
+
+func newDestroyableRepository() *destroyableRepository {
+	return &destroyableRepository{}
+}
+
+func (r *destroyableRepository) Close() error {
+	destroyed = append(destroyed, "repository")
+	return nil
+}
+
+type destroyableService struct {
+	repository *destroyableRepository
+}
+
+func newDestroyableService(repository *destroyableRepository) *destroyableService {
+	return &destroyableService{repository: repository}
+}
+
+func (s *destroyableService) Destroy(_ struct{ at.PreDestroy }) {
+	destroyed = append(destroyed, "service")
+}
+
+func TestDestroyComponents(t *testing.T) {
+	destroyed = nil
+	testComponents := []*factory.MetaData{
+		factory.NewMetaData(newDestroyableService),
+		factory.NewMetaData(newDestroyableRepository),
+	}
+	instFactory := instantiate.NewInstantiateFactory(cmap.New(), testComponents, cmap.New())
+	err := instFactory.BuildComponents()
+	assert.Equal(t, nil, err)
+
+	instFactory.DestroyComponents()
+	assert.Equal(t, []string{"service", "repository"}, destroyed)
+}
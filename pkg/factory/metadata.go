@@ -15,6 +15,7 @@ const (
 	ScopeSingleton = "singleton"
 	ScopePrototype = "prototype"
 	ScopeRequest   = "request"
+	ScopeRefresh   = "refresh"
 )
 
 // MetaData is the injectable object meta data
@@ -29,6 +30,7 @@ type MetaData struct {
 	MetaObject  interface{}
 	Type        reflect.Type
 	DepNames    []string
+	DepTypes    []reflect.Type
 	DepMetaData []*MetaData
 	Scope       string
 	BeforeInit  bool
@@ -82,6 +84,9 @@ func parseDependencies(object interface{}, kind string, typ reflect.Type) (deps
 		numIn := fn.Type().NumIn()
 		for i := 0; i < numIn; i++ {
 			inTyp := fn.Type().In(i)
+			if _, collection, ok := ParseInterfaceDep(inTyp); ok && collection {
+				continue
+			}
 			depNames = appendDep(depNames, findDep(typ, inTyp))
 		}
 	case types.Method:
@@ -91,6 +96,8 @@ func parseDependencies(object interface{}, kind string, typ reflect.Type) (deps
 			inTyp := method.Type.In(i)
 			if annotation.IsAnnotation(inTyp) {
 				log.Debugf("%v is annotation", inTyp.Name())
+			} else if _, collection, ok := ParseInterfaceDep(inTyp); ok && collection {
+				continue
 			} else {
 				depNames = appendDep(depNames, findDep(typ, inTyp))
 			}
@@ -99,6 +106,9 @@ func parseDependencies(object interface{}, kind string, typ reflect.Type) (deps
 		// find user specific inject tag
 		for _, field := range reflector.DeepFields(typ) {
 			tag, ok := field.Tag.Lookup("inject")
+			if _, collection, isInterface := ParseInterfaceDep(field.Type); isInterface && collection {
+				continue
+			}
 			if ok {
 				name := tag
 				if name == "" {
@@ -124,6 +134,63 @@ func parseDependencies(object interface{}, kind string, typ reflect.Type) (deps
 	return
 }
 
+// ParseInterfaceDep parses the dependency that is resolved by the interface type, it returns the interface type and
+// reports if all implementations are injected as a slice or a map, e.g. []HealthService or map[string]HealthService
+func ParseInterfaceDep(typ reflect.Type) (iface reflect.Type, collection bool, ok bool) {
+	iface = typ
+	switch typ.Kind() {
+	case reflect.Slice:
+		iface, collection = typ.Elem(), true
+	case reflect.Map:
+		if typ.Key().Kind() != reflect.String {
+			return
+		}
+		iface, collection = typ.Elem(), true
+	}
+	// the empty interface is implemented by all instances, it is not resolved by type
+	ok = iface.Kind() == reflect.Interface && iface.NumMethod() > 0
+	return
+}
+
+// parseDepTypes find the dependencies that are resolved by the interface type,
+// the slice or map of interface is the dependency of all implementations of the interface
+func parseDepTypes(object interface{}, kind string, typ reflect.Type) (depTypes []reflect.Type) {
+	var inTypes []reflect.Type
+	switch kind {
+	case types.Func:
+		fnTyp := reflect.TypeOf(object)
+		for i := 0; i < fnTyp.NumIn(); i++ {
+			inTypes = append(inTypes, fnTyp.In(i))
+		}
+	case types.Method:
+		method := object.(reflect.Method)
+		for i := 1; i < method.Type.NumIn(); i++ {
+			inTypes = append(inTypes, method.Type.In(i))
+		}
+	default:
+		if typ == nil {
+			return
+		}
+		typ = reflector.IndirectType(typ)
+		if typ.Kind() != reflect.Struct {
+			return
+		}
+		_, autoWired := reflector.GetEmbeddedFieldByType(typ, at.AutoWired{}, reflect.Struct)
+		for _, field := range reflector.DeepFields(typ) {
+			_, hasTag := field.Tag.Lookup("inject")
+			if autoWired || hasTag {
+				inTypes = append(inTypes, field.Type)
+			}
+		}
+	}
+	for _, inTyp := range inTypes {
+		if _, _, ok := ParseInterfaceDep(inTyp); ok {
+			depTypes = append(depTypes, inTyp)
+		}
+	}
+	return
+}
+
 func getFullName(object interface{}, n string) (name string) {
 	name = n
 	if object != nil {
@@ -272,6 +339,7 @@ func NewMetaData(params ...interface{}) (metaData *MetaData) {
 			MetaObject:  metaObject,
 			Type:        typ,
 			DepNames:    deps,
+			DepTypes:    parseDepTypes(metaObject, kindName, typ),
 			Scope:       scope,
 			BeforeInit:  beforeInit,
 			AfterInit:   afterInit,
@@ -295,6 +363,7 @@ func CloneMetaData(src *MetaData) (dst *MetaData) {
 		MetaObject:  src.MetaObject,
 		Type:        src.Type,
 		DepNames:    src.DepNames,
+		DepTypes:    src.DepTypes,
 		DepMetaData: src.DepMetaData,
 		Scope:       src.Scope,
 		BeforeInit:  src.BeforeInit,
@@ -59,7 +59,8 @@ func IsAnnotation(object interface{}) (yes bool) {
 		for i := 0; i < numField; i++ {
 			v := typ.Field(i)
 			if v.Anonymous {
-				if v.Type == annTyp {
+				// the anonymous struct that embeds annotation is annotation too, e.g. struct{ at.ConditionalOnMissingBean }
+				if v.Type == annTyp || (typ.Name() == "" && IsAnnotation(v.Type)) {
 					yes = true
 					break
 				}
@@ -292,7 +293,10 @@ func addTags(tags *structtag.Tags, typ reflect.Type) {
 			if e == nil {
 				t, e := tgs.Get(atTag.Name)
 				if e == nil {
-					_ = tags.Set(t)
+					// default value must not override the value that is specified by user
+					if _, ne := tags.Get(atTag.Name); ne != nil {
+						_ = tags.Set(t)
+					}
 				}
 			}
 		}
@@ -387,8 +391,8 @@ func FindMethodAnnotation(method reflect.Method, att interface{}) (annotation *A
 	numIn := method.Type.NumIn()
 	for n := 1; n < numIn; n++ {
 		typ := method.Type.In(n)
-		iTyp := reflector.IndirectType(typ)
-		if iTyp.Kind() == reflect.Struct {
+		// the annotation parameter is a struct value, e.g. _ struct{ at.PreDestroy }
+		if typ.Kind() == reflect.Struct {
 			av := reflect.New(typ)
 			avo := av.Interface()
 			ma := GetAnnotations(avo)
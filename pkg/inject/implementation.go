@@ -0,0 +1,136 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/inject/annotation"
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+// lowestPrecedence is the order of the implementation without at.Order
+const lowestPrecedence = math.MaxInt32
+
+type implementation struct {
+	name     string
+	instance interface{}
+	order    int
+	primary  bool
+}
+
+// findAnnotation find the annotation of the instance, or the annotation parameter of the method that creates the instance
+func findAnnotation(md *factory.MetaData, att interface{}) (ann *annotation.Annotation) {
+	ann = annotation.GetAnnotation(md.Instance, att)
+	if ann == nil {
+		method, ok := md.MetaObject.(reflect.Method)
+		if ok {
+			ann = annotation.FindMethodAnnotation(method, att)
+		}
+	}
+	return
+}
+
+// findImplementations find the instances that implement the interface, they are sorted by at.Order, then by name
+func (i *inject) findImplementations(instanceContainer factory.InstanceContainer, iface reflect.Type) (impls []*implementation) {
+	items := make(map[string]interface{})
+	for name, item := range i.factory.Items() {
+		items[name] = item
+	}
+	if instanceContainer != nil {
+		for name, item := range instanceContainer.Items() {
+			items[name] = item
+		}
+	}
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	seen := make(map[uintptr]bool)
+	for _, name := range names {
+		md := factory.CastMetaData(items[name])
+		if md == nil || md.Instance == nil || !reflect.TypeOf(md.Instance).Implements(iface) {
+			continue
+		}
+		// the same instance may be saved with different names
+		iv := reflect.ValueOf(md.Instance)
+		if iv.Kind() == reflect.Ptr {
+			if seen[iv.Pointer()] {
+				continue
+			}
+			seen[iv.Pointer()] = true
+		}
+		impl := &implementation{
+			name:     name,
+			instance: md.Instance,
+			order:    lowestPrecedence,
+			primary:  findAnnotation(md, at.Primary{}) != nil,
+		}
+		if ann := findAnnotation(md, at.Order{}); ann != nil {
+			order, err := strconv.Atoi(ann.Field.StructField.Tag.Get("value"))
+			if err == nil {
+				impl.order = order
+			}
+		}
+		impls = append(impls, impl)
+	}
+	sort.SliceStable(impls, func(a, b int) bool {
+		return impls[a].order < impls[b].order
+	})
+	return
+}
+
+// resolveCollection creates the slice or the map of all implementations of the interface, the key of the map is the instance name
+func (i *inject) resolveCollection(instanceContainer factory.InstanceContainer, typ, iface reflect.Type) (val reflect.Value) {
+	impls := i.findImplementations(instanceContainer, iface)
+	if typ.Kind() == reflect.Map {
+		val = reflect.MakeMapWithSize(typ, len(impls))
+		for _, impl := range impls {
+			val.SetMapIndex(reflect.ValueOf(impl.name).Convert(typ.Key()), reflect.ValueOf(impl.instance))
+		}
+		return
+	}
+	val = reflect.MakeSlice(typ, 0, len(impls))
+	for _, impl := range impls {
+		val = reflect.Append(val, reflect.ValueOf(impl.instance))
+	}
+	return
+}
+
+// resolveImplementation resolves the implementation of the interface that is not saved with the name of the interface,
+// the implementation that is annotated with at.Primary is preferred if there are more than one implementations
+func (i *inject) resolveImplementation(instanceContainer factory.InstanceContainer, iface reflect.Type) (inst interface{}) {
+	impls := i.findImplementations(instanceContainer, iface)
+	for _, impl := range impls {
+		if impl.primary {
+			return impl.instance
+		}
+	}
+	switch len(impls) {
+	case 0:
+	case 1:
+		inst = impls[0].instance
+	default:
+		log.Warnf("[inject] %v has %v implementations, one of them should be annotated with at.Primary", iface, len(impls))
+	}
+	return
+}
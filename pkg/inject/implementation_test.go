@@ -0,0 +1,125 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject_test
+
+import (
+	"path"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/factory/instantiate"
+	"github.com/hidevopsio/hiboot/pkg/utils/cmap"
+	"github.com/stretchr/testify/assert"
+)
+
+type Plugin interface {
+	Name() string
+}
+
+type alphaPlugin struct {
+	at.Order `value:"2"`
+}
+
+func (p *alphaPlugin) Name() string {
+	return "alpha"
+}
+
+type betaPlugin struct {
+	at.Order `value:"1"`
+}
+
+func (p *betaPlugin) Name() string {
+	return "beta"
+}
+
+type gammaPlugin struct {
+	at.Primary
+}
+
+func (p *gammaPlugin) Name() string {
+	return "gamma"
+}
+
+type pluginRegistry struct {
+	plugins []Plugin
+	byName  map[string]Plugin
+	primary Plugin
+}
+
+func newPluginRegistry(plugins []Plugin, byName map[string]Plugin, primary Plugin) *pluginRegistry {
+	return &pluginRegistry{plugins: plugins, byName: byName, primary: primary}
+}
+
+type pluginHolder struct {
+	Plugins []Plugin `inject:""`
+	Primary Plugin   `inject:""`
+}
+
+func pluginNames(plugins []Plugin) (names []string) {
+	for _, p := range plugins {
+		names = append(names, p.Name())
+	}
+	return
+}
+
+func TestInjectImplementations(t *testing.T) {
+	holder := new(pluginHolder)
+	// the consumers are registered before the implementations
+	f := instantiate.NewInstantiateFactory(cmap.New(), []*factory.MetaData{
+		factory.NewMetaData(newPluginRegistry),
+		factory.NewMetaData(holder),
+		factory.NewMetaData(new(gammaPlugin)),
+		factory.NewMetaData(new(alphaPlugin)),
+		factory.NewMetaData(new(betaPlugin)),
+	}, cmap.New())
+	err := f.BuildComponents()
+	assert.Equal(t, nil, err)
+
+	registry := f.GetInstance(pluginRegistry{}).(*pluginRegistry)
+
+	t.Run("should inject all implementations into slice sorted by at.Order", func(t *testing.T) {
+		assert.Equal(t, []string{"beta", "alpha", "gamma"}, pluginNames(registry.plugins))
+	})
+
+	t.Run("should inject all implementations into map by name", func(t *testing.T) {
+		assert.Equal(t, 3, len(registry.byName))
+		for name, p := range registry.byName {
+			assert.Equal(t, "inject_test."+p.Name()+"Plugin", path.Base(name))
+		}
+	})
+
+	t.Run("should inject the implementation that is annotated with at.Primary", func(t *testing.T) {
+		assert.Equal(t, "gamma", registry.primary.Name())
+	})
+
+	t.Run("should inject all implementations into the tagged field", func(t *testing.T) {
+		assert.Equal(t, []string{"beta", "alpha", "gamma"}, pluginNames(holder.Plugins))
+		assert.Equal(t, "gamma", holder.Primary.Name())
+	})
+}
+
+func TestInjectSingleImplementation(t *testing.T) {
+	f := instantiate.NewInstantiateFactory(cmap.New(), []*factory.MetaData{
+		factory.NewMetaData(newPluginRegistry),
+		factory.NewMetaData(new(alphaPlugin)),
+	}, cmap.New())
+	err := f.BuildComponents()
+	assert.Equal(t, nil, err)
+
+	registry := f.GetInstance(pluginRegistry{}).(*pluginRegistry)
+	assert.Equal(t, "alpha", registry.primary.Name())
+	assert.Equal(t, []string{"alpha"}, pluginNames(registry.plugins))
+}
@@ -195,6 +195,11 @@ func (i *inject) IntoObjectValue(instance factory.InstanceContainer, object refl
 		targetTags = tagsContainer
 	}
 
+	var autoWired bool
+	if obj.IsValid() {
+		_, autoWired = reflector.GetEmbeddedFieldByType(obj.Type(), at.AutoWired{}, reflect.Struct)
+	}
+
 	// field injection
 	for _, f := range reflector.DeepFields(object.Type()) {
 		var injectedObject interface{}
@@ -233,16 +238,29 @@ func (i *inject) IntoObjectValue(instance factory.InstanceContainer, object refl
 			fieldObjValue = obj.FieldByName(f.Name)
 		}
 
-		// TODO: assume that the f.Name of value and inject tag is not the same
-		injectedObject = i.getInstance(instance, f.Type)
-		if injectedObject == nil {
-			for _, tagImpl := range targetTags {
-				tagImpl.Init(i.factory)
-				injectedObject = tagImpl.Decode(object, f, prop)
-				if injectedObject != nil {
-					break
+		// the interface fields of at.AutoWired object or the field with inject tag are resolved by type
+		iface, collection, isInterface := factory.ParseInterfaceDep(f.Type)
+		_, hasInjectTag := f.Tag.Lookup("inject")
+		byType := isInterface && (autoWired || hasInjectTag)
+
+		if byType && collection {
+			// inject all implementations into []I or map[string]I
+			injectedObject = i.resolveCollection(instance, f.Type, iface).Interface()
+		} else {
+			// TODO: assume that the f.Name of value and inject tag is not the same
+			injectedObject = i.getInstance(instance, f.Type)
+			if injectedObject == nil {
+				for _, tagImpl := range targetTags {
+					tagImpl.Init(i.factory)
+					injectedObject = tagImpl.Decode(object, f, prop)
+					if injectedObject != nil {
+						break
+					}
 				}
 			}
+			if injectedObject == nil && byType {
+				injectedObject = i.resolveImplementation(instance, iface)
+			}
 		}
 
 		// assign value to struct field
@@ -276,8 +294,17 @@ func (i *inject) IntoObjectValue(instance factory.InstanceContainer, object refl
 }
 
 func (i *inject) parseFuncOrMethodInput(instance factory.InstanceContainer, inType reflect.Type) (paramValue reflect.Value, ok bool) {
+	iface, collection, isInterface := factory.ParseInterfaceDep(inType)
+	if isInterface && collection {
+		paramValue = i.resolveCollection(instance, inType, iface)
+		ok = true
+		return
+	}
 	inType = reflector.IndirectType(inType)
 	inst := i.getInstance(instance, inType)
+	if inst == nil && isInterface {
+		inst = i.resolveImplementation(instance, iface)
+	}
 	ok = true
 	if inst == nil {
 		//log.Debug(inType.Kind())
@@ -368,7 +395,7 @@ func (i *inject) IntoMethod(instance factory.InstanceContainer, object interface
 				if ok {
 					inputs[n] = val
 				} else {
-					if reflect.TypeOf(at.AllowNil{}) == ann || annotation.Contains(ann, at.AllowNil{}) {
+					if annotation.IsAnnotation(fnInType) || reflect.TypeOf(at.AllowNil{}) == ann || annotation.Contains(ann, at.AllowNil{}) {
 						inputs[n] = reflect.Zero(fnInType)
 					} else {
 						err = fmt.Errorf("[IntoMethod] %v.%v(%v:%v) is not injected", reflector.GetLowerCamelFullName(object), method.Name, n, reflector.GetLowerCamelFullNameByType(fnInType))
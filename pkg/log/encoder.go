@@ -0,0 +1,110 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TextFormat is the log format of text line
+	TextFormat = "text"
+	// JSONFormat is the log format of json line
+	JSONFormat = "json"
+)
+
+// Entry is a single structured log entry
+type Entry struct {
+	Time    time.Time
+	Level   string
+	Logger  string
+	Message string
+	Fields  []Field
+}
+
+// Encoder encodes the log entry to a single line
+type Encoder interface {
+	Encode(buf *bytes.Buffer, entry *Entry)
+}
+
+// TextEncoder encodes the entry as text line, e.g. [INFO] [2006-01-02 15:04:05.000] name: message key=value
+type TextEncoder struct {
+	// TimeFormat is the layout of the time, the time is omitted if it is empty
+	TimeFormat string
+}
+
+// Encode encodes the entry as text line
+func (e *TextEncoder) Encode(buf *bytes.Buffer, entry *Entry) {
+	buf.WriteString("[" + strings.ToUpper(entry.Level) + "]")
+	if e.TimeFormat != "" {
+		buf.WriteString(" " + entry.Time.Format(e.TimeFormat))
+	}
+	buf.WriteByte(' ')
+	if entry.Logger != "" {
+		buf.WriteString(entry.Logger + ": ")
+	}
+	buf.WriteString(entry.Message)
+	for _, f := range entry.Fields {
+		s := fmt.Sprintf("%v", fieldValue(f.Value))
+		if s == "" || strings.ContainsAny(s, " =\"\t\n") {
+			s = strconv.Quote(s)
+		}
+		buf.WriteString(" " + f.Key + "=" + s)
+	}
+	buf.WriteByte('\n')
+}
+
+// JSONEncoder encodes the entry as json line, e.g. {"time":"...","level":"info","logger":"name","msg":"message","key":"value"}
+type JSONEncoder struct {
+}
+
+// Encode encodes the entry as json line, the fields are encoded in order
+func (e *JSONEncoder) Encode(buf *bytes.Buffer, entry *Entry) {
+	buf.WriteString(`{"time":`)
+	writeJSON(buf, entry.Time.Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSON(buf, entry.Level)
+	if entry.Logger != "" {
+		buf.WriteString(`,"logger":`)
+		writeJSON(buf, entry.Logger)
+	}
+	buf.WriteString(`,"msg":`)
+	writeJSON(buf, entry.Message)
+	for _, f := range entry.Fields {
+		buf.WriteByte(',')
+		writeJSON(buf, f.Key)
+		buf.WriteByte(':')
+		writeJSON(buf, fieldValue(f.Value))
+	}
+	buf.WriteString("}\n")
+}
+
+func writeJSON(buf *bytes.Buffer, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("%v", v))
+	}
+	buf.Write(b)
+}
+
+var bufferPool = sync.Pool{New: func() interface{} {
+	return new(bytes.Buffer)
+}}
@@ -0,0 +1,111 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"fmt"
+	"time"
+)
+
+const badKey = "!BADKEY"
+
+// Field is a typed key value pair of the structured log
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// String returns the string field
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int returns the int field
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 returns the int64 field
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 returns the float64 field
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool returns the bool field
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration returns the duration field, it is encoded as the duration string, e.g. 1.5s
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Time returns the time field, it is encoded in RFC3339 format
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err returns the error field with key "error"
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// Any returns the field of any value
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// toFields converts the args to fields, the args can be Field or the alternating key value pairs,
+// e.g. With("user", "john", log.Int("age", 18))
+func toFields(args []interface{}) (fields []Field) {
+	for i := 0; i < len(args); i++ {
+		switch a := args[i].(type) {
+		case Field:
+			fields = append(fields, a)
+		case []Field:
+			fields = append(fields, a...)
+		case string:
+			if i+1 < len(args) {
+				fields = append(fields, Field{Key: a, Value: args[i+1]})
+				i++
+			} else {
+				fields = append(fields, Field{Key: badKey, Value: a})
+			}
+		default:
+			fields = append(fields, Field{Key: badKey, Value: a})
+		}
+	}
+	return
+}
+
+// fieldValue returns the value that is suitable for encoding
+func fieldValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case error:
+		return val.Error()
+	case time.Duration:
+		return val.String()
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return val.String()
+	}
+	return v
+}
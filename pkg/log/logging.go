@@ -96,11 +96,25 @@ func NewLine(newLineChar string) {
 // Reset re-sets the default logger to an empty one.
 func Reset() {
 	golog.Reset()
+	golog.Handle(handleLog)
 }
 
 // SetOutput overrides the golog.Logger's Printer's output with another `io.Writer`.
 func SetOutput(w io.Writer) {
 	golog.SetOutput(w)
+	setOutput(w)
+}
+
+// Writer returns the writer that writes through the output of the logs, it follows the output that is set by
+// SetOutput or AddOutput afterwards, e.g. the log file
+func Writer() io.Writer {
+	return writerFunc(writeOutput)
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (w writerFunc) Write(p []byte) (int, error) {
+	return w(p)
 }
 
 // AddOutput adds one or more `io.Writer` to the golog.Logger's Printer.
@@ -109,6 +123,10 @@ func SetOutput(w io.Writer) {
 // then colors will be disabled for all outputs.
 func AddOutput(writers ...io.Writer) {
 	golog.AddOutput(writers...)
+	mu.RLock()
+	w := output
+	mu.RUnlock()
+	setOutput(append([]io.Writer{w}, writers...)...)
 }
 
 // SetPrefix sets a prefix for the default package-level Logger.
@@ -145,6 +163,14 @@ func SetLevel(levelName string) {
 	golog.SetLevel(levelName)
 }
 
+// GetLevel returns the name of current log level, e.g. "info"
+func GetLevel() string {
+	if meta, ok := golog.Levels[golog.Default.Level]; ok {
+		return meta.Name
+	}
+	return Disable
+}
+
 // Print prints a log message without levels and colors.
 func Print(v ...interface{}) {
 	golog.Print(v...)
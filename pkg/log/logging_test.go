@@ -94,6 +94,13 @@ func TestLogging(t *testing.T) {
 		SetLevel(DebugLevel)
 		Debug("testing ...")
 	})
+	t.Run("should get log level", func(t *testing.T) {
+		SetLevel(WarnLevel)
+		if GetLevel() != WarnLevel {
+			t.Errorf("expected %v, got %v", WarnLevel, GetLevel())
+		}
+		SetLevel(DebugLevel)
+	})
 	t.Run("should pass log.Debugf() test", func(t *testing.T) {
 		SetLevel(DebugLevel)
 		Debugf("testing %v", "...")
@@ -0,0 +1,253 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	compressSuffix   = ".gz"
+	megabyte         = 1024 * 1024
+	cleanupInterval  = time.Hour
+)
+
+// RotateFile is the log file that is rotated once it reaches the max size,
+// the backups are named as name-2006-01-02T15-04-05.000.ext and removed by max age and max backups,
+// the backups are cleaned up on start, on rotation and hourly if max age is set
+type RotateFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+	file       *os.File
+	size       int64
+	cleaning   sync.Mutex
+	stop       chan struct{}
+}
+
+// NewRotateFile opens the log file, maxSize is in megabytes, maxAge is in days,
+// the file is never rotated if maxSize is 0, and the backups are kept forever if maxAge and maxBackups are 0
+func NewRotateFile(path string, maxSize int, maxAge int, maxBackups int, compress bool) (f *RotateFile, err error) {
+	f = &RotateFile{
+		path:       path,
+		maxSize:    int64(maxSize) * megabyte,
+		maxAge:     time.Duration(maxAge) * 24 * time.Hour,
+		maxBackups: maxBackups,
+		compress:   compress,
+	}
+	if err = f.open(); err != nil {
+		f = nil
+		return
+	}
+	if f.maxAge > 0 || f.maxBackups > 0 || f.compress {
+		go f.cleanup()
+	}
+	// the backups expire even if the log file is never rotated
+	if f.maxAge > 0 {
+		f.stop = make(chan struct{})
+		go f.cleanupEvery(cleanupInterval, f.stop)
+	}
+	return
+}
+
+// Path returns the path of the log file
+func (f *RotateFile) Path() string {
+	return f.path
+}
+
+// Write writes to the log file, the file is rotated before writing if the max size is reached
+func (f *RotateFile) Write(p []byte) (n int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		if err = f.open(); err != nil {
+			return
+		}
+	}
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err = f.rotate(); err != nil {
+			return
+		}
+	}
+	n, err = f.file.Write(p)
+	f.size += int64(n)
+	return
+}
+
+// Rotate renames current log file to the backup and opens a new one
+func (f *RotateFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotate()
+}
+
+// Reopen closes and reopens the log file, it is used when the file is moved by the external tools, e.g. on SIGHUP
+func (f *RotateFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.close()
+	return f.open()
+}
+
+// Close closes the log file and stops the periodic cleanup
+func (f *RotateFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.stop != nil {
+		close(f.stop)
+		f.stop = nil
+	}
+	return f.close()
+}
+
+func (f *RotateFile) open() (err error) {
+	if err = os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return
+	}
+	f.file, f.size = file, info.Size()
+	return
+}
+
+func (f *RotateFile) close() (err error) {
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	return
+}
+
+func (f *RotateFile) rotate() (err error) {
+	if err = f.close(); err != nil {
+		return
+	}
+	if err = os.Rename(f.path, f.backupName(time.Now())); err != nil && !os.IsNotExist(err) {
+		return
+	}
+	if err = f.open(); err != nil {
+		return
+	}
+	go f.cleanup()
+	return
+}
+
+func (f *RotateFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	return strings.TrimSuffix(f.path, ext) + "-" + t.Format(backupTimeFormat) + ext
+}
+
+type backup struct {
+	path string
+	time time.Time
+}
+
+// backups returns the backups of the log file, the newest first
+func (f *RotateFile) backups() (backups []backup) {
+	dir := filepath.Dir(f.path)
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(filepath.Base(f.path), ext) + "-"
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), compressSuffix)
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		t, err := time.ParseInLocation(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext), time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, e.Name()), time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+	return
+}
+
+// cleanup removes the backups that exceed max backups or max age, then compresses the rest if it is enabled
+func (f *RotateFile) cleanup() {
+	f.cleaning.Lock()
+	defer f.cleaning.Unlock()
+	for i, b := range f.backups() {
+		if (f.maxBackups > 0 && i >= f.maxBackups) || (f.maxAge > 0 && time.Since(b.time) > f.maxAge) {
+			_ = os.Remove(b.path)
+			continue
+		}
+		if f.compress && !strings.HasSuffix(b.path, compressSuffix) {
+			if err := compressFile(b.path); err != nil {
+				Errorf("failed to compress log file %v: %v", b.path, err)
+			}
+		}
+	}
+}
+
+func (f *RotateFile) cleanupEvery(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			f.cleanup()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err == nil {
+		err = gz.Close()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(path + compressSuffix)
+		return
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,128 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateFile(t *testing.T) {
+	t.Run("should rotate by max size and keep max backups", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "logs", "app.log")
+		f, err := NewRotateFile(path, 0, 0, 2, false)
+		assert.Equal(t, nil, err)
+		defer f.Close()
+		f.maxSize = 10
+
+		for i := 0; i < 4; i++ {
+			_, err = f.Write([]byte("0123456789"))
+			assert.Equal(t, nil, err)
+			// the backups are named by milliseconds
+			time.Sleep(2 * time.Millisecond)
+		}
+		f.cleanup()
+
+		b, _ := os.ReadFile(path)
+		assert.Equal(t, "0123456789", string(b))
+		assert.Equal(t, 2, len(f.backups()))
+	})
+
+	t.Run("should compress backups", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		f, err := NewRotateFile(path, 0, 0, 0, true)
+		assert.Equal(t, nil, err)
+		defer f.Close()
+
+		_, _ = f.Write([]byte("compressed"))
+		assert.Equal(t, nil, f.Rotate())
+		f.cleanup()
+
+		backups := f.backups()
+		assert.Equal(t, true, strings.HasSuffix(backups[0].path, compressSuffix))
+		gf, err := os.Open(backups[0].path)
+		assert.Equal(t, nil, err)
+		defer gf.Close()
+		gz, err := gzip.NewReader(gf)
+		assert.Equal(t, nil, err)
+		b, _ := io.ReadAll(gz)
+		assert.Equal(t, "compressed", string(b))
+	})
+
+	t.Run("should remove backups by max age", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		f, err := NewRotateFile(path, 0, 1, 0, false)
+		assert.Equal(t, nil, err)
+		defer f.Close()
+
+		old := f.backupName(time.Now().Add(-48 * time.Hour))
+		assert.Equal(t, nil, os.WriteFile(old, []byte("old"), 0644))
+		f.cleanup()
+		_, err = os.Stat(old)
+		assert.Equal(t, true, os.IsNotExist(err))
+	})
+
+	t.Run("should remove backups by max age on start", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		old := filepath.Join(dir, "app-"+time.Now().Add(-48*time.Hour).Format(backupTimeFormat)+".log")
+		assert.Equal(t, nil, os.WriteFile(old, []byte("old"), 0644))
+
+		f, err := NewRotateFile(path, 0, 1, 0, false)
+		assert.Equal(t, nil, err)
+		defer f.Close()
+
+		for i := 0; i < 50; i++ {
+			if _, err = os.Stat(old); os.IsNotExist(err) {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.Equal(t, true, os.IsNotExist(err))
+	})
+
+	t.Run("should stop the periodic cleanup on close", func(t *testing.T) {
+		f, err := NewRotateFile(filepath.Join(t.TempDir(), "app.log"), 0, 1, 0, false)
+		assert.Equal(t, nil, err)
+		stop := f.stop
+		assert.Equal(t, nil, f.Close())
+		_, ok := <-stop
+		assert.Equal(t, false, ok)
+		assert.Equal(t, nil, f.Close())
+	})
+
+	t.Run("should reopen moved file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		f, err := NewRotateFile(path, 0, 0, 0, false)
+		assert.Equal(t, nil, err)
+		defer f.Close()
+
+		moved := filepath.Join(dir, "moved.log")
+		assert.Equal(t, nil, os.Rename(path, moved))
+		assert.Equal(t, nil, f.Reopen())
+		_, _ = f.Write([]byte("reopened"))
+
+		b, _ := os.ReadFile(path)
+		assert.Equal(t, "reopened", string(b))
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/hidevopsio/golog"
+)
+
+// slogHandler is the slog.Handler bridge that writes the slog records through the structured logger
+type slogHandler struct {
+	logger *logger
+	group  string
+}
+
+// NewSlogHandler returns the slog.Handler that writes the records through the logger, e.g.
+//
+//	slog.SetDefault(slog.New(log.NewSlogHandler(log.GetLogger("slog"))))
+func NewSlogHandler(l Logger) slog.Handler {
+	lg, ok := l.(*logger)
+	if !ok {
+		lg = &logger{name: l.Name()}
+	}
+	return &slogHandler{logger: lg}
+}
+
+// Enabled returns true if the level is enabled for the logger
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.enabled(fromSlogLevel(level))
+}
+
+// Handle writes the record through the logger, the request id of ctx is included if there is
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := make([]Field, 0, r.NumAttrs()+1)
+	if id := RequestIDFromContext(ctx); id != "" {
+		fields = append(fields, String(RequestIDKey, id))
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, h.group, a)
+		return true
+	})
+	h.logger.write(r.Time, fromSlogLevel(r.Level), r.Message, fields)
+	return nil
+}
+
+// WithAttrs returns the handler that includes the attrs in every record
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []Field
+	for _, a := range attrs {
+		fields = appendAttr(fields, h.group, a)
+	}
+	lg := h.logger.With(fields).(*logger)
+	return &slogHandler{logger: lg, group: h.group}
+}
+
+// WithGroup returns the handler that qualifies the keys of the attrs with the group name, e.g. group.key
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{logger: h.logger, group: qualify(h.group, name)}
+}
+
+func appendAttr(fields []Field, group string, a slog.Attr) []Field {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		for _, ga := range v.Group() {
+			fields = appendAttr(fields, qualify(group, a.Key), ga)
+		}
+		return fields
+	}
+	if a.Key == "" {
+		return fields
+	}
+	return append(fields, Field{Key: qualify(group, a.Key), Value: v.Any()})
+}
+
+func qualify(group, key string) string {
+	if group == "" {
+		return key
+	}
+	if key == "" {
+		return group
+	}
+	return group + "." + key
+}
+
+func fromSlogLevel(level slog.Level) golog.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return golog.DebugLevel
+	case level < slog.LevelWarn:
+		return golog.InfoLevel
+	case level < slog.LevelError:
+		return golog.WarnLevel
+	default:
+		return golog.ErrorLevel
+	}
+}
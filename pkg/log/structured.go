@@ -0,0 +1,283 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hidevopsio/golog"
+)
+
+// Logger is the structured, leveled logger, the args of the log methods and With
+// can be Field or the alternating key value pairs, e.g.
+//
+//	logger := log.GetLogger("user").With("tenant", "acme")
+//	logger.Info("user is created", "id", 123, log.Duration("elapsed", elapsed))
+type Logger interface {
+	// Debug logs the message at debug level
+	Debug(msg string, args ...interface{})
+	// Info logs the message at info level
+	Info(msg string, args ...interface{})
+	// Warn logs the message at warn level
+	Warn(msg string, args ...interface{})
+	// Error logs the message at error level
+	Error(msg string, args ...interface{})
+	// With returns the logger that includes the fields in every entry
+	With(args ...interface{}) Logger
+	// Named returns the child logger, its name is the dot separated name of this logger and the given name
+	Named(name string) Logger
+	// WithContext returns the logger that includes the request id of ctx in every entry, see ContextWithRequestID
+	WithContext(ctx context.Context) Logger
+	// Name returns the name of the logger
+	Name() string
+	// Enabled returns true if the level is enabled for this logger
+	Enabled(level string) bool
+}
+
+type logger struct {
+	name   string
+	fields []Field
+}
+
+var (
+	mu sync.RWMutex
+	// writeMu serializes the writes, the output such as bytes.Buffer may not be safe for concurrent use
+	writeMu sync.Mutex
+	output  io.Writer = os.Stdout
+	// colored is true if the output is a terminal, the level of the printf style text logs is colored then
+	colored = golog.Default.Printer.IsTerminal
+	encoder Encoder
+	levels  = map[string]golog.Level{}
+	loggers sync.Map
+)
+
+func init() {
+	golog.Handle(handleLog)
+}
+
+// handleLog encodes the printf style logs by the structured encoder if it is not the text one,
+// so that all the logs end up in the same format, the text ones are formatted as golog does,
+// all the logs are written through writeOutput, so that they are not interleaved
+func handleLog(l *golog.Log) bool {
+	mu.RLock()
+	enc, color := encoder, colored
+	mu.RUnlock()
+	if enc != nil {
+		write(enc, &Entry{Time: l.Time, Level: levelName(l.Level), Message: l.Message})
+		return true
+	}
+
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	buf.Write(l.Logger.Prefix)
+	if text := golog.GetTextForLevel(l.Level, color); text != "" {
+		buf.WriteString(text + " ")
+	}
+	if t := l.FormatTime(); t != "" {
+		buf.WriteString(t + " ")
+	}
+	buf.WriteString(l.Message)
+	if l.NewLine {
+		buf.WriteByte('\n')
+	}
+	_, _ = writeOutput(buf.Bytes())
+	bufferPool.Put(buf)
+	return true
+}
+
+// GetLogger returns the named logger, the loggers with the same name are shared
+func GetLogger(name string) Logger {
+	if l, ok := loggers.Load(name); ok {
+		return l.(Logger)
+	}
+	l, _ := loggers.LoadOrStore(name, &logger{name: name})
+	return l.(Logger)
+}
+
+// SetLoggerLevel sets the level of the named logger and its children, e.g. SetLoggerLevel("user", "debug")
+// enables the debug level of the loggers user and user.repository, the loggers that have no level of their own
+// follow the level that is set by SetLevel, the unknown level is ignored with a warning
+func SetLoggerLevel(name, levelName string) {
+	level, ok := levelOf(levelName)
+	if levelName != "" && !ok {
+		Warnf("[log] unknown level %q of logger %v, it should be one of debug, info, warn, error, fatal or disable",
+			levelName, name)
+		return
+	}
+	mu.Lock()
+	if levelName == "" {
+		delete(levels, name)
+	} else {
+		levels[name] = level
+	}
+	mu.Unlock()
+}
+
+// GetLoggerLevel returns the name of the effective level of the named logger
+func GetLoggerLevel(name string) string {
+	return levelName(effectiveLevel(name))
+}
+
+// SetFormat sets the format of the logs, text or json
+func SetFormat(format string) {
+	mu.Lock()
+	if format == JSONFormat {
+		encoder = new(JSONEncoder)
+	} else {
+		encoder = nil
+	}
+	mu.Unlock()
+}
+
+// SetEncoder sets the custom encoder of the logs, the default text encoder is used if it is nil
+func SetEncoder(enc Encoder) {
+	mu.Lock()
+	encoder = enc
+	mu.Unlock()
+}
+
+func (l *logger) Debug(msg string, args ...interface{}) {
+	l.log(golog.DebugLevel, msg, args)
+}
+
+func (l *logger) Info(msg string, args ...interface{}) {
+	l.log(golog.InfoLevel, msg, args)
+}
+
+func (l *logger) Warn(msg string, args ...interface{}) {
+	l.log(golog.WarnLevel, msg, args)
+}
+
+func (l *logger) Error(msg string, args ...interface{}) {
+	l.log(golog.ErrorLevel, msg, args)
+}
+
+func (l *logger) With(args ...interface{}) Logger {
+	fields := make([]Field, 0, len(l.fields)+len(args))
+	fields = append(fields, l.fields...)
+	return &logger{name: l.name, fields: append(fields, toFields(args)...)}
+}
+
+func (l *logger) WithContext(ctx context.Context) Logger {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return l.With(String(RequestIDKey, id))
+	}
+	return l
+}
+
+func (l *logger) Named(name string) Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	return &logger{name: name, fields: l.fields}
+}
+
+func (l *logger) Name() string {
+	return l.name
+}
+
+func (l *logger) Enabled(level string) bool {
+	lvl, ok := levelOf(level)
+	return ok && l.enabled(lvl)
+}
+
+func (l *logger) enabled(level golog.Level) bool {
+	return level != golog.DisableLevel && effectiveLevel(l.name) >= level
+}
+
+func (l *logger) log(level golog.Level, msg string, args []interface{}) {
+	l.write(time.Now(), level, msg, toFields(args))
+}
+
+func (l *logger) write(t time.Time, level golog.Level, msg string, fields []Field) {
+	if !l.enabled(level) {
+		return
+	}
+	mu.RLock()
+	enc := encoder
+	mu.RUnlock()
+	if enc == nil {
+		enc = &TextEncoder{TimeFormat: golog.Default.TimeFormat}
+	}
+	if len(l.fields) != 0 {
+		fields = append(append(make([]Field, 0, len(l.fields)+len(fields)), l.fields...), fields...)
+	}
+	write(enc, &Entry{Time: t, Level: levelName(level), Logger: l.name, Message: msg, Fields: fields})
+}
+
+func write(enc Encoder, entry *Entry) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	enc.Encode(buf, entry)
+	_, _ = writeOutput(buf.Bytes())
+	bufferPool.Put(buf)
+}
+
+func writeOutput(p []byte) (int, error) {
+	mu.RLock()
+	w := output
+	mu.RUnlock()
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	return w.Write(p)
+}
+
+// effectiveLevel returns the level of the nearest named logger, e.g. user.repository, user, or the default level
+func effectiveLevel(name string) golog.Level {
+	mu.RLock()
+	defer mu.RUnlock()
+	for name != "" {
+		if level, ok := levels[name]; ok {
+			return level
+		}
+		idx := strings.LastIndex(name, ".")
+		if idx < 0 {
+			break
+		}
+		name = name[:idx]
+	}
+	return golog.Default.Level
+}
+
+// levelOf returns the level of its name, ok is false if the name is unknown
+func levelOf(name string) (level golog.Level, ok bool) {
+	name = strings.ToLower(name)
+	for lvl, meta := range golog.Levels {
+		if meta.Name == name {
+			return lvl, true
+		}
+	}
+	return
+}
+
+func levelName(level golog.Level) string {
+	if meta, ok := golog.Levels[level]; ok {
+		return meta.Name
+	}
+	return Disable
+}
+
+func setOutput(writers ...io.Writer) {
+	mu.Lock()
+	output = io.MultiWriter(writers...)
+	colored = golog.Default.Printer.IsTerminal
+	mu.Unlock()
+}
@@ -0,0 +1,189 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func captureOutput(t *testing.T, format string) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	SetOutput(buf)
+	SetFormat(format)
+	SetTimeFormat("")
+	t.Cleanup(func() {
+		SetOutput(os.Stdout)
+		SetFormat(TextFormat)
+		SetLevel(InfoLevel)
+	})
+	return buf
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) (entries []map[string]interface{}) {
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		entry := make(map[string]interface{})
+		assert.Equal(t, nil, json.Unmarshal([]byte(line), &entry), line)
+		entries = append(entries, entry)
+	}
+	return
+}
+
+func TestStructuredLogger(t *testing.T) {
+	t.Run("should log fields in text format", func(t *testing.T) {
+		buf := captureOutput(t, TextFormat)
+		SetLevel(InfoLevel)
+		logger := GetLogger("user").With("tenant", "acme")
+		logger.Info("user is created", Int("id", 123), Duration("elapsed", 1500*time.Millisecond), "name", "John Deng")
+		logger.Debug("should not be logged")
+		assert.Equal(t, "[INFO] user: user is created tenant=acme id=123 elapsed=1.5s name=\"John Deng\"\n", buf.String())
+	})
+
+	t.Run("should log fields in json format", func(t *testing.T) {
+		buf := captureOutput(t, JSONFormat)
+		GetLogger("order").Named("payment").Error("payment failed", Err(errors.New("timeout")), Bool("retry", true), "odd")
+		entries := decodeLines(t, buf)
+		assert.Equal(t, 1, len(entries))
+		assert.Equal(t, "error", entries[0]["level"])
+		assert.Equal(t, "order.payment", entries[0]["logger"])
+		assert.Equal(t, "payment failed", entries[0]["msg"])
+		assert.Equal(t, "timeout", entries[0]["error"])
+		assert.Equal(t, true, entries[0]["retry"])
+		assert.Equal(t, "odd", entries[0][badKey])
+	})
+
+	t.Run("should encode printf style logs in json format", func(t *testing.T) {
+		buf := captureOutput(t, JSONFormat)
+		Warnf("hello %v", "world")
+		entries := decodeLines(t, buf)
+		assert.Equal(t, "warn", entries[0]["level"])
+		assert.Equal(t, "hello world", entries[0]["msg"])
+	})
+
+	t.Run("should set level of named loggers", func(t *testing.T) {
+		buf := captureOutput(t, JSONFormat)
+		SetLevel(WarnLevel)
+		SetLoggerLevel("repository", DebugLevel)
+		defer SetLoggerLevel("repository", "")
+
+		assert.Equal(t, DebugLevel, GetLoggerLevel("repository.user"))
+		assert.Equal(t, WarnLevel, GetLoggerLevel("service"))
+		assert.Equal(t, true, GetLogger("repository").Named("user").Enabled(DebugLevel))
+		assert.Equal(t, false, GetLogger("service").Enabled(InfoLevel))
+
+		GetLogger("repository.user").Debug("query")
+		GetLogger("service").Info("ignored")
+		entries := decodeLines(t, buf)
+		assert.Equal(t, 1, len(entries))
+		assert.Equal(t, "repository.user", entries[0]["logger"])
+	})
+
+	t.Run("should include the request id of the context", func(t *testing.T) {
+		buf := captureOutput(t, JSONFormat)
+		ctx := ContextWithRequestID(context.Background(), "abc-123")
+		GetLogger("user").WithContext(ctx).Info("user is found")
+		GetLogger("user").WithContext(context.Background()).Info("no request id")
+		entries := decodeLines(t, buf)
+		assert.Equal(t, 2, len(entries))
+		assert.Equal(t, "abc-123", entries[0][RequestIDKey])
+		assert.Equal(t, nil, entries[1][RequestIDKey])
+	})
+
+	t.Run("should serialize concurrent writes", func(t *testing.T) {
+		buf := captureOutput(t, JSONFormat)
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				GetLogger("concurrent").Info("logged", Int("i", i))
+			}(i)
+			go func() {
+				defer wg.Done()
+				_, _ = Writer().Write([]byte(`{"msg":"written"}` + "\n"))
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 100, len(decodeLines(t, buf)))
+	})
+
+	t.Run("should serialize concurrent printf style and structured writes in text format", func(t *testing.T) {
+		buf := captureOutput(t, TextFormat)
+		SetPrefix("")
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				GetLogger("concurrent").Info("logged", Int("i", i))
+			}(i)
+			go func(i int) {
+				defer wg.Done()
+				Warnf("printed %v", i)
+			}(i)
+		}
+		wg.Wait()
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Equal(t, 100, len(lines))
+		for _, line := range lines {
+			assert.Equal(t, true, strings.HasPrefix(line, "[INFO] concurrent: logged i=") ||
+				strings.HasPrefix(line, "[WARN] printed "), line)
+		}
+	})
+
+	t.Run("should ignore the unknown level of named loggers", func(t *testing.T) {
+		buf := captureOutput(t, TextFormat)
+		SetLevel(InfoLevel)
+		SetLoggerLevel("typo", "dbug")
+		assert.Equal(t, InfoLevel, GetLoggerLevel("typo"))
+		assert.Equal(t, false, GetLogger("typo").Enabled("dbug"))
+		assert.Contains(t, buf.String(), `[WARN] [log] unknown level "dbug" of logger typo`)
+	})
+
+	t.Run("should share the named logger", func(t *testing.T) {
+		assert.Equal(t, GetLogger("shared"), GetLogger("shared"))
+	})
+}
+
+func TestSlogHandler(t *testing.T) {
+	buf := captureOutput(t, JSONFormat)
+	SetLevel(InfoLevel)
+	logger := slog.New(NewSlogHandler(GetLogger("slog")))
+
+	logger.Debug("should not be logged")
+	logger.With("component", "client").WithGroup("http").
+		WarnContext(ContextWithRequestID(context.Background(), "abc-123"),
+			"request failed", "status", 503, slog.Group("retry", "count", 2))
+
+	entries := decodeLines(t, buf)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "warn", entries[0]["level"])
+	assert.Equal(t, "slog", entries[0]["logger"])
+	assert.Equal(t, "request failed", entries[0]["msg"])
+	assert.Equal(t, "client", entries[0]["component"])
+	assert.Equal(t, float64(503), entries[0]["http.status"])
+	assert.Equal(t, float64(2), entries[0]["http.retry.count"])
+	assert.Equal(t, "abc-123", entries[0][RequestIDKey])
+}
@@ -12,12 +12,18 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package actuator provide the health check endpoint for web application
+// Package actuator provide the management endpoints, e.g. health, info, env, beans, mappings, loggers and refresh, for web application
 package actuator
 
 import (
+	"net/http"
+
 	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
 	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/system"
 )
 
 const (
@@ -25,9 +31,49 @@ const (
 	Profile = "actuator"
 )
 
+// endpoint is disabled by default
+type endpoint struct {
+	// Enabled toggles the endpoint
+	Enabled bool `json:"enabled"`
+}
+
+// publicEndpoint is enabled by default
+type publicEndpoint struct {
+	// Enabled toggles the endpoint
+	Enabled bool `json:"enabled" default:"true"`
+}
+
+type healthEndpoint struct {
+	// Enabled toggles the endpoint
+	Enabled bool `json:"enabled" default:"true"`
+	// Timeout is the seconds to wait for each health check
+	Timeout int64 `json:"timeout" default:"3"`
+	// Groups are the named health check groups that are served on /health/{group}, e.g. liveness and readiness for kubernetes probes,
+	// the value is the names of the health checks in the group, the group without health checks only reports the application itself
+	Groups map[string][]string `json:"groups"`
+}
+
+type envEndpoint struct {
+	// Enabled toggles the endpoint
+	Enabled bool `json:"enabled"`
+	// KeysToSanitize the value of the property is masked if its key contains any of these words
+	KeysToSanitize []string `json:"keys_to_sanitize" default:"password,secret,key,token,credential"`
+}
+
 type properties struct {
 	at.ConfigurationProperties `value:"actuator"`
 	at.AutoWired
+
+	// BasePath is the base path of all actuator endpoints, e.g. /actuator
+	BasePath string `json:"base_path"`
+
+	Health   healthEndpoint `json:"health"`
+	Info     publicEndpoint `json:"info"`
+	Env      envEndpoint    `json:"env"`
+	Beans    endpoint       `json:"beans"`
+	Mappings endpoint       `json:"mappings"`
+	Loggers  endpoint       `json:"loggers"`
+	Refresh  endpoint       `json:"refresh"`
 }
 
 type configuration struct {
@@ -40,6 +86,46 @@ func newConfiguration() *configuration {
 	return &configuration{}
 }
 
+// InfoController is the /info endpoint
+func (c *configuration) InfoController(systemApp *system.App) *infoController {
+	return newInfoController(systemApp, c.Properties)
+}
+
+// EnvController is the /env endpoint
+func (c *configuration) EnvController(configurableFactory factory.ConfigurableFactory) *envController {
+	return newEnvController(configurableFactory, c.Properties)
+}
+
+// BeansController is the /beans endpoint
+func (c *configuration) BeansController(configurableFactory factory.ConfigurableFactory) *beansController {
+	return newBeansController(configurableFactory, c.Properties)
+}
+
+// MappingsController is the /mappings endpoint
+func (c *configuration) MappingsController(dispatcher *web.Dispatcher) *mappingsController {
+	return newMappingsController(dispatcher, c.Properties)
+}
+
+// LoggersController is the /loggers endpoint
+func (c *configuration) LoggersController() *loggersController {
+	return newLoggersController(c.Properties)
+}
+
+// RefreshController is the /refresh endpoint
+func (c *configuration) RefreshController(configurableFactory factory.ConfigurableFactory) *refreshController {
+	return newRefreshController(configurableFactory, c.Properties)
+}
+
 func init() {
 	app.Register(newConfiguration)
 }
+
+// serveEndpoint continues the request if the endpoint is enabled, otherwise responses http.StatusNotFound
+func serveEndpoint(ctx context.Context, enabled bool) {
+	if !enabled {
+		ctx.ResponseError(http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		ctx.StopExecution()
+		return
+	}
+	ctx.Next()
+}
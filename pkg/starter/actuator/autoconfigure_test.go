@@ -1,11 +1,91 @@
 package actuator
 
 import (
-	"github.com/stretchr/testify/assert"
+	"net/http"
 	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/log"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestConfiguration(t *testing.T) {
 	c := newConfiguration()
 	assert.NotEqual(t, nil, c)
 }
+
+func TestEndpoints(t *testing.T) {
+	testApp := web.NewTestApp().
+		SetProperty(app.ProfilesInclude, web.Profile, Profile).
+		SetProperty("actuator.base_path", "/actuator").
+		SetProperty("actuator.env.enabled", true).
+		SetProperty("actuator.beans.enabled", true).
+		SetProperty("actuator.mappings.enabled", true).
+		SetProperty("actuator.loggers.enabled", true).
+		SetProperty("foo.bar", "actuator-test").
+		SetProperty("foo.password", "magic-password").
+		Run(t)
+
+	t.Run("should get health under base path", func(t *testing.T) {
+		testApp.Get("/actuator/health").
+			Expect().Status(http.StatusOK)
+	})
+
+	t.Run("should get info", func(t *testing.T) {
+		testApp.Get("/actuator/info").
+			Expect().Status(http.StatusOK).
+			JSON().Object().ContainsKey("name").ContainsKey("version")
+	})
+
+	t.Run("should get env with secrets masked", func(t *testing.T) {
+		env := testApp.Get("/actuator/env").
+			Expect().Status(http.StatusOK).
+			JSON().Object()
+		env.Value("foo.bar").Equal("actuator-test")
+		env.Value("foo.password").Equal(maskedValue)
+	})
+
+	t.Run("should get beans", func(t *testing.T) {
+		testApp.Get("/actuator/beans").
+			Expect().Status(http.StatusOK).
+			JSON().Object().ContainsKey("github.com/hidevopsio/hiboot/pkg/starter/actuator.infoController")
+	})
+
+	t.Run("should get mappings", func(t *testing.T) {
+		testApp.Get("/actuator/mappings").
+			Expect().Status(http.StatusOK).
+			JSON().Array().NotEmpty()
+	})
+
+	t.Run("should change log level", func(t *testing.T) {
+		testApp.Post("/actuator/loggers").
+			WithJSON(map[string]string{"level": log.InfoLevel}).
+			Expect().Status(http.StatusOK)
+		testApp.Get("/actuator/loggers").
+			Expect().Status(http.StatusOK).
+			JSON().Object().Value("level").Equal(log.InfoLevel)
+		testApp.Post("/actuator/loggers").
+			WithJSON(map[string]string{"level": "verbose"}).
+			Expect().Status(http.StatusBadRequest)
+		log.SetLevel(log.DebugLevel)
+	})
+}
+
+func TestDisabledEndpoints(t *testing.T) {
+	testApp := web.NewTestApp().
+		SetProperty(app.ProfilesInclude, web.Profile, Profile).
+		SetProperty("actuator.info.enabled", false).
+		Run(t)
+
+	t.Run("should get health", func(t *testing.T) {
+		testApp.Get("/health").
+			Expect().Status(http.StatusOK)
+	})
+
+	t.Run("should not get disabled endpoints", func(t *testing.T) {
+		testApp.Get("/info").Expect().Status(http.StatusNotFound)
+		testApp.Get("/env").Expect().Status(http.StatusNotFound)
+		testApp.Get("/loggers").Expect().Status(http.StatusNotFound)
+	})
+}
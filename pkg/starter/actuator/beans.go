@@ -0,0 +1,73 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actuator
+
+import (
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
+)
+
+// Bean is the description of the component that is managed by hiboot
+type Bean struct {
+	at.Schema    `json:"-"`
+	Type         string   `schema:"The type of the bean" json:"type"`
+	Kind         string   `schema:"The kind of the bean, e.g. func, method or struct" json:"kind"`
+	Scope        string   `schema:"The scope of the bean, it is singleton if it is empty" json:"scope,omitempty"`
+	Dependencies []string `schema:"The dependencies of the bean" json:"dependencies,omitempty"`
+}
+
+type beansController struct {
+	at.RestController
+	at.ManagementEndpoint
+	at.RequestMapping `value:"${actuator.base_path:}/beans" no_context_path:"true"`
+
+	configurableFactory factory.ConfigurableFactory
+	properties          *properties
+}
+
+func newBeansController(configurableFactory factory.ConfigurableFactory, properties *properties) *beansController {
+	return &beansController{configurableFactory: configurableFactory, properties: properties}
+}
+
+// Before checks if the beans endpoint is enabled
+func (c *beansController) Before(ctx context.Context) {
+	serveEndpoint(ctx, c.properties.Beans.Enabled)
+}
+
+// GET /beans
+func (c *beansController) Get(struct {
+	at.GetMapping `value:"/"`
+	at.Operation  `id:"beans" description:"the components that are managed by hiboot"`
+	at.Produces   `values:"application/json"`
+}) map[string]*Bean {
+	beans := make(map[string]*Bean)
+	for name, item := range c.configurableFactory.Items() {
+		md := factory.CastMetaData(item)
+		if md == nil {
+			continue
+		}
+		bean := &Bean{
+			Kind:         md.Kind,
+			Scope:        md.Scope,
+			Dependencies: md.DepNames,
+		}
+		if md.Type != nil {
+			bean.Type = md.Type.String()
+		}
+		beans[name] = bean
+	}
+	return beans
+}
@@ -0,0 +1,84 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actuator
+
+import (
+	"strings"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
+)
+
+const maskedValue = "******"
+
+// propertyKeys is implemented by the property builder that is able to list all property keys
+type propertyKeys interface {
+	AllKeys() []string
+}
+
+type envController struct {
+	at.RestController
+	at.ManagementEndpoint
+	at.RequestMapping `value:"${actuator.base_path:}/env" no_context_path:"true"`
+
+	configurableFactory factory.ConfigurableFactory
+	properties          *properties
+}
+
+func newEnvController(configurableFactory factory.ConfigurableFactory, properties *properties) *envController {
+	return &envController{configurableFactory: configurableFactory, properties: properties}
+}
+
+// Before checks if the env endpoint is enabled
+func (c *envController) Before(ctx context.Context) {
+	serveEndpoint(ctx, c.properties.Env.Enabled)
+}
+
+// GET /env
+func (c *envController) Get(struct {
+	at.GetMapping `value:"/"`
+	at.Operation  `id:"env" description:"resolved properties endpoint, the secrets are masked"`
+	at.Produces   `values:"application/json"`
+}) map[string]interface{} {
+	env := make(map[string]interface{})
+	builder := c.configurableFactory.Builder()
+	keys, ok := builder.(propertyKeys)
+	if !ok {
+		return env
+	}
+	for _, key := range keys.AllKeys() {
+		if c.sensitive(key) {
+			env[key] = maskedValue
+			continue
+		}
+		val := builder.GetProperty(key)
+		if s, ok := val.(string); ok {
+			val = c.configurableFactory.Replace(s)
+		}
+		env[key] = val
+	}
+	return env
+}
+
+func (c *envController) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range c.properties.Env.KeysToSanitize {
+		if k != "" && strings.Contains(key, strings.ToLower(k)) {
+			return true
+		}
+	}
+	return false
+}
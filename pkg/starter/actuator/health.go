@@ -16,26 +16,64 @@
 package actuator
 
 import (
+	stdcontext "context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
 	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
 	"github.com/hidevopsio/hiboot/pkg/at"
 	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/utils/str"
+)
+
+// Status is the status of health check
+type Status string
+
+const (
+	// StatusUp the component is functioning as expected
+	StatusUp Status = "UP"
+	// StatusDown the component has suffered an unexpected failure
+	StatusDown Status = "DOWN"
+	// StatusOutOfService the component has been taken out of service and should not be used
+	StatusOutOfService Status = "OUT_OF_SERVICE"
+	// StatusUnknown the component is in an unknown state
+	StatusUnknown Status = "UNKNOWN"
+
+	defaultHealthTimeout = 3
 )
 
-// HealthService is the interface for health check
+// statusOrder is the severity order of the status, the first one is the most severe
+var statusOrder = []Status{StatusDown, StatusOutOfService, StatusUp, StatusUnknown}
+
+// HealthService is the interface for health check, Status can not be cancelled and must return on its own,
+// it is reported down once the timeout elapses but keeps running until it returns
 type HealthService interface {
 	Name() string
 	Status() bool
 }
 
+// HealthIndicator is the interface for detailed health check, it is preferred over HealthService.
+// ctx is cancelled when the timeout elapses or the request is gone, Health should return as soon as ctx is done
+type HealthIndicator interface {
+	Name() string
+	Health(ctx stdcontext.Context) *Health
+}
+
 // Health is the health check struct
 type Health struct {
 	at.Schema `json:"-"`
-	Status string `schema:"The status of health check" json:"status"`
+	Status    Status                 `schema:"The status of health check" json:"status"`
+	Details   map[string]interface{} `schema:"The details of health check" json:"details,omitempty"`
+	Error     string                 `schema:"The error of health check" json:"error,omitempty"`
 }
 
 type healthController struct {
 	at.RestController
-	at.RequestMapping `value:"/health" no_context_path:"true"`
+	at.ManagementEndpoint
+	at.RequestMapping `value:"${actuator.base_path:}/health" no_context_path:"true"`
 
 	configurableFactory factory.ConfigurableFactory
 }
@@ -48,38 +86,165 @@ func newHealthController(configurableFactory factory.ConfigurableFactory) *healt
 	return &healthController{configurableFactory: configurableFactory}
 }
 
+// Before checks if the health endpoint is enabled, it is enabled even if the actuator profile is not included
+func (c *healthController) Before(ctx context.Context) {
+	p := c.actuatorProperties()
+	serveEndpoint(ctx, p == nil || p.Health.Enabled)
+}
+
+// actuatorProperties returns nil if the actuator profile is not included
+func (c *healthController) actuatorProperties() (p *properties) {
+	p, _ = c.configurableFactory.GetInstance(properties{}).(*properties)
+	return
+}
+
 // GET /health
-func (c *healthController) Get( struct {
+func (c *healthController) Get(_ struct {
 	at.GetMapping `value:"/"`
 	at.Operation  `id:"health" description:"health check endpoint"`
 	at.Produces   `values:"application/json"`
-	Responses struct {
+	Responses     struct {
 		StatusOK struct {
 			at.Response `code:"200" description:"Returns the status of health check"`
 			Health
 		}
+		StatusServiceUnavailable struct {
+			at.Response `code:"503" description:"Returns the status of health check if it is down"`
+			Health
+		}
+	}
+}, ctx context.Context) map[string]interface{} {
+	return c.check(ctx, nil)
+}
+
+// GET /health/{group}
+func (c *healthController) GetGroup(_ struct {
+	at.GetMapping `value:"/{group}"`
+	at.Operation  `id:"healthGroup" description:"health check endpoint of the group, e.g. liveness or readiness"`
+	at.Produces   `values:"application/json"`
+}, group string, ctx context.Context) map[string]interface{} {
+	members, ok := c.groups()[group]
+	if !ok {
+		ctx.StatusCode(http.StatusNotFound)
+		return map[string]interface{}{"status": StatusUnknown}
+	}
+	// the group without members only reports the application itself, e.g. liveness
+	if members == nil {
+		members = []string{}
+	}
+	return c.check(ctx, members)
+}
+
+func (c *healthController) groups() (groups map[string][]string) {
+	if p := c.actuatorProperties(); p != nil {
+		groups = p.Health.Groups
+	}
+	return
+}
+
+func (c *healthController) timeout() time.Duration {
+	timeout := int64(defaultHealthTimeout)
+	if p := c.actuatorProperties(); p != nil && p.Health.Timeout > 0 {
+		timeout = p.Health.Timeout
+	}
+	return time.Duration(timeout) * time.Second
+}
+
+// check runs the health checks concurrently, members is the name filter of the health checks, nil means all
+func (c *healthController) check(ctx context.Context, members []string) map[string]interface{} {
+	var services []interface{}
+	for _, md := range c.configurableFactory.GetInstances(at.HealthCheckService{}) {
+		metaData := factory.CastMetaData(md)
+		if metaData == nil || metaData.Instance == nil {
+			continue
+		}
+		name := healthName(metaData.Instance)
+		if name == "" || (members != nil && !str.InSlice(name, members)) {
+			continue
+		}
+		services = append(services, metaData.Instance)
+	}
+
+	result := make(map[string]interface{})
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	statuses := []Status{StatusUp}
+	timeout := c.timeout()
+	for _, svc := range services {
+		wg.Add(1)
+		go func(svc interface{}) {
+			defer wg.Done()
+			health := checkHealth(ctx.Request().Context(), svc, timeout)
+			mu.Lock()
+			result[healthName(svc)] = health
+			statuses = append(statuses, health.Status)
+			mu.Unlock()
+		}(svc)
+	}
+	wg.Wait()
+
+	status := aggregate(statuses)
+	result["status"] = status
+	if status == StatusDown || status == StatusOutOfService {
+		ctx.StatusCode(http.StatusServiceUnavailable)
+	}
+	return result
+}
+
+func healthName(svc interface{}) (name string) {
+	switch s := svc.(type) {
+	case HealthIndicator:
+		name = s.Name()
+	case HealthService:
+		name = s.Name()
 	}
-}) map[string]interface{} {
-	healthServices := c.configurableFactory.GetInstances(at.HealthCheckService{})
-	healthCheckProfiles := make(map[string]interface{})
-
-	healthCheckProfiles["status"] = "Up"
-
-	if healthServices != nil {
-		for _, md := range healthServices {
-			metaData := factory.CastMetaData(md)
-			if metaData.Instance != nil {
-				healthService := metaData.Instance.(HealthService)
-				status := "Down"
-				if healthService.Status() {
-					status = "Up"
-				}
-				healthCheckProfiles[healthService.Name()] = Health{
-					Status: status,
-				}
+	return
+}
+
+// checkHealth calls the health check with timeout, the health check is treated as down if it times out,
+// the context passed to HealthIndicator is cancelled on return so that the check can stop
+func checkHealth(parent stdcontext.Context, svc interface{}, timeout time.Duration) (health *Health) {
+	ctx, cancel := stdcontext.WithTimeout(parent, timeout)
+	defer cancel()
+
+	done := make(chan *Health, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- &Health{Status: StatusDown, Error: fmt.Sprintf("%v", r)}
+			}
+		}()
+		switch s := svc.(type) {
+		case HealthIndicator:
+			done <- s.Health(ctx)
+		case HealthService:
+			status := StatusDown
+			if s.Status() {
+				status = StatusUp
 			}
+			done <- &Health{Status: status}
 		}
+	}()
+
+	select {
+	case health = <-done:
+		if health == nil {
+			health = &Health{Status: StatusUnknown}
+		}
+	case <-ctx.Done():
+		health = &Health{Status: StatusDown, Error: fmt.Sprintf("health check timed out after %v", timeout)}
 	}
+	return
+}
 
-	return healthCheckProfiles
+// aggregate returns the most severe status
+func aggregate(statuses []Status) Status {
+	for _, s := range statusOrder {
+		for _, status := range statuses {
+			if s == status {
+				return s
+			}
+		}
+	}
+	return StatusUnknown
 }
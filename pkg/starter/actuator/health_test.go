@@ -15,13 +15,18 @@
 package actuator
 
 import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
 	"github.com/hidevopsio/hiboot/pkg/app"
 	"github.com/hidevopsio/hiboot/pkg/app/web"
 	"github.com/hidevopsio/hiboot/pkg/at"
 	"github.com/hidevopsio/hiboot/pkg/log"
 	"github.com/hidevopsio/hiboot/pkg/starter/logging"
-	"net/http"
-	"testing"
+	"github.com/stretchr/testify/assert"
 )
 
 type fakeHealthCheckService struct {
@@ -40,8 +45,56 @@ func newFakeHealthCheckService() HealthService {
 	return &fakeHealthCheckService{}
 }
 
+var (
+	dbStatus    = StatusUp
+	cacheDelay  int64
+	cacheHealth = &Health{Status: StatusUp, Details: map[string]interface{}{"hits": 100}}
+	// cacheCancelled receives the error of the context once the cache health check is cancelled
+	cacheCancelled = make(chan error, 1)
+)
+
+type fakeDbHealthIndicator struct {
+	at.HealthCheckService
+}
+
+func newFakeDbHealthIndicator() *fakeDbHealthIndicator {
+	return &fakeDbHealthIndicator{}
+}
+
+func (s *fakeDbHealthIndicator) Name() string {
+	return "db"
+}
+
+func (s *fakeDbHealthIndicator) Health(ctx context.Context) *Health {
+	return &Health{Status: dbStatus, Details: map[string]interface{}{"database": "fake"}}
+}
+
+type fakeCacheHealthIndicator struct {
+	at.HealthCheckService
+}
+
+func newFakeCacheHealthIndicator() *fakeCacheHealthIndicator {
+	return &fakeCacheHealthIndicator{}
+}
+
+func (s *fakeCacheHealthIndicator) Name() string {
+	return "cache"
+}
+
+func (s *fakeCacheHealthIndicator) Health(ctx context.Context) *Health {
+	select {
+	case <-time.After(time.Duration(atomic.LoadInt64(&cacheDelay))):
+	case <-ctx.Done():
+		select {
+		case cacheCancelled <- ctx.Err():
+		default:
+		}
+	}
+	return cacheHealth
+}
+
 func init() {
-	app.Register(newFakeHealthCheckService)
+	app.Register(newFakeHealthCheckService, newFakeDbHealthIndicator, newFakeCacheHealthIndicator)
 }
 
 func TestHealthController(t *testing.T) {
@@ -50,3 +103,63 @@ func TestHealthController(t *testing.T) {
 		Get("/health").
 		Expect().Status(http.StatusOK)
 }
+
+func TestHealthAggregation(t *testing.T) {
+	testApp := web.NewTestApp().
+		SetProperty(app.ProfilesInclude, web.Profile, Profile).
+		SetProperty("actuator.health.timeout", 1).
+		SetProperty("actuator.health.groups.liveness", []string{}).
+		SetProperty("actuator.health.groups.readiness", []string{"db"}).
+		Run(t)
+
+	t.Run("should report details of all health checks", func(t *testing.T) {
+		health := testApp.Get("/health").
+			Expect().Status(http.StatusOK).
+			JSON().Object()
+		health.Value("status").Equal(StatusUp)
+		health.Value("fake").Object().Value("status").Equal(StatusUp)
+		health.Value("db").Object().Value("details").Object().Value("database").Equal("fake")
+		health.Value("cache").Object().Value("details").Object().Value("hits").Equal(100)
+	})
+
+	t.Run("should response http.StatusServiceUnavailable if any health check is down", func(t *testing.T) {
+		dbStatus = StatusDown
+		defer func() { dbStatus = StatusUp }()
+		testApp.Get("/health").
+			Expect().Status(http.StatusServiceUnavailable).
+			JSON().Object().Value("status").Equal(StatusDown)
+	})
+
+	t.Run("should report down if health check times out", func(t *testing.T) {
+		atomic.StoreInt64(&cacheDelay, int64(2*time.Second))
+		defer atomic.StoreInt64(&cacheDelay, 0)
+		testApp.Get("/health").
+			Expect().Status(http.StatusServiceUnavailable).
+			JSON().Object().Value("cache").Object().Value("error").String().Contains("timed out")
+		select {
+		case err := <-cacheCancelled:
+			assert.Equal(t, context.DeadlineExceeded, err)
+		case <-time.After(time.Second):
+			t.Error("the context of the timed out health check is not cancelled")
+		}
+	})
+
+	t.Run("should check health groups", func(t *testing.T) {
+		dbStatus = StatusOutOfService
+		defer func() { dbStatus = StatusUp }()
+		testApp.Get("/health/liveness").
+			Expect().Status(http.StatusOK).
+			JSON().Object().NotContainsKey("db")
+		testApp.Get("/health/readiness").
+			Expect().Status(http.StatusServiceUnavailable).
+			JSON().Object().NotContainsKey("fake").Value("status").Equal(StatusOutOfService)
+		testApp.Get("/health/startup").
+			Expect().Status(http.StatusNotFound)
+	})
+
+	t.Run("should aggregate status by severity", func(t *testing.T) {
+		assert.Equal(t, StatusDown, aggregate([]Status{StatusUp, StatusOutOfService, StatusDown}))
+		assert.Equal(t, StatusUp, aggregate([]Status{StatusUnknown, StatusUp}))
+		assert.Equal(t, StatusUnknown, aggregate([]Status{StatusUnknown}))
+	})
+}
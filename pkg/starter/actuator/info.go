@@ -0,0 +1,68 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actuator
+
+import (
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/system"
+)
+
+// Info is the application information
+type Info struct {
+	at.Schema   `json:"-"`
+	Name        string `schema:"The name of the application" json:"name"`
+	Version     string `schema:"The version of the application" json:"version"`
+	Project     string `schema:"The project of the application" json:"project,omitempty"`
+	Description string `schema:"The description of the application" json:"description,omitempty"`
+}
+
+type infoController struct {
+	at.RestController
+	at.ManagementEndpoint
+	at.RequestMapping `value:"${actuator.base_path:}/info" no_context_path:"true"`
+
+	systemApp  *system.App
+	properties *properties
+}
+
+func newInfoController(systemApp *system.App, properties *properties) *infoController {
+	return &infoController{systemApp: systemApp, properties: properties}
+}
+
+// Before checks if the info endpoint is enabled
+func (c *infoController) Before(ctx context.Context) {
+	serveEndpoint(ctx, c.properties.Info.Enabled)
+}
+
+// GET /info
+func (c *infoController) Get(struct {
+	at.GetMapping `value:"/"`
+	at.Operation  `id:"info" description:"application information endpoint"`
+	at.Produces   `values:"application/json"`
+	Responses     struct {
+		StatusOK struct {
+			at.Response `code:"200" description:"Returns the application information"`
+			Info
+		}
+	}
+}) *Info {
+	return &Info{
+		Name:        c.systemApp.Name,
+		Version:     c.systemApp.Version,
+		Project:     c.systemApp.Project,
+		Description: c.systemApp.Description,
+	}
+}
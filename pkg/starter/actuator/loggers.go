@@ -0,0 +1,80 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actuator
+
+import (
+	"net/http"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/log"
+	"github.com/hidevopsio/hiboot/pkg/model"
+)
+
+// Logger is the log level of the application
+type Logger struct {
+	at.Schema `json:"-"`
+	Level     string   `schema:"The current log level" json:"level"`
+	Levels    []string `schema:"The available log levels" json:"levels,omitempty"`
+}
+
+type loggerRequest struct {
+	model.RequestBody
+	Level string `json:"level" validate:"required,oneof=disable fatal error warn info debug"`
+}
+
+type loggersController struct {
+	at.RestController
+	at.ManagementEndpoint
+	at.RequestMapping `value:"${actuator.base_path:}/loggers" no_context_path:"true"`
+
+	properties *properties
+}
+
+func newLoggersController(properties *properties) *loggersController {
+	return &loggersController{properties: properties}
+}
+
+// Before checks if the loggers endpoint is enabled
+func (c *loggersController) Before(ctx context.Context) {
+	serveEndpoint(ctx, c.properties.Loggers.Enabled)
+}
+
+// GET /loggers
+func (c *loggersController) Get(struct {
+	at.GetMapping `value:"/"`
+	at.Operation  `id:"getLoggers" description:"get the log level"`
+	at.Produces   `values:"application/json"`
+}) *Logger {
+	return &Logger{
+		Level:  log.GetLevel(),
+		Levels: []string{log.Disable, log.FatalLevel, log.ErrorLevel, log.WarnLevel, log.InfoLevel, log.DebugLevel},
+	}
+}
+
+// POST /loggers
+func (c *loggersController) Post(_ struct {
+	at.PostMapping `value:"/"`
+	at.Operation   `id:"setLoggers" description:"change the log level at runtime"`
+	at.Consumes    `values:"application/json"`
+	at.Produces    `values:"application/json"`
+}, request *loggerRequest) (response model.Response) {
+	log.SetLevel(request.Level)
+	log.Infof("log level is changed to %v", request.Level)
+	response = new(model.BaseResponse)
+	response.SetCode(http.StatusOK)
+	response.SetData(&Logger{Level: log.GetLevel()})
+	return
+}
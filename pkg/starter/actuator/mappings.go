@@ -0,0 +1,48 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actuator
+
+import (
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+)
+
+type mappingsController struct {
+	at.RestController
+	at.ManagementEndpoint
+	at.RequestMapping `value:"${actuator.base_path:}/mappings" no_context_path:"true"`
+
+	dispatcher *web.Dispatcher
+	properties *properties
+}
+
+func newMappingsController(dispatcher *web.Dispatcher, properties *properties) *mappingsController {
+	return &mappingsController{dispatcher: dispatcher, properties: properties}
+}
+
+// Before checks if the mappings endpoint is enabled
+func (c *mappingsController) Before(ctx context.Context) {
+	serveEndpoint(ctx, c.properties.Mappings.Enabled)
+}
+
+// GET /mappings
+func (c *mappingsController) Get(struct {
+	at.GetMapping `value:"/"`
+	at.Operation  `id:"mappings" description:"the request mappings with their middleware chain"`
+	at.Produces   `values:"application/json"`
+}) []*web.Mapping {
+	return c.dispatcher.Mappings()
+}
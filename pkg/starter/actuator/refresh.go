@@ -0,0 +1,61 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actuator
+
+import (
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
+)
+
+// Refresh is the result of refreshing the configuration properties
+type Refresh struct {
+	at.Schema `json:"-"`
+	Keys      []string `schema:"The keys of the changed properties" json:"keys"`
+}
+
+type refreshController struct {
+	at.RestController
+	at.ManagementEndpoint
+	at.RequestMapping `value:"${actuator.base_path:}/refresh" no_context_path:"true"`
+
+	configurableFactory factory.ConfigurableFactory
+	properties          *properties
+}
+
+func newRefreshController(configurableFactory factory.ConfigurableFactory, properties *properties) *refreshController {
+	return &refreshController{configurableFactory: configurableFactory, properties: properties}
+}
+
+// Before checks if the refresh endpoint is enabled
+func (c *refreshController) Before(ctx context.Context) {
+	serveEndpoint(ctx, c.properties.Refresh.Enabled)
+}
+
+// POST /refresh
+func (c *refreshController) Post(struct {
+	at.PostMapping `value:"/"`
+	at.Operation   `id:"refresh" description:"reload the config files and rebuild the refresh scoped components"`
+	at.Produces    `values:"application/json"`
+}) (response *Refresh, err error) {
+	keys, err := c.configurableFactory.Refresh()
+	if err == nil {
+		response = &Refresh{Keys: keys}
+		if response.Keys == nil {
+			response.Keys = []string{}
+		}
+	}
+	return
+}
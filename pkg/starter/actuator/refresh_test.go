@@ -0,0 +1,137 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actuator
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
+	"github.com/hidevopsio/hiboot/pkg/log"
+	"github.com/hidevopsio/hiboot/pkg/utils/io"
+	"github.com/stretchr/testify/assert"
+)
+
+type greetingProperties struct {
+	at.ConfigurationProperties `value:"greeting"`
+	at.AutoWired
+
+	Message string `json:"message" default:"hi"`
+}
+
+type greetingConfiguration struct {
+	at.AutoConfiguration
+
+	Properties *greetingProperties
+}
+
+func newGreetingConfiguration() *greetingConfiguration {
+	return &greetingConfiguration{}
+}
+
+var greeterBuilds int32
+
+type greeter struct {
+	at.RefreshScope
+
+	message string
+}
+
+// Greeter is rebuilt lazily after refresh
+func (c *greetingConfiguration) Greeter() *greeter {
+	atomic.AddInt32(&greeterBuilds, 1)
+	return &greeter{message: c.Properties.Message}
+}
+
+type refreshListener struct {
+	keys []string
+}
+
+func newRefreshListener() *refreshListener {
+	return &refreshListener{}
+}
+
+func (l *refreshListener) OnRefresh(event *factory.RefreshEvent) {
+	l.keys = event.Keys
+}
+
+type greetingController struct {
+	at.RestController
+	at.RequestMapping `value:"/greeting"`
+}
+
+func newGreetingController() *greetingController {
+	return &greetingController{}
+}
+
+func (c *greetingController) Get(g *greeter) string {
+	return g.message
+}
+
+func writeConfig(t *testing.T, dir, message, level string) {
+	content := "app:\n  name: refresh-test\nlogging:\n  level: " + level + "\ngreeting:\n  message: " + message + "\n"
+	assert.Equal(t, nil, os.WriteFile(filepath.Join(dir, "config", "application.yml"), []byte(content), 0644))
+}
+
+func TestRefresh(t *testing.T) {
+	wd := io.GetWorkDir()
+	dir := t.TempDir()
+	assert.Equal(t, nil, os.MkdirAll(filepath.Join(dir, "config"), 0755))
+	writeConfig(t, dir, "hello", "info")
+	assert.Equal(t, nil, io.ChangeWorkDir(dir))
+	defer func() {
+		_ = io.ChangeWorkDir(wd)
+		log.SetLevel(log.DebugLevel)
+	}()
+
+	app.Register(newGreetingConfiguration, newRefreshListener)
+	testApp := web.NewTestApp(newGreetingController).
+		SetProperty(app.ProfilesInclude, web.Profile, Profile, "greeting").
+		SetProperty("actuator.refresh.enabled", true).
+		Run(t)
+	listener := testApp.(app.ApplicationContext).GetInstance(refreshListener{}).(*refreshListener)
+	properties := testApp.(app.ApplicationContext).GetInstance(greetingProperties{}).(*greetingProperties)
+
+	testApp.Get("/greeting").Expect().Status(http.StatusOK).Body().Equal("hello")
+	testApp.Get("/greeting").Expect().Status(http.StatusOK).Body().Equal("hello")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&greeterBuilds))
+
+	t.Run("should not change anything if the config files are not changed", func(t *testing.T) {
+		testApp.Post("/refresh").Expect().Status(http.StatusOK).
+			JSON().Object().Value("keys").Array().Empty()
+		assert.Equal(t, 0, len(listener.keys))
+	})
+
+	t.Run("should rebuild refresh scoped components with the refreshed properties", func(t *testing.T) {
+		writeConfig(t, dir, "hola", "warn")
+		testApp.Post("/refresh").Expect().Status(http.StatusOK).
+			JSON().Object().Value("keys").Array().Elements("greeting.message", "logging.level")
+
+		assert.Equal(t, []string{"greeting.message", "logging.level"}, listener.keys)
+		// the singleton properties are not changed
+		assert.Equal(t, "hello", properties.Message)
+		assert.Equal(t, log.WarnLevel, log.GetLevel())
+
+		testApp.Get("/greeting").Expect().Status(http.StatusOK).Body().Equal("hola")
+		testApp.Get("/greeting").Expect().Status(http.StatusOK).Body().Equal("hola")
+		assert.Equal(t, int32(2), atomic.LoadInt32(&greeterBuilds))
+	})
+}
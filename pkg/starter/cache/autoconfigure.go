@@ -0,0 +1,67 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache provides the hiboot starter for declarative caching, the results of the methods that are annotated
+// with at.Cacheable are cached in the named caches of CacheManager, at.CachePut updates and at.CacheEvict evicts them.
+//
+// The annotations work on the controller methods, and on the components that are injected as the interfaces
+// whose proxies are registered by aop.RegisterProxy, e.g. at.Cacheable `name:"users" key:"{0}" methods:"Get"`.
+// The caches are in-memory LRU caches with ttl and size limits by default, an external store, e.g. redis,
+// can be provided by registering a constructor that returns cache.Store.
+// The statistics of the caches are served on the actuator endpoint /caches.
+package cache
+
+import (
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/at"
+)
+
+const (
+	// Profile is the profile of cache, it should be as same as the package name
+	Profile = "cache"
+)
+
+type configuration struct {
+	at.AutoConfiguration
+
+	Properties *Properties
+}
+
+func init() {
+	app.Register(newConfiguration)
+}
+
+func newConfiguration() *configuration {
+	return &configuration{}
+}
+
+// Store is the in-memory store of the caches, it backs off if the user supplies the Store, e.g. a redis based store
+func (c *configuration) Store(_ struct{ at.ConditionalOnMissingBean }) Store {
+	return NewMemoryStore()
+}
+
+// CacheManager is the injectable manager of the named caches
+func (c *configuration) CacheManager(store Store) *CacheManager {
+	return NewCacheManager(store, c.Properties)
+}
+
+// Interceptor applies at.Cacheable, at.CachePut and at.CacheEvict
+func (c *configuration) Interceptor(manager *CacheManager) *interceptor {
+	return newInterceptor(manager)
+}
+
+// Controller serves the statistics of the caches on actuator endpoint
+func (c *configuration) Controller(manager *CacheManager) *controller {
+	return newController(manager)
+}
@@ -0,0 +1,253 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/aop"
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/starter/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserRepository interface {
+	Find(id int) (*User, error)
+	Save(user *User) (*User, error)
+	Delete(id int) error
+}
+
+type userRepository struct {
+	at.Cacheable  `name:"users" key:"user:{0}" methods:"Find"`
+	at.CachePut   `name:"users" key:"user:{0.ID}" methods:"Save"`
+	at.CacheEvict `name:"users" key:"user:{0}" methods:"Delete"`
+
+	mu    sync.Mutex
+	users map[int]*User
+	finds int
+}
+
+func newUserRepository() UserRepository {
+	return &userRepository{users: map[int]*User{1: {ID: 1, Name: "john"}}}
+}
+
+func (r *userRepository) Find(id int) (*User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finds++
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return &User{ID: user.ID, Name: user.Name}, nil
+}
+
+func (r *userRepository) Save(user *User) (*User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID] = user
+	return user, nil
+}
+
+func (r *userRepository) Delete(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, id)
+	return nil
+}
+
+type userRepositoryProxy struct {
+	*aop.Proxy
+}
+
+func (p *userRepositoryProxy) Find(id int) (*User, error) {
+	results := p.Invoke("Find", id)
+	err, _ := results[1].(error)
+	return results[0].(*User), err
+}
+
+func (p *userRepositoryProxy) Save(user *User) (*User, error) {
+	results := p.Invoke("Save", user)
+	err, _ := results[1].(error)
+	return results[0].(*User), err
+}
+
+func (p *userRepositoryProxy) Delete(id int) error {
+	err, _ := p.Invoke("Delete", id)[0].(error)
+	return err
+}
+
+func init() {
+	aop.RegisterProxy(new(UserRepository), func(p *aop.Proxy) UserRepository {
+		return &userRepositoryProxy{p}
+	})
+}
+
+type userRequest struct {
+	at.RequestBody
+	Name string `json:"name"`
+}
+
+type userController struct {
+	at.RestController
+	at.RequestMapping `value:"/users"`
+
+	repository UserRepository
+}
+
+func newUserController(repository UserRepository) *userController {
+	return &userController{repository: repository}
+}
+
+func (c *userController) Get(_ struct {
+	at.GetMapping `value:"/{id}"`
+}, id int) (*User, error) {
+	return c.repository.Find(id)
+}
+
+func (c *userController) Put(_ struct {
+	at.PutMapping `value:"/{id}"`
+}, id int, request *userRequest) (*User, error) {
+	return c.repository.Save(&User{ID: id, Name: request.Name})
+}
+
+func (c *userController) Delete(_ struct {
+	at.DeleteMapping `value:"/{id}"`
+}, id int) error {
+	return c.repository.Delete(id)
+}
+
+type greetingController struct {
+	at.RestController
+	at.RequestMapping `value:"/greetings"`
+
+	calls  int
+	hellos int
+}
+
+func newGreetingController() *greetingController {
+	return &greetingController{}
+}
+
+func (c *greetingController) Get(_ struct {
+	at.GetMapping `value:"/{name}"`
+	at.Cacheable  `name:"greetings" key:"{0}"`
+}, name string) string {
+	c.calls++
+	return fmt.Sprintf("hello %v #%v", name, c.calls)
+}
+
+func (c *greetingController) GetHello(_ struct {
+	at.GetMapping `value:"/{name}/hello"`
+	at.Cacheable  `name:"hellos"`
+}, name string, ctx context.Context) string {
+	c.hellos++
+	return fmt.Sprintf("hello %v from %v #%v", name, ctx.Path(), c.hellos)
+}
+
+func (c *greetingController) Delete(_ struct {
+	at.DeleteMapping `value:"/"`
+	at.CacheEvict    `name:"greetings" allEntries:"true"`
+}) string {
+	return "cleared"
+}
+
+func TestCache(t *testing.T) {
+	app.Register(newUserRepository)
+	testApp := web.NewTestApp(newUserController, newGreetingController).
+		SetProperty(app.ProfilesInclude, web.Profile, cache.Profile).
+		Run(t)
+	manager := testApp.(app.ApplicationContext).GetInstance(cache.CacheManager{}).(*cache.CacheManager)
+
+	t.Run("should cache the result of the controller method", func(t *testing.T) {
+		testApp.Get("/greetings/{name}").WithPath("name", "john").
+			Expect().Status(http.StatusOK).Body().Contains("hello john #1")
+		testApp.Get("/greetings/{name}").WithPath("name", "john").
+			Expect().Status(http.StatusOK).Body().Contains("hello john #1")
+		testApp.Get("/greetings/{name}").WithPath("name", "jane").
+			Expect().Status(http.StatusOK).Body().Contains("hello jane #2")
+	})
+
+	t.Run("should cache the result of the controller method that takes the context", func(t *testing.T) {
+		body := testApp.Get("/greetings/{name}/hello").WithPath("name", "john").
+			Expect().Status(http.StatusOK).Body().Raw()
+		testApp.Get("/greetings/{name}/hello").WithPath("name", "john").
+			Expect().Status(http.StatusOK).Body().Equal(body)
+		_, ok, _ := manager.Cache("hellos").Get("john")
+		assert.Equal(t, true, ok)
+	})
+
+	t.Run("should evict all entries of the cache", func(t *testing.T) {
+		testApp.Delete("/greetings").Expect().Status(http.StatusOK)
+		testApp.Get("/greetings/{name}").WithPath("name", "john").
+			Expect().Status(http.StatusOK).Body().Contains("hello john #3")
+	})
+
+	t.Run("should cache the result of the injected service", func(t *testing.T) {
+		testApp.Get("/users/{id}").WithPath("id", 1).
+			Expect().Status(http.StatusOK).Body().Contains("john")
+		testApp.Get("/users/{id}").WithPath("id", 1).
+			Expect().Status(http.StatusOK).Body().Contains("john")
+		stats := manager.Stats()["users"]
+		assert.Equal(t, uint64(1), stats.Hits)
+		assert.Equal(t, uint64(1), stats.Misses)
+	})
+
+	t.Run("should not cache the error", func(t *testing.T) {
+		testApp.Get("/users/{id}").WithPath("id", 2).Expect().Status(http.StatusInternalServerError)
+		testApp.Get("/users/{id}").WithPath("id", 2).Expect().Status(http.StatusInternalServerError)
+		assert.Equal(t, uint64(1), manager.Stats()["users"].Puts)
+	})
+
+	t.Run("should update the cache by at.CachePut", func(t *testing.T) {
+		testApp.Put("/users/{id}").WithPath("id", 1).WithJSON(map[string]string{"name": "johnny"}).
+			Expect().Status(http.StatusOK)
+		testApp.Get("/users/{id}").WithPath("id", 1).
+			Expect().Status(http.StatusOK).Body().Contains("johnny")
+		assert.Equal(t, uint64(2), manager.Stats()["users"].Hits)
+	})
+
+	t.Run("should evict the key by at.CacheEvict", func(t *testing.T) {
+		testApp.Delete("/users/{id}").WithPath("id", 1).Expect().Status(http.StatusOK)
+		testApp.Get("/users/{id}").WithPath("id", 1).Expect().Status(http.StatusInternalServerError)
+		assert.Equal(t, uint64(1), manager.Stats()["users"].Evictions)
+	})
+
+	t.Run("should serve the statistics of the caches", func(t *testing.T) {
+		body := testApp.Get("/caches").Expect().Status(http.StatusOK).JSON().Object()
+		body.Value("greetings").Object().Value("hits").Number().Equal(1)
+		body.Value("users").Object().Value("hits").Number().Equal(2)
+		body.Value("users").Object().Value("misses").Number().Equal(4)
+	})
+
+	t.Run("should clear the cache on the actuator endpoint", func(t *testing.T) {
+		testApp.Delete("/caches/{name}").WithPath("name", "greetings").
+			Expect().Status(http.StatusOK).JSON().Object().Value("size").Number().Equal(0)
+		testApp.Delete("/caches/{name}").WithPath("name", "unknown").
+			Expect().Status(http.StatusNotFound)
+	})
+}
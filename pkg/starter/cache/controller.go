@@ -0,0 +1,62 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"net/http"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/at"
+)
+
+// ErrCacheNotFound is responded if the cache does not exist
+var ErrCacheNotFound = web.NewError(http.StatusNotFound, "cache_not_found", "cache not found")
+
+type controller struct {
+	at.RestController
+	at.ManagementEndpoint
+	at.RequestMapping `value:"${actuator.base_path:}/caches" no_context_path:"true"`
+
+	manager *CacheManager
+}
+
+func newController(manager *CacheManager) *controller {
+	return &controller{manager: manager}
+}
+
+// GET /caches
+func (c *controller) Get(struct {
+	at.GetMapping `value:"/"`
+	at.Operation  `id:"caches" description:"the hit and miss statistics of the caches"`
+	at.Produces   `values:"application/json"`
+}) map[string]*Stats {
+	return c.manager.Stats()
+}
+
+// DELETE /caches/{name}
+func (c *controller) Delete(_ struct {
+	at.DeleteMapping `value:"/{name}"`
+	at.Operation     `id:"clearCache" description:"remove all entries of the cache"`
+	at.Produces      `values:"application/json"`
+}, name string) (stats *Stats, err error) {
+	ok, err := c.manager.Clear(name)
+	if err == nil && !ok {
+		err = ErrCacheNotFound
+	}
+	if err == nil {
+		stats = c.manager.Stats()[name]
+	}
+	return
+}
@@ -0,0 +1,216 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hidevopsio/hiboot/pkg/aop"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/inject/annotation"
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+// ErrInvalidCacheName the cache annotation should specify the name of the cache
+var ErrInvalidCacheName = errors.New("[cache] invalid cache annotation, the name of the cache is not specified")
+
+var (
+	// keyVariable is the argument in the key expression, e.g. {0} or {0.ID}
+	keyVariable = regexp.MustCompile(`\{(\d+)((?:\.\w+)*)\}`)
+
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// interceptor caches the results of the methods that are annotated with at.Cacheable, at.CachePut or at.CacheEvict
+type interceptor struct {
+	at.Interceptor
+	at.Cacheable
+	at.CachePut
+	at.CacheEvict
+
+	manager *CacheManager
+}
+
+// operation is the cache operation of the annotation on the invocation
+type operation struct {
+	name       string
+	cache      Cache
+	key        string
+	allEntries bool
+}
+
+func newInterceptor(manager *CacheManager) *interceptor {
+	return &interceptor{manager: manager}
+}
+
+// Intercept returns the cached result of at.Cacheable if it is hit, otherwise it calls the method,
+// then caches its result or evicts the cache once it succeeds
+func (i *interceptor) Intercept(inv *aop.Invocation) {
+	cacheable := i.operation(inv, at.Cacheable{})
+	if cacheable != nil && cacheable.hit(inv) {
+		return
+	}
+	if err := inv.Proceed(); err != nil {
+		return
+	}
+	if cacheable != nil {
+		cacheable.put(inv)
+	}
+	if put := i.operation(inv, at.CachePut{}); put != nil {
+		put.put(inv)
+	}
+	if evict := i.operation(inv, at.CacheEvict{}); evict != nil {
+		evict.evict()
+	}
+}
+
+func (i *interceptor) operation(inv *aop.Invocation, att interface{}) (op *operation) {
+	ann := inv.Annotation(att)
+	if ann == nil {
+		return
+	}
+	var key string
+	op = new(operation)
+	switch a := ann.Field.Value.Interface().(type) {
+	case at.Cacheable:
+		op.name, key = a.AtName, a.AtKey
+	case at.CachePut:
+		op.name, key = a.AtName, a.AtKey
+	case at.CacheEvict:
+		op.name, key, op.allEntries = a.AtName, a.AtKey, a.AtAllEntries
+	}
+	if op.name == "" {
+		log.Errorf("%v: %v", ErrInvalidCacheName, inv.Name())
+		return nil
+	}
+	op.cache = i.manager.Cache(op.name)
+	op.key = evalKey(key, arguments(inv))
+	return
+}
+
+// hit sets the cached value as the result of the invocation
+func (op *operation) hit(inv *aop.Invocation) bool {
+	if !returnsValue(inv) {
+		return false
+	}
+	value, ok, err := op.cache.Get(op.key)
+	if err != nil {
+		log.Warnf("cache %v get %v: %v", op.name, op.key, err)
+		return false
+	}
+	if !ok || value == nil || !reflect.TypeOf(value).AssignableTo(inv.Method.Type.Out(0)) {
+		return false
+	}
+	inv.Results, inv.Err = []interface{}{value}, nil
+	return true
+}
+
+// put caches the first result of the invocation, nil is not cached
+func (op *operation) put(inv *aop.Invocation) {
+	if !returnsValue(inv) || len(inv.Results) == 0 || isNil(inv.Results[0]) {
+		return
+	}
+	if err := op.cache.Put(op.key, inv.Results[0]); err != nil {
+		log.Warnf("cache %v put %v: %v", op.name, op.key, err)
+	}
+}
+
+func (op *operation) evict() {
+	var err error
+	if op.allEntries {
+		err = op.cache.Clear()
+	} else {
+		err = op.cache.Evict(op.key)
+	}
+	if err != nil {
+		log.Warnf("cache %v evict %v: %v", op.name, op.key, err)
+	}
+}
+
+// returnsValue returns true if the first result of the method is not the error
+func returnsValue(inv *aop.Invocation) bool {
+	return inv.Method.Type.NumOut() > 0 && inv.Method.Type.Out(0) != errorType
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// arguments returns the arguments of the invocation without the annotation parameters and the contexts,
+// the contexts are different on each request so that they are never part of the key
+func arguments(inv *aop.Invocation) (args []interface{}) {
+	for _, arg := range inv.Args {
+		if arg != nil && reflect.TypeOf(arg).Kind() == reflect.Struct && annotation.IsAnnotation(arg) {
+			continue
+		}
+		switch arg.(type) {
+		case context.Context, stdcontext.Context:
+			continue
+		}
+		args = append(args, arg)
+	}
+	return
+}
+
+// evalKey replaces the arguments in the key expression, all arguments are the key if the expression is empty
+func evalKey(expr string, args []interface{}) string {
+	if expr == "" {
+		keys := make([]string, len(args))
+		for i, arg := range args {
+			keys[i] = fmt.Sprint(arg)
+		}
+		return strings.Join(keys, ",")
+	}
+	return keyVariable.ReplaceAllStringFunc(expr, func(variable string) string {
+		m := keyVariable.FindStringSubmatch(variable)
+		idx, _ := strconv.Atoi(m[1])
+		if idx >= len(args) {
+			return ""
+		}
+		v := reflect.ValueOf(args[idx])
+		for _, name := range strings.Split(m[2], ".")[1:] {
+			for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+				v = v.Elem()
+			}
+			switch {
+			case v.Kind() == reflect.Struct:
+				v = v.FieldByName(name)
+			case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+				v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			default:
+				v = reflect.Value{}
+			}
+		}
+		if !v.IsValid() || !v.CanInterface() {
+			return ""
+		}
+		return fmt.Sprint(v.Interface())
+	})
+}
@@ -0,0 +1,178 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/at"
+)
+
+// Stats is the statistics of the cache
+type Stats struct {
+	at.Schema `json:"-"`
+	Hits      uint64  `schema:"The number of the cache hits" json:"hits"`
+	Misses    uint64  `schema:"The number of the cache misses" json:"misses"`
+	Puts      uint64  `schema:"The number of the cached values" json:"puts"`
+	Evictions uint64  `schema:"The number of the evicted keys and clears" json:"evictions"`
+	HitRatio  float64 `schema:"The ratio of the hits to all lookups" json:"hit_ratio"`
+	Size      *int    `schema:"The number of the entries, if it is reported by the store" json:"size,omitempty"`
+}
+
+// managedCache records the statistics of the cache
+type managedCache struct {
+	Cache
+
+	hits      uint64
+	misses    uint64
+	puts      uint64
+	evictions uint64
+}
+
+// Get returns the cached value of key, and records the hit or miss
+func (c *managedCache) Get(key string) (value interface{}, ok bool, err error) {
+	value, ok, err = c.Cache.Get(key)
+	if ok {
+		atomic.AddUint64(&c.hits, 1)
+	} else {
+		atomic.AddUint64(&c.misses, 1)
+	}
+	return
+}
+
+// Put caches the value of key
+func (c *managedCache) Put(key string, value interface{}) (err error) {
+	err = c.Cache.Put(key, value)
+	if err == nil {
+		atomic.AddUint64(&c.puts, 1)
+	}
+	return
+}
+
+// Evict removes the cached value of key
+func (c *managedCache) Evict(key string) (err error) {
+	err = c.Cache.Evict(key)
+	if err == nil {
+		atomic.AddUint64(&c.evictions, 1)
+	}
+	return
+}
+
+// Clear removes all entries of the cache
+func (c *managedCache) Clear() (err error) {
+	err = c.Cache.Clear()
+	if err == nil {
+		atomic.AddUint64(&c.evictions, 1)
+	}
+	return
+}
+
+func (c *managedCache) stats() (stats *Stats) {
+	stats = &Stats{
+		Hits:      atomic.LoadUint64(&c.hits),
+		Misses:    atomic.LoadUint64(&c.misses),
+		Puts:      atomic.LoadUint64(&c.puts),
+		Evictions: atomic.LoadUint64(&c.evictions),
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+	if l, ok := c.Cache.(interface{ Len() int }); ok {
+		size := l.Len()
+		stats.Size = &size
+	}
+	return
+}
+
+// CacheManager manages the named caches that are created by the Store on demand
+type CacheManager struct {
+	store      Store
+	properties *Properties
+
+	mu     sync.Mutex
+	caches map[string]*managedCache
+}
+
+// NewCacheManager returns the CacheManager, the caches are specified by the properties cache.*
+func NewCacheManager(store Store, properties *Properties) *CacheManager {
+	if properties == nil {
+		properties = new(Properties)
+	}
+	return &CacheManager{
+		store:      store,
+		properties: properties,
+		caches:     make(map[string]*managedCache),
+	}
+}
+
+// Cache returns the cache of name, it is created once it is requested for the first time
+func (m *CacheManager) Cache(name string) Cache {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.caches[name]
+	if !ok {
+		ttl, maxSize := m.properties.TTL, m.properties.MaxSize
+		if spec, ok := m.properties.Caches[name]; ok {
+			if spec.TTL != 0 {
+				ttl = spec.TTL
+			}
+			if spec.MaxSize != 0 {
+				maxSize = spec.MaxSize
+			}
+		}
+		c = &managedCache{Cache: m.store.Cache(name, time.Duration(ttl)*time.Second, maxSize)}
+		m.caches[name] = c
+	}
+	return c
+}
+
+// Names returns the sorted names of the caches
+func (m *CacheManager) Names() (names []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for name := range m.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// Stats returns the statistics of the caches by name
+func (m *CacheManager) Stats() map[string]*Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make(map[string]*Stats, len(m.caches))
+	for name, c := range m.caches {
+		stats[name] = c.stats()
+	}
+	return stats
+}
+
+// Clear removes all entries of the cache of name, it returns false if the cache does not exist
+func (m *CacheManager) Clear(name string) (ok bool, err error) {
+	m.mu.Lock()
+	c, ok := m.caches[name]
+	m.mu.Unlock()
+	if ok {
+		err = c.Clear()
+	}
+	return
+}
@@ -0,0 +1,39 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import "github.com/hidevopsio/hiboot/pkg/at"
+
+// Spec is the spec of the named cache, the zero values fall back to the defaults
+type Spec struct {
+	// MaxSize is the max number of entries of the cache
+	MaxSize int `json:"max_size"`
+	// TTL is the time to live of the entries in seconds
+	TTL int64 `json:"ttl"`
+}
+
+// Properties the cache properties
+type Properties struct {
+	at.ConfigurationProperties `value:"cache"`
+	at.AutoWired
+
+	// MaxSize is the default max number of entries of each cache, the least recently used entries are evicted
+	// once it is exceeded, the cache is unbounded if it is negative
+	MaxSize int `json:"max_size" default:"1000"`
+	// TTL is the default time to live of the entries in seconds, the entries never expire if it is negative
+	TTL int64 `json:"ttl" default:"600"`
+	// Caches are the specs of the named caches, e.g. cache.caches.users.ttl
+	Caches map[string]Spec `json:"caches"`
+}
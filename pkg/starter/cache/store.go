@@ -0,0 +1,150 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache is the named cache, the in-memory LRU cache is used by default
+type Cache interface {
+	// Get returns the cached value of key, ok is false if it is not cached or expired
+	Get(key string) (value interface{}, ok bool, err error)
+	// Put caches the value of key
+	Put(key string, value interface{}) error
+	// Evict removes the cached value of key
+	Evict(key string) error
+	// Clear removes all entries of the cache
+	Clear() error
+}
+
+// Store creates the named caches, the in-memory store is used by default,
+// an external store, e.g. redis, can be provided by registering a constructor that returns cache.Store
+type Store interface {
+	// Cache returns the cache of name, the entries expire after ttl, and the cache holds at most maxSize entries,
+	// ttl or maxSize is not limited if it is not positive
+	Cache(name string, ttl time.Duration, maxSize int) Cache
+}
+
+type memoryStore struct {
+}
+
+// NewMemoryStore returns the in-memory Store that creates the LRU caches
+func NewMemoryStore() Store {
+	return &memoryStore{}
+}
+
+// Cache returns the new LRU cache
+func (s *memoryStore) Cache(name string, ttl time.Duration, maxSize int) Cache {
+	return newLRUCache(ttl, maxSize)
+}
+
+type entry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+type lruCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	entries map[string]*list.Element
+	order   *list.List
+	now     func() time.Time
+}
+
+func newLRUCache(ttl time.Duration, maxSize int) *lruCache {
+	return &lruCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached value of key, and marks it as the most recently used one
+func (c *lruCache) Get(key string) (value interface{}, ok bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	e := elem.Value.(*entry)
+	if c.ttl > 0 && c.now().After(e.expires) {
+		c.remove(elem)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(elem)
+	return e.value, true, nil
+}
+
+// Put caches the value of key, the least recently used entry is evicted if the cache is full
+func (c *lruCache) Put(key string, value interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(c.ttl)
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry)
+		e.value, e.expires = value, expires
+		c.order.MoveToFront(elem)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(&entry{key: key, value: value, expires: expires})
+	for c.maxSize > 0 && c.order.Len() > c.maxSize {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// Evict removes the cached value of key
+func (c *lruCache) Evict(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	return nil
+}
+
+// Clear removes all entries
+func (c *lruCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	return nil
+}
+
+// Len returns the number of entries, including the expired ones that are not evicted yet
+func (c *lruCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *lruCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*entry).key)
+}
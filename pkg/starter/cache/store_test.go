@@ -0,0 +1,137 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func TestLRUCache(t *testing.T) {
+	c := &clock{t: time.Unix(1000, 0)}
+	lru := NewMemoryStore().Cache("test", 10*time.Second, 2).(*lruCache)
+	lru.now = c.now
+
+	t.Run("should evict the least recently used entry", func(t *testing.T) {
+		_ = lru.Put("a", 1)
+		_ = lru.Put("b", 2)
+		_, ok, _ := lru.Get("a")
+		assert.Equal(t, true, ok)
+		_ = lru.Put("c", 3)
+		_, ok, _ = lru.Get("b")
+		assert.Equal(t, false, ok)
+		v, ok, _ := lru.Get("a")
+		assert.Equal(t, true, ok)
+		assert.Equal(t, 1, v)
+		assert.Equal(t, 2, lru.Len())
+	})
+
+	t.Run("should expire the entry after ttl", func(t *testing.T) {
+		c.t = c.t.Add(5 * time.Second)
+		_ = lru.Put("a", 4)
+		c.t = c.t.Add(6 * time.Second)
+		_, ok, _ := lru.Get("c")
+		assert.Equal(t, false, ok)
+		v, ok, _ := lru.Get("a")
+		assert.Equal(t, true, ok)
+		assert.Equal(t, 4, v)
+	})
+
+	t.Run("should evict and clear the entries", func(t *testing.T) {
+		_ = lru.Put("b", 2)
+		_ = lru.Evict("a")
+		_, ok, _ := lru.Get("a")
+		assert.Equal(t, false, ok)
+		_ = lru.Clear()
+		assert.Equal(t, 0, lru.Len())
+	})
+}
+
+func TestUnboundedCache(t *testing.T) {
+	lru := NewMemoryStore().Cache("test", 0, 0).(*lruCache)
+	for i := 0; i < 100; i++ {
+		_ = lru.Put(string(rune('a'+i)), i)
+	}
+	assert.Equal(t, 100, lru.Len())
+}
+
+func TestCacheManager(t *testing.T) {
+	m := NewCacheManager(NewMemoryStore(), &Properties{
+		MaxSize: 10,
+		TTL:     60,
+		Caches:  map[string]Spec{"users": {MaxSize: 1}},
+	})
+
+	users := m.Cache("users")
+	assert.Equal(t, users, m.Cache("users"))
+	_ = users.Put("a", 1)
+	_ = users.Put("b", 2)
+	_, _, _ = users.Get("a")
+	_, _, _ = users.Get("b")
+	_ = m.Cache("orders").Evict("a")
+
+	t.Run("should create the cache by its spec", func(t *testing.T) {
+		lru := users.(*managedCache).Cache.(*lruCache)
+		assert.Equal(t, 1, lru.maxSize)
+		assert.Equal(t, time.Minute, lru.ttl)
+	})
+
+	t.Run("should report the statistics", func(t *testing.T) {
+		assert.Equal(t, []string{"orders", "users"}, m.Names())
+		stats := m.Stats()["users"]
+		assert.Equal(t, uint64(1), stats.Hits)
+		assert.Equal(t, uint64(1), stats.Misses)
+		assert.Equal(t, uint64(2), stats.Puts)
+		assert.Equal(t, 0.5, stats.HitRatio)
+		assert.Equal(t, 1, *stats.Size)
+		assert.Equal(t, uint64(1), m.Stats()["orders"].Evictions)
+	})
+
+	t.Run("should clear the cache", func(t *testing.T) {
+		ok, err := m.Clear("users")
+		assert.Equal(t, true, ok)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, 0, *m.Stats()["users"].Size)
+		ok, _ = m.Clear("unknown")
+		assert.Equal(t, false, ok)
+	})
+}
+
+type user struct {
+	ID      int
+	Profile map[string]string
+	secret  string
+}
+
+func TestEvalKey(t *testing.T) {
+	u := &user{ID: 7, Profile: map[string]string{"team": "hiboot"}, secret: "x"}
+	assert.Equal(t, "user:7", evalKey("user:{0.ID}", []interface{}{u}))
+	assert.Equal(t, "hiboot/7", evalKey("{0.Profile.team}/{1}", []interface{}{u, 7}))
+	assert.Equal(t, "a,1", evalKey("", []interface{}{"a", 1}))
+	assert.Equal(t, "user:", evalKey("user:{2}", []interface{}{u}))
+	assert.Equal(t, "", evalKey("{0.secret}", []interface{}{u}))
+	assert.Equal(t, "", evalKey("{0.Name}", []interface{}{u}))
+	assert.Equal(t, "", evalKey("{0.ID}", []interface{}{(*user)(nil)}))
+}
@@ -0,0 +1,50 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compress provides the hiboot starter for response compression,
+// it negotiates the Accept-Encoding header and compresses the responses of the configured content types
+// with gzip or deflate, the gzip and deflate request bodies are decompressed as well.
+// Brotli is not built in, as it is not in the standard library, its encoder can be registered by RegisterEncoder
+package compress
+
+import (
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/at"
+)
+
+const (
+	// Profile is the profile of compress, it should be as same as the package name
+	Profile = "compress"
+)
+
+type configuration struct {
+	at.AutoConfiguration
+
+	Properties *Properties
+}
+
+func init() {
+	app.Register(newConfiguration)
+}
+
+func newConfiguration() *configuration {
+	return &configuration{}
+}
+
+// Middleware compresses the responses of all requests
+func (c *configuration) Middleware(applicationContext app.ApplicationContext) (mw *Middleware) {
+	mw = newMiddleware(c.Properties)
+	applicationContext.Use(mw.Serve)
+	return
+}
@@ -0,0 +1,183 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compress_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/starter/compress"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	Name string `json:"name"`
+}
+
+type userRequest struct {
+	at.RequestBody
+	Name string `json:"name"`
+}
+
+type userController struct {
+	at.RestController
+	at.RequestMapping `value:"/user"`
+}
+
+func newUserController() *userController {
+	return &userController{}
+}
+
+func (c *userController) Get(_ struct {
+	at.GetMapping `value:"/"`
+}) []*user {
+	var users []*user
+	for i := 0; i < 100; i++ {
+		users = append(users, &user{Name: "john.deng"})
+	}
+	return users
+}
+
+func (c *userController) GetYaml(_ struct {
+	at.GetMapping `value:"/yaml"`
+	at.Produces   `values:"application/json,application/yaml"`
+}) []*user {
+	return c.Get(struct {
+		at.GetMapping `value:"/"`
+	}{})
+}
+
+func (c *userController) GetMe(_ struct {
+	at.GetMapping `value:"/me"`
+}) *user {
+	return &user{Name: "john.deng"}
+}
+
+func (c *userController) GetAvatar(_ struct {
+	at.GetMapping `value:"/avatar"`
+}) *web.Download {
+	d := web.NewDownload(bytes.NewReader(make([]byte, 4096)), "avatar.png")
+	d.ContentType = "image/png"
+	return d
+}
+
+func (c *userController) GetEvents(_ struct {
+	at.GetMapping `value:"/events"`
+}) <-chan *web.Event {
+	ch := make(chan *web.Event)
+	go func() {
+		defer close(ch)
+		ch <- &web.Event{ID: "1", Data: strings.Repeat("hello", 500)}
+	}()
+	return ch
+}
+
+func (c *userController) Post(_ struct {
+	at.PostMapping `value:"/"`
+}, request *userRequest) *user {
+	return &user{Name: request.Name}
+}
+
+func gzipped(data string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, _ = w.Write([]byte(data))
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+func TestCompress(t *testing.T) {
+	testApp := web.NewTestApp(newUserController).
+		SetProperty(app.ProfilesInclude, web.Profile, compress.Profile).
+		Run(t)
+
+	t.Run("should compress the large json response with gzip", func(t *testing.T) {
+		resp := testApp.Get("/user").WithHeader("Accept-Encoding", "gzip, deflate").
+			Expect().Status(http.StatusOK)
+		resp.Header("Content-Encoding").Equal(compress.Gzip)
+		resp.Header("Vary").Equal("Accept-Encoding")
+		r, err := gzip.NewReader(strings.NewReader(resp.Body().Raw()))
+		assert.Equal(t, nil, err)
+		data, _ := io.ReadAll(r)
+		assert.Contains(t, string(data), "john.deng")
+	})
+
+	t.Run("should compress the large yaml response", func(t *testing.T) {
+		resp := testApp.Get("/user/yaml").WithHeader("Accept-Encoding", "gzip").
+			WithHeader("Accept", "application/yaml").
+			Expect().Status(http.StatusOK)
+		resp.Header("Content-Type").Contains("application/yaml")
+		resp.Header("Content-Encoding").Equal(compress.Gzip)
+	})
+
+	t.Run("should compress with the encoding of the highest quality", func(t *testing.T) {
+		resp := testApp.Get("/user").WithHeader("Accept-Encoding", "gzip;q=0.5, deflate").
+			Expect().Status(http.StatusOK)
+		resp.Header("Content-Encoding").Equal(compress.Deflate)
+		data, _ := io.ReadAll(flate.NewReader(strings.NewReader(resp.Body().Raw())))
+		assert.Contains(t, string(data), "john.deng")
+	})
+
+	t.Run("should not compress if the client does not accept it", func(t *testing.T) {
+		testApp.Get("/user").Expect().Status(http.StatusOK).
+			Header("Content-Encoding").Empty()
+		testApp.Get("/user").WithHeader("Accept-Encoding", "br, gzip;q=0").
+			Expect().Status(http.StatusOK).Header("Content-Encoding").Empty()
+	})
+
+	t.Run("should not compress the small response", func(t *testing.T) {
+		resp := testApp.Get("/user/me").WithHeader("Accept-Encoding", "gzip").
+			Expect().Status(http.StatusOK)
+		resp.Header("Content-Encoding").Empty()
+		resp.JSON().Path("$.name").Equal("john.deng")
+	})
+
+	t.Run("should not compress the content type that is not configured", func(t *testing.T) {
+		testApp.Get("/user/avatar").WithHeader("Accept-Encoding", "gzip").
+			Expect().Status(http.StatusOK).Header("Content-Encoding").Empty()
+	})
+
+	t.Run("should not compress server-sent events", func(t *testing.T) {
+		resp := testApp.Get("/user/events").WithHeader("Accept-Encoding", "gzip").
+			Expect().Status(http.StatusOK)
+		resp.Header("Content-Encoding").Empty()
+		resp.Body().Contains("data: hello")
+	})
+
+	t.Run("should decompress the gzip request body", func(t *testing.T) {
+		testApp.Post("/user").
+			WithHeader("Content-Type", "application/json").
+			WithHeader("Content-Encoding", "gzip").
+			WithBytes(gzipped(`{"name":"mike"}`)).
+			Expect().Status(http.StatusOK).
+			JSON().Path("$.name").Equal("mike")
+	})
+
+	t.Run("should reject the invalid gzip request body", func(t *testing.T) {
+		testApp.Post("/user").
+			WithHeader("Content-Type", "application/json").
+			WithHeader("Content-Encoding", "gzip").
+			WithBytes([]byte(`{"name":"mike"}`)).
+			Expect().Status(http.StatusBadRequest)
+	})
+}
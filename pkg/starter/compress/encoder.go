@@ -0,0 +1,121 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// Gzip is the gzip content encoding
+	Gzip = "gzip"
+	// Deflate is the deflate content encoding
+	Deflate = "deflate"
+	// Brotli is the brotli content encoding, the encoder is not built in
+	Brotli = "br"
+)
+
+// Encoder returns the writer that compresses the data written to w at the compression level,
+// the writer may implement Flush() error to support the streaming responses
+type Encoder func(w io.Writer, level int) (io.WriteCloser, error)
+
+var (
+	encoders = map[string]Encoder{
+		Gzip: func(w io.Writer, level int) (io.WriteCloser, error) {
+			return gzip.NewWriterLevel(w, level)
+		},
+		Deflate: func(w io.Writer, level int) (io.WriteCloser, error) {
+			return flate.NewWriter(w, level)
+		},
+	}
+	encoderMu sync.RWMutex
+)
+
+// RegisterEncoder registers the encoder of the content encoding, e.g. the brotli encoder:
+//
+//	compress.RegisterEncoder(compress.Brotli, func(w io.Writer, level int) (io.WriteCloser, error) {
+//		return brotli.NewWriterLevel(w, level), nil
+//	})
+func RegisterEncoder(encoding string, encoder Encoder) {
+	encoderMu.Lock()
+	defer encoderMu.Unlock()
+	encoders[encoding] = encoder
+}
+
+func getEncoder(encoding string) (encoder Encoder, ok bool) {
+	encoderMu.RLock()
+	defer encoderMu.RUnlock()
+	encoder, ok = encoders[encoding]
+	return
+}
+
+type acceptEncoding struct {
+	encoding string
+	q        float64
+}
+
+// negotiate returns the encoding of the highest quality in the Accept-Encoding header,
+// the order of the encodings breaks a tie, empty string is returned if none is acceptable
+func negotiate(accept string, encodings []string) (encoding string) {
+	var accepted []acceptEncoding
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		ae := acceptEncoding{encoding: strings.ToLower(strings.TrimSpace(params[0])), q: 1}
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if q, err := strconv.ParseFloat(p[2:], 64); err == nil {
+					ae.q = q
+				}
+			}
+		}
+		if ae.encoding != "" {
+			accepted = append(accepted, ae)
+		}
+	}
+
+	var candidates []acceptEncoding
+	for _, enc := range encodings {
+		if _, ok := getEncoder(enc); !ok {
+			continue
+		}
+		q := -1.0
+		for _, ae := range accepted {
+			if ae.encoding == enc {
+				q = ae.q
+				break
+			}
+			if ae.encoding == "*" {
+				q = ae.q
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, acceptEncoding{encoding: enc, q: q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	if len(candidates) != 0 {
+		encoding = candidates[0].encoding
+	}
+	return
+}
@@ -0,0 +1,31 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	encodings := []string{Brotli, Gzip, Deflate}
+	assert.Equal(t, Gzip, negotiate("gzip, deflate, br", encodings))
+	assert.Equal(t, Deflate, negotiate("gzip;q=0.5, deflate", encodings))
+	assert.Equal(t, Gzip, negotiate("*", encodings))
+	assert.Equal(t, Deflate, negotiate("gzip;q=0, *;q=0.1", encodings))
+	assert.Equal(t, "", negotiate("identity", encodings))
+	assert.Equal(t, "", negotiate("", encodings))
+}
@@ -0,0 +1,111 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+// Middleware compresses the responses and decompresses the request bodies
+type Middleware struct {
+	properties *Properties
+}
+
+func newMiddleware(properties *Properties) *Middleware {
+	for _, enc := range properties.Encodings {
+		if _, ok := getEncoder(enc); !ok {
+			log.Warnf("[compress] encoding %v is not registered, it is ignored", enc)
+		}
+	}
+	return &Middleware{properties: properties}
+}
+
+func (m *Middleware) matchContentType(mediaType string) bool {
+	for _, ct := range m.properties.ContentTypes {
+		if ct == mediaType || (strings.HasSuffix(ct, "/*") && strings.HasPrefix(mediaType, ct[:len(ct)-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *readCloser) Close() (err error) {
+	for _, c := range r.closers {
+		if e := c.Close(); e != nil {
+			err = e
+		}
+	}
+	return
+}
+
+// decompress replaces the gzip or deflate encoded request body with the decompressed one
+func (m *Middleware) decompress(ctx context.Context) (code int) {
+	req := ctx.Request()
+	var r io.ReadCloser
+	switch strings.ToLower(strings.TrimSpace(req.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+		return
+	case Gzip, "x-gzip":
+		gr, err := gzip.NewReader(req.Body)
+		if err != nil {
+			return http.StatusBadRequest
+		}
+		r = gr
+	case Deflate:
+		r = flate.NewReader(req.Body)
+	default:
+		return http.StatusUnsupportedMediaType
+	}
+
+	var body io.Reader = r
+	if m.properties.MaxRequestSize > 0 {
+		body = http.MaxBytesReader(ctx.ResponseWriter(), r, m.properties.MaxRequestSize)
+	}
+	req.Body = &readCloser{Reader: body, closers: []io.Closer{r, req.Body}}
+	req.Header.Del("Content-Encoding")
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	return
+}
+
+// Serve negotiates the Accept-Encoding header and compresses the response
+func (m *Middleware) Serve(ctx context.Context) {
+	if m.properties.DecompressRequest {
+		if code := m.decompress(ctx); code != 0 {
+			ctx.ResponseError(http.StatusText(code), code)
+			return
+		}
+	}
+
+	// the websocket upgrade and HEAD requests are not compressed
+	if ctx.Method() != http.MethodHead && ctx.GetHeader("Upgrade") == "" {
+		if encoding := negotiate(ctx.GetHeader("Accept-Encoding"), m.properties.Encodings); encoding != "" {
+			ctx.ResetResponseWriter(newResponseWriter(ctx.ResponseWriter(), m, encoding))
+		}
+	}
+	ctx.Next()
+}
@@ -0,0 +1,39 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compress
+
+import (
+	"github.com/hidevopsio/hiboot/pkg/at"
+)
+
+// Properties the compress properties
+type Properties struct {
+	at.ConfigurationProperties `value:"compress"`
+	at.AutoWired
+
+	// Encodings is the list of encodings in the order of preference, gzip and deflate are built in,
+	// brotli is not built in, br is used only if its encoder is registered by RegisterEncoder
+	Encodings []string `json:"encodings" default:"gzip,deflate"`
+	// Level is the compression level, -1 is the default level of the encoder
+	Level int `json:"level" default:"-1"`
+	// MinSize is the min size in bytes of the response that is compressed
+	MinSize int `json:"min_size" default:"1024"`
+	// ContentTypes is the list of the content types that are compressed, wildcard subtype is supported, e.g. text/*
+	ContentTypes []string `json:"content_types" default:"text/*,application/json,application/problem+json,application/xml,application/yaml,application/x-yaml,application/javascript"`
+	// DecompressRequest decompresses the gzip or deflate encoded request body
+	DecompressRequest bool `json:"decompress_request" default:"true"`
+	// MaxRequestSize is the max size in bytes of the decompressed request body
+	MaxRequestSize int64 `json:"max_request_size" default:"10485760"`
+}
@@ -0,0 +1,162 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compress
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/log"
+	"github.com/hidevopsio/iris/context"
+)
+
+type flusher interface {
+	Flush() error
+}
+
+// responseWriter buffers the response until it reaches the min size, the response is flushed or ended,
+// then decides if the response is compressed by its status code and headers
+type responseWriter struct {
+	context.ResponseWriter
+
+	middleware *Middleware
+	encoding   string
+	buf        []byte
+	started    bool
+	closed     bool
+	encoder    io.WriteCloser
+}
+
+func newResponseWriter(w context.ResponseWriter, middleware *Middleware, encoding string) *responseWriter {
+	return &responseWriter{
+		ResponseWriter: w,
+		middleware:     middleware,
+		encoding:       encoding,
+	}
+}
+
+// compressible checks if the response should be compressed, the min size is only checked on the end of the response,
+// the streaming responses are compressed if the content type matches, except the server-sent events
+func (w *responseWriter) compressible(end bool) bool {
+	h := w.ResponseWriter.Header()
+	code := w.ResponseWriter.StatusCode()
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified ||
+		code == http.StatusPartialContent || h.Get("Content-Range") != "" {
+		return false
+	}
+	// the body is already compressed
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	if len(w.buf) == 0 && end {
+		return false
+	}
+
+	contentType := h.Get("Content-Type")
+	if contentType == "" {
+		// the content type can not be detected by net/http once the body is compressed
+		contentType = http.DetectContentType(w.buf)
+		h.Set("Content-Type", contentType)
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == web.ContentTypeEventStream || !w.middleware.matchContentType(mediaType) {
+		return false
+	}
+	h.Add("Vary", "Accept-Encoding")
+	return !end || len(w.buf) >= w.middleware.properties.MinSize
+}
+
+func (w *responseWriter) start(end bool) {
+	w.started = true
+	if w.compressible(end) {
+		encoder, _ := getEncoder(w.encoding)
+		cw, err := encoder(w.ResponseWriter, w.middleware.properties.Level)
+		if err == nil {
+			h := w.ResponseWriter.Header()
+			h.Set("Content-Encoding", w.encoding)
+			h.Del("Content-Length")
+			w.encoder = cw
+		} else {
+			log.Errorf("[compress] %v", err)
+		}
+	}
+	if len(w.buf) != 0 {
+		_, _ = w.write(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *responseWriter) write(contents []byte) (int, error) {
+	if w.encoder != nil {
+		return w.encoder.Write(contents)
+	}
+	return w.ResponseWriter.Write(contents)
+}
+
+// Write buffers the contents until the response is decided to be compressed or not
+func (w *responseWriter) Write(contents []byte) (int, error) {
+	if !w.started {
+		w.buf = append(w.buf, contents...)
+		if len(w.buf) >= w.middleware.properties.MinSize {
+			w.start(false)
+		}
+		return len(contents), nil
+	}
+	return w.write(contents)
+}
+
+// Writef formats according to a format specifier and writes to the response
+func (w *responseWriter) Writef(format string, a ...interface{}) (n int, err error) {
+	return fmt.Fprintf(w, format, a...)
+}
+
+// WriteString writes a simple string to the response
+func (w *responseWriter) WriteString(s string) (n int, err error) {
+	return w.Write([]byte(s))
+}
+
+// Written returns the buffered length before the response is started, so that the status code is not fired twice
+func (w *responseWriter) Written() int {
+	if !w.started && len(w.buf) != 0 {
+		return len(w.buf)
+	}
+	return w.ResponseWriter.Written()
+}
+
+// Flush sends the buffered data of the streaming response to the client
+func (w *responseWriter) Flush() {
+	if !w.started {
+		w.start(false)
+	}
+	if f, ok := w.encoder.(flusher); ok && !w.closed {
+		_ = f.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// FlushResponse ends the compressed stream before the response is flushed
+func (w *responseWriter) FlushResponse() {
+	if !w.started {
+		w.start(true)
+	}
+	if w.encoder != nil && !w.closed {
+		w.closed = true
+		_ = w.encoder.Close()
+	}
+	w.ResponseWriter.FlushResponse()
+}
@@ -0,0 +1,122 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+)
+
+type subject struct {
+	authenticated bool
+	roles         []string
+	permissions   []string
+}
+
+// IsAuthenticated returns true if the request has a valid jwt token
+func (s *subject) IsAuthenticated() bool {
+	return s.authenticated
+}
+
+// HasRole returns true if the roles claim of the jwt token contains the role
+func (s *subject) HasRole(role string) bool {
+	for _, r := range s.roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPermitted returns true if any permission in the permissions claim of the jwt token implies the permission,
+// wildcard is supported, e.g. user:* implies user:read
+func (s *subject) IsPermitted(permission string) bool {
+	for _, p := range s.permissions {
+		if implies(p, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizer is the default web.Authorizer that reads roles and permissions from jwt claims
+type authorizer struct {
+	properties *Properties
+	middleware *Middleware
+}
+
+func newAuthorizer(properties *Properties, middleware *Middleware) *authorizer {
+	return &authorizer{
+		properties: properties,
+		middleware: middleware,
+	}
+}
+
+// Subject returns the subject that is parsed from the jwt token of current request
+func (a *authorizer) Subject(ctx context.Context) web.Subject {
+	s := new(subject)
+	// the jwt middleware is not applied to regular rest controller, so check the token here
+	if ctx.Values().Get(a.middleware.Config.ContextKey) == nil {
+		if err := a.middleware.CheckJWT(ctx); err != nil {
+			return s
+		}
+	}
+
+	claims, ok := newTokenProperties(ctx).GetAll()
+	if ok {
+		s.authenticated = true
+		s.roles = claimValues(claims[a.properties.RolesClaim])
+		s.permissions = claimValues(claims[a.properties.PermissionsClaim])
+	}
+	return s
+}
+
+// claimValues accepts both array claim and comma separated string claim
+func claimValues(claim interface{}) (values []string) {
+	switch claim.(type) {
+	case nil:
+	case []interface{}:
+		for _, v := range claim.([]interface{}) {
+			values = append(values, fmt.Sprintf("%v", v))
+		}
+	case []string:
+		values = claim.([]string)
+	default:
+		for _, v := range strings.Split(fmt.Sprintf("%v", claim), ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return
+}
+
+// implies checks if the granted permission implies the required permission, the parts are separated by colon
+func implies(granted, required string) bool {
+	gp := strings.Split(granted, ":")
+	rp := strings.Split(required, ":")
+	for i, g := range gp {
+		if g == "*" {
+			continue
+		}
+		if i >= len(rp) || g != rp[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/starter/jwt"
+)
+
+type securedController struct {
+	at.RestController
+	at.RequestMapping `value:"/secured"`
+}
+
+func newSecuredController() *securedController {
+	return &securedController{}
+}
+
+func (c *securedController) Profile(_ struct {
+	at.GetMapping `value:"/profile"`
+	at.RequiresAuthentication
+}) string {
+	return "profile"
+}
+
+func (c *securedController) Admin(_ struct {
+	at.GetMapping    `value:"/admin"`
+	at.RequiresRoles `values:"admin"`
+}) string {
+	return "admin"
+}
+
+func (c *securedController) Report(_ struct {
+	at.GetMapping          `value:"/report"`
+	at.RequiresRoles       `values:"admin,auditor" logical:"or"`
+	at.RequiresPermissions `values:"report:read"`
+}) string {
+	return "report"
+}
+
+func (c *securedController) Delete(_ struct {
+	at.DeleteMapping       `value:"/report"`
+	at.RequiresPermissions `values:"report:read,report:delete"`
+}) string {
+	return "deleted"
+}
+
+func (c *securedController) Signup(_ struct {
+	at.PostMapping `value:"/signup"`
+	at.RequiresGuest
+}) string {
+	return "signup"
+}
+
+func TestAuthorizer(t *testing.T) {
+	testApp := web.NewTestApp(newSecuredController).SetProperty(app.ProfilesInclude, web.Profile, jwt.Profile).Run(t)
+	token := testApp.(app.ApplicationContext).GetInstance(new(jwt.Token)).(jwt.Token)
+	tokenStr, err := token.Generate(jwt.Map{
+		"username":    "johndoe",
+		"roles":       []string{"auditor"},
+		"permissions": "report:*",
+	}, 10, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bearer := fmt.Sprintf("Bearer %v", tokenStr)
+
+	t.Run("should return http.StatusUnauthorized without token", func(t *testing.T) {
+		testApp.Get("/secured/profile").
+			Expect().Status(http.StatusUnauthorized).
+			JSON().Object().Value("code").Equal(http.StatusUnauthorized)
+	})
+
+	t.Run("should pass at.RequiresAuthentication with token", func(t *testing.T) {
+		testApp.Get("/secured/profile").
+			WithHeader("Authorization", bearer).
+			Expect().Status(http.StatusOK)
+	})
+
+	t.Run("should return http.StatusForbidden if user does not have role admin", func(t *testing.T) {
+		testApp.Get("/secured/admin").
+			WithHeader("Authorization", bearer).
+			Expect().Status(http.StatusForbidden).
+			JSON().Object().Value("code").Equal(http.StatusForbidden)
+	})
+
+	t.Run("should pass roles with logical or and wildcard permission", func(t *testing.T) {
+		testApp.Get("/secured/report").
+			WithHeader("Authorization", bearer).
+			Expect().Status(http.StatusOK)
+	})
+
+	t.Run("should pass all permissions with logical and", func(t *testing.T) {
+		testApp.Delete("/secured/report").
+			WithHeader("Authorization", bearer).
+			Expect().Status(http.StatusOK)
+	})
+
+	t.Run("should allow guest only", func(t *testing.T) {
+		testApp.Post("/secured/signup").
+			Expect().Status(http.StatusOK)
+		testApp.Post("/secured/signup").
+			WithHeader("Authorization", bearer).
+			Expect().Status(http.StatusForbidden)
+	})
+}
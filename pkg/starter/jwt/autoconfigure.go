@@ -18,6 +18,7 @@ package jwt
 import (
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
 	"github.com/hidevopsio/hiboot/pkg/app/web/context"
 	"github.com/hidevopsio/hiboot/pkg/at"
 )
@@ -63,6 +64,11 @@ func (c *configuration) Token() Token {
 	return t
 }
 
+// Authorizer is the default authorizer that reads roles and permissions from jwt claims
+func (c *configuration) Authorizer(middleware *Middleware) web.Authorizer {
+	return newAuthorizer(c.Properties, middleware)
+}
+
 // TokenProperties is the token properties parser
 func (c *configuration) TokenProperties(context context.Context) *TokenProperties {
 	return newTokenProperties(context)
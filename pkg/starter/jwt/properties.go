@@ -23,4 +23,9 @@ type Properties struct {
 
 	PrivateKeyPath string `json:"private_key_path" default:"config/ssl/app.rsa"`
 	PublicKeyPath  string `json:"public_key_path" default:"config/ssl/app.rsa.pub"`
+
+	// RolesClaim is the claim name of the roles that is checked by at.RequiresRoles
+	RolesClaim string `json:"roles_claim" default:"roles"`
+	// PermissionsClaim is the claim name of the permissions that is checked by at.RequiresPermissions
+	PermissionsClaim string `json:"permissions_claim" default:"permissions"`
 }
@@ -0,0 +1,133 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+const (
+	// FormatText is the access log format of text line
+	FormatText = "text"
+	// FormatJSON is the access log format of json line
+	FormatJSON = "json"
+
+	maxRequestIDLength = 128
+)
+
+// accessLogOutput is where the access log is written to, it is the output of the logs, e.g. the log file
+var accessLogOutput = log.Writer()
+
+type accessLogEntry struct {
+	Time      string  `json:"time"`
+	RequestID string  `json:"request_id"`
+	Method    string  `json:"method"`
+	Route     string  `json:"route"`
+	Path      string  `json:"path"`
+	Status    int     `json:"status"`
+	Latency   float64 `json:"latency_ms"`
+	Bytes     int     `json:"bytes"`
+	ClientIP  string  `json:"client_ip"`
+}
+
+type accessLog struct {
+	mu     sync.Mutex
+	out    io.Writer
+	format string
+	header string
+}
+
+func newAccessLog(out io.Writer, format, header string) *accessLog {
+	return &accessLog{
+		out:    out,
+		format: format,
+		header: header,
+	}
+}
+
+// Serve assigns or propagates the request id, then logs the request once it is served
+func (a *accessLog) Serve(ctx context.Context) {
+	start := time.Now()
+
+	requestID := ctx.GetHeader(a.header)
+	if !isValidRequestID(requestID) {
+		requestID = newRequestID()
+	}
+	ctx.SetRequestID(requestID)
+	ctx.Header(a.header, requestID)
+
+	ctx.Next()
+
+	entry := &accessLogEntry{
+		Time:      start.Format(time.RFC3339),
+		RequestID: requestID,
+		Method:    ctx.Method(),
+		Route:     ctx.Path(),
+		Path:      ctx.Path(),
+		Status:    ctx.GetStatusCode(),
+		Latency:   float64(time.Since(start).Microseconds()) / 1000,
+		Bytes:     ctx.ResponseWriter().Written(),
+		ClientIP:  ctx.RemoteAddr(),
+	}
+	if r := ctx.GetCurrentRoute(); r != nil {
+		entry.Route = r.Path()
+	}
+	if entry.Bytes < 0 {
+		entry.Bytes = 0
+	}
+	a.write(entry)
+}
+
+func (a *accessLog) write(entry *accessLogEntry) {
+	var line []byte
+	if a.format == FormatJSON {
+		line, _ = json.Marshal(entry)
+		line = append(line, '\n')
+	} else {
+		line = []byte(fmt.Sprintf("%v | %v | %v %v | %v | %.3fms | %vB | %v\n",
+			entry.Time, entry.RequestID, entry.Method, entry.Route, entry.Status, entry.Latency, entry.Bytes, entry.ClientIP))
+	}
+	a.mu.Lock()
+	_, _ = a.out.Write(line)
+	a.mu.Unlock()
+}
+
+// isValidRequestID only accepts the request id that is safe to be propagated and logged
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.' || c == ':') {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
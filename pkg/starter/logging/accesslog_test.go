@@ -0,0 +1,115 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	stdcontext "context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type userController struct {
+	at.RestController
+	at.RequestMapping `value:"/user"`
+}
+
+func newUserController() *userController {
+	return &userController{}
+}
+
+func (c *userController) Get(_ struct {
+	at.GetMapping `value:"/{id}"`
+}, id int, ctx context.Context) string {
+	return ctx.RequestID()
+}
+
+func (c *userController) GetContext(_ struct {
+	at.GetMapping `value:"/context"`
+}, ctx stdcontext.Context) string {
+	return log.RequestIDFromContext(ctx)
+}
+
+func TestAccessLog(t *testing.T) {
+	buf := new(bytes.Buffer)
+	accessLogOutput = buf
+
+	testApp := web.NewTestApp(newUserController).
+		SetProperty(app.ProfilesInclude, web.Profile, Profile).
+		SetProperty("logging.access_log.enabled", true).
+		SetProperty("logging.access_log.format", FormatJSON).
+		Run(t)
+
+	t.Run("should propagate request id", func(t *testing.T) {
+		buf.Reset()
+		testApp.Get("/user/123").
+			WithHeader("X-Request-Id", "abc-123").
+			Expect().Status(http.StatusOK).
+			Header("X-Request-Id").Equal("abc-123")
+
+		var entry accessLogEntry
+		assert.Equal(t, nil, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "abc-123", entry.RequestID)
+		assert.Equal(t, http.MethodGet, entry.Method)
+		assert.Equal(t, "/user/{id}", entry.Route)
+		assert.Equal(t, "/user/123", entry.Path)
+		assert.Equal(t, http.StatusOK, entry.Status)
+		assert.Equal(t, len("abc-123"), entry.Bytes)
+	})
+
+	t.Run("should assign request id", func(t *testing.T) {
+		buf.Reset()
+		resp := testApp.Get("/user/123").
+			WithHeader("X-Request-Id", "invalid id\"").
+			Expect().Status(http.StatusOK)
+		requestID := resp.Header("X-Request-Id").Raw()
+		assert.Equal(t, 32, len(requestID))
+		resp.Body().Equal(requestID)
+		assert.Contains(t, buf.String(), "\"request_id\":\""+requestID+"\"")
+	})
+
+	t.Run("should carry request id in the context of the request", func(t *testing.T) {
+		testApp.Get("/user/context").
+			WithHeader("X-Request-Id", "abc-456").
+			Expect().Status(http.StatusOK).
+			Body().Equal("abc-456")
+	})
+}
+
+func TestAccessLogOutput(t *testing.T) {
+	buf := new(bytes.Buffer)
+	log.SetOutput(buf)
+	defer log.SetOutput(os.Stdout)
+
+	a := newAccessLog(log.Writer(), FormatText, "X-Request-Id")
+	a.write(&accessLogEntry{Time: "now", RequestID: "id", Method: "GET", Route: "/", Status: 200})
+	assert.Contains(t, buf.String(), "now | id | GET / | 200")
+}
+
+func TestAccessLogTextFormat(t *testing.T) {
+	buf := new(bytes.Buffer)
+	a := newAccessLog(buf, FormatText, "X-Request-Id")
+	a.write(&accessLogEntry{Time: "now", RequestID: "id", Method: "GET", Route: "/user/{id}", Status: 200, Latency: 1.5, Bytes: 2, ClientIP: "127.0.0.1"})
+	assert.Equal(t, "now | id | GET /user/{id} | 200 | 1.500ms | 2B | 127.0.0.1\n", buf.String())
+}
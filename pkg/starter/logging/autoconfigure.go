@@ -18,6 +18,7 @@ package logging
 import (
 	"github.com/hidevopsio/hiboot/pkg/app"
 	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/log"
 	"github.com/hidevopsio/iris/middleware/logger"
 )
 
@@ -56,8 +57,15 @@ func init() {
 	app.Register(newConfiguration)
 }
 
-// LoggerHandler config logger handler
+// LoggerHandler config logger handler, it is the access log middleware if logging.access_log.enabled is true
 func (c *configuration) LoggerHandler() context.Handler {
+	if c.Properties.AccessLog.Enabled {
+		accessLog := newAccessLog(accessLogOutput, c.Properties.AccessLog.Format, c.Properties.AccessLog.RequestIDHeader)
+		lh := context.Handler(accessLog.Serve)
+		c.applicationContext.Use(lh)
+		return lh
+	}
+
 	loggerHandler := logger.New(logger.Config{
 		// Status displays status code
 		Status: c.Properties.Status,
@@ -86,3 +94,8 @@ func (c *configuration) LoggerHandler() context.Handler {
 
 	return lh
 }
+
+// Logger is the injectable structured logger, the named loggers can be derived by Named, e.g. logger.Named("user")
+func (c *configuration) Logger() log.Logger {
+	return log.GetLogger("")
+}
@@ -37,4 +37,10 @@ func TestConfiguration(t *testing.T) {
 		io.EnsureWorkDir(1, "config/application.yml")
 		c.LoggerHandler()
 	})
+
+	t.Run("should get structured logger", func(t *testing.T) {
+		logger := c.Logger()
+		assert.NotEqual(t, nil, logger)
+		assert.Equal(t, "user", logger.Named("user").Name())
+	})
 }
@@ -30,4 +30,16 @@ type properties struct {
 	Columns     bool     `json:"columns" default:"false"`
 	ContextKeys []string `json:"context_keys" default:"logger_message"`
 	HeaderKeys  []string `json:"header_keys" default:"User-Agent"`
+
+	AccessLog accessLogProperties `json:"access_log"`
+}
+
+// accessLogProperties is the access log properties
+type accessLogProperties struct {
+	// Enabled replaces the default logger handler with the access log middleware
+	Enabled bool `json:"enabled" default:"false"`
+	// Format is the output format of the access log, text or json
+	Format string `json:"format" default:"text"`
+	// RequestIDHeader is the header that the request id is read from and written to
+	RequestIDHeader string `json:"request_id_header" default:"X-Request-Id"`
 }
@@ -0,0 +1,64 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides the hiboot starter for injectable metrics registry,
+// it instruments http requests and scheduled jobs, and serves the metrics in prometheus text format
+package metrics
+
+import (
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/at"
+)
+
+const (
+	// Profile is the profile of metrics, it should be as same as the package name
+	Profile = "metrics"
+)
+
+type configuration struct {
+	at.AutoConfiguration
+
+	Properties *Properties
+}
+
+func init() {
+	app.Register(newConfiguration)
+}
+
+func newConfiguration() *configuration {
+	return &configuration{}
+}
+
+// Registry is the injectable metrics registry
+func (c *configuration) Registry() *Registry {
+	registry := NewRegistry(c.Properties.Namespace)
+	if c.Properties.Scheduler {
+		registry.Collect(collectSchedulerMetrics)
+	}
+	return registry
+}
+
+// Middleware records the count, latency and status code of http requests
+func (c *configuration) Middleware(applicationContext app.ApplicationContext, registry *Registry) (mw *Middleware) {
+	mw = newMiddleware(registry)
+	if c.Properties.Http {
+		applicationContext.Use(mw.Serve)
+	}
+	return
+}
+
+// Controller serves the metrics on actuator endpoint
+func (c *configuration) Controller(registry *Registry) *controller {
+	return newController(registry)
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/starter/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+type userController struct {
+	at.RestController
+	at.RequestMapping `value:"/user"`
+
+	requests *metrics.Counter
+}
+
+func newUserController(registry *metrics.Registry) *userController {
+	return &userController{
+		requests: registry.Counter("user_requests_total", "The total number of user requests."),
+	}
+}
+
+func (c *userController) Get(_ struct {
+	at.GetMapping `value:"/{id}"`
+}, id int) string {
+	c.requests.Inc()
+	return "user"
+}
+
+type jobService struct {
+	at.EnableScheduling
+	done chan bool
+}
+
+func newJobService() *jobService {
+	return &jobService{done: make(chan bool, 1)}
+}
+
+func (s *jobService) Sync(_ struct {
+	at.Scheduled `limit:"1" tag:"sync"`
+}) error {
+	s.done <- true
+	return errors.New("sync failed")
+}
+
+func TestMetrics(t *testing.T) {
+	app.Register(newJobService)
+	testApp := web.NewTestApp(newUserController).
+		SetProperty(app.ProfilesInclude, web.Profile, metrics.Profile).
+		Run(t)
+	svc := testApp.(app.ApplicationContext).GetInstance(jobService{}).(*jobService)
+	<-svc.done
+	time.Sleep(100 * time.Millisecond)
+
+	testApp.Get("/user/123").Expect().Status(http.StatusOK)
+	testApp.Get("/user/456").Expect().Status(http.StatusOK)
+
+	t.Run("should serve metrics in prometheus text format", func(t *testing.T) {
+		resp := testApp.Get("/metrics").Expect().Status(http.StatusOK)
+		resp.Header("Content-Type").Equal(metrics.ContentType)
+		body := resp.Body()
+		body.Contains("http_requests_total{method=\"GET\",route=\"/user/{id}\",status=\"200\"} 2\n")
+		body.Contains("http_request_duration_seconds_count{method=\"GET\",route=\"/user/{id}\"} 2\n")
+		body.Contains("user_requests_total 2\n")
+		body.Contains("scheduler_job_runs_total{job=\"sync\"} 1\n")
+		body.Contains("scheduler_job_failures_total{job=\"sync\"} 1\n")
+	})
+
+	t.Run("should inject registry", func(t *testing.T) {
+		registry := testApp.(app.ApplicationContext).GetInstance(metrics.Registry{})
+		assert.NotEqual(t, nil, registry)
+	})
+}
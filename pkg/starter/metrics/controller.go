@@ -0,0 +1,48 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+type controller struct {
+	at.RestController
+	at.ManagementEndpoint
+	at.RequestMapping `value:"${actuator.base_path:}/metrics" no_context_path:"true"`
+
+	registry *Registry
+}
+
+func newController(registry *Registry) *controller {
+	return &controller{registry: registry}
+}
+
+// GET /metrics
+func (c *controller) Get(_ struct {
+	at.GetMapping `value:"/"`
+	at.Operation  `id:"metrics" description:"metrics endpoint in prometheus text format"`
+	at.Produces   `values:"text/plain"`
+}, ctx context.Context) {
+	ctx.Header("Content-Type", ContentType)
+	ctx.StatusCode(http.StatusOK)
+	if err := c.registry.Write(ctx.ResponseWriter()); err != nil {
+		log.Error(err)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/system/scheduler"
+)
+
+// Middleware is the http request instrumentation
+type Middleware struct {
+	requests *Counter
+	duration *Histogram
+	inFlight *Gauge
+}
+
+func newMiddleware(registry *Registry) *Middleware {
+	return &Middleware{
+		requests: registry.Counter("http_requests_total", "The total number of http requests.", "method", "route", "status"),
+		duration: registry.Histogram("http_request_duration_seconds", "The latency of http requests in seconds.", nil, "method", "route"),
+		inFlight: registry.Gauge("http_requests_in_flight", "The number of http requests that are being served."),
+	}
+}
+
+// Serve records the request with the route template, e.g. /user/{id}, rather than the raw path,
+// it is registered as the route middleware so that the current route is always set
+func (m *Middleware) Serve(ctx context.Context) {
+	start := time.Now()
+	m.inFlight.Inc()
+	defer m.inFlight.Dec()
+
+	ctx.Next()
+
+	route := ctx.GetCurrentRoute().Path()
+	method := ctx.Method()
+	m.requests.Inc(method, route, strconv.Itoa(ctx.GetStatusCode()))
+	m.duration.Observe(time.Since(start).Seconds(), method, route)
+}
+
+// collectSchedulerMetrics updates the scheduled job metrics from the scheduler statistics
+func collectSchedulerMetrics(registry *Registry) {
+	runs := registry.Counter("scheduler_job_runs_total", "The total number of scheduled job runs.", "job")
+	failures := registry.Counter("scheduler_job_failures_total", "The total number of failed scheduled job runs.", "job")
+	for job, stats := range scheduler.Stats() {
+		runs.m.set(float64(stats.Runs), []string{job})
+		failures.m.set(float64(stats.Failures), []string{job})
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+
+	// ContentType is the content type of prometheus text format
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// DefaultBuckets are the default upper bounds of histogram buckets, they are tailored to measure the latency in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type sample struct {
+	labelValues []string
+	value       float64
+	// histogram only
+	buckets []uint64
+	count   uint64
+}
+
+type metric struct {
+	sync.Mutex
+	name        string
+	help        string
+	typ         string
+	labelNames  []string
+	upperBounds []float64
+	samples     map[string]*sample
+}
+
+func (m *metric) sample(labelValues []string) *sample {
+	if len(labelValues) != len(m.labelNames) {
+		log.Errorf("[metrics] %v expects %d label values, got %d", m.name, len(m.labelNames), len(labelValues))
+		labelValues = append(labelValues, make([]string, len(m.labelNames))...)[:len(m.labelNames)]
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := m.samples[key]
+	if !ok {
+		s = &sample{labelValues: append([]string{}, labelValues...)}
+		if m.typ == typeHistogram {
+			s.buckets = make([]uint64, len(m.upperBounds))
+		}
+		m.samples[key] = s
+	}
+	return s
+}
+
+func (m *metric) add(v float64, labelValues []string) {
+	m.Lock()
+	m.sample(labelValues).value += v
+	m.Unlock()
+}
+
+func (m *metric) set(v float64, labelValues []string) {
+	m.Lock()
+	m.sample(labelValues).value = v
+	m.Unlock()
+}
+
+func (m *metric) observe(v float64, labelValues []string) {
+	m.Lock()
+	s := m.sample(labelValues)
+	for i, upperBound := range m.upperBounds {
+		if v <= upperBound {
+			s.buckets[i]++
+		}
+	}
+	s.count++
+	s.value += v
+	m.Unlock()
+}
+
+func (m *metric) write(w io.Writer) (err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if len(m.samples) == 0 {
+		return
+	}
+	if _, err = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, escape(m.help, false), m.name, m.typ); err != nil {
+		return
+	}
+	keys := make([]string, 0, len(m.samples))
+	for k := range m.samples {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := m.samples[k]
+		labels := m.labels(s.labelValues)
+		if m.typ != typeHistogram {
+			if _, err = fmt.Fprintf(w, "%s%s %s\n", m.name, labels(""), formatFloat(s.value)); err != nil {
+				return
+			}
+			continue
+		}
+		for i, upperBound := range m.upperBounds {
+			if _, err = fmt.Fprintf(w, "%s_bucket%s %d\n", m.name, labels(formatFloat(upperBound)), s.buckets[i]); err != nil {
+				return
+			}
+		}
+		if _, err = fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			m.name, labels("+Inf"), s.count,
+			m.name, labels(""), formatFloat(s.value),
+			m.name, labels(""), s.count); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// labels returns the function that formats the labels, le is the extra label of the histogram bucket
+func (m *metric) labels(labelValues []string) func(le string) string {
+	return func(le string) string {
+		var pairs []string
+		for i, name := range m.labelNames {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", name, escape(labelValues[i], true)))
+		}
+		if le != "" {
+			pairs = append(pairs, fmt.Sprintf("le=\"%s\"", le))
+		}
+		if len(pairs) == 0 {
+			return ""
+		}
+		return "{" + strings.Join(pairs, ",") + "}"
+	}
+}
+
+func escape(s string, quoted bool) string {
+	s = strings.Replace(s, "\\", "\\\\", -1)
+	s = strings.Replace(s, "\n", "\\n", -1)
+	if quoted {
+		s = strings.Replace(s, "\"", "\\\"", -1)
+	}
+	return s
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Counter is the metric that only goes up, e.g. the number of requests
+type Counter struct {
+	m *metric
+}
+
+// Inc increases the counter of the label values by 1
+func (c *Counter) Inc(labelValues ...string) {
+	c.m.add(1, labelValues)
+}
+
+// Add adds v to the counter of the label values, v must not be negative
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		log.Errorf("[metrics] counter %v can not decrease", c.m.name)
+		return
+	}
+	c.m.add(v, labelValues)
+}
+
+// Gauge is the metric that can go up and down, e.g. the number of in-flight requests
+type Gauge struct {
+	m *metric
+}
+
+// Set sets the gauge of the label values to v
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.m.set(v, labelValues)
+}
+
+// Add adds v to the gauge of the label values, v can be negative
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.m.add(v, labelValues)
+}
+
+// Inc increases the gauge of the label values by 1
+func (g *Gauge) Inc(labelValues ...string) {
+	g.m.add(1, labelValues)
+}
+
+// Dec decreases the gauge of the label values by 1
+func (g *Gauge) Dec(labelValues ...string) {
+	g.m.add(-1, labelValues)
+}
+
+// Histogram samples observations into buckets, e.g. the request latency
+type Histogram struct {
+	m *metric
+}
+
+// Observe adds a single observation of the label values to the histogram
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.m.observe(v, labelValues)
+}
+
+// Collector is called before the metrics are written, it is used to update the metrics from other sources
+type Collector func(registry *Registry)
+
+// Registry holds the counters, gauges and histograms, it writes them in prometheus text format
+type Registry struct {
+	mu         sync.RWMutex
+	namespace  string
+	metrics    map[string]*metric
+	collectors []Collector
+}
+
+// NewRegistry creates a new registry, namespace is the prefix of the metric names
+func NewRegistry(namespace string) *Registry {
+	return &Registry{
+		namespace: namespace,
+		metrics:   make(map[string]*metric),
+	}
+}
+
+// register returns the existing metric if the name is registered with the same type
+func (r *Registry) register(name, help, typ string, upperBounds []float64, labelNames []string) *metric {
+	if r.namespace != "" {
+		name = r.namespace + "_" + name
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if m, ok := r.metrics[name]; ok {
+		if m.typ != typ {
+			log.Errorf("[metrics] %v is already registered as %v", name, m.typ)
+		}
+		return m
+	}
+	m := &metric{
+		name:        name,
+		help:        help,
+		typ:         typ,
+		labelNames:  labelNames,
+		upperBounds: upperBounds,
+		samples:     make(map[string]*sample),
+	}
+	r.metrics[name] = m
+	return m
+}
+
+// Counter registers the counter, it returns the existing one if it is already registered
+func (r *Registry) Counter(name, help string, labelNames ...string) *Counter {
+	return &Counter{m: r.register(name, help, typeCounter, nil, labelNames)}
+}
+
+// Gauge registers the gauge, it returns the existing one if it is already registered
+func (r *Registry) Gauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{m: r.register(name, help, typeGauge, nil, labelNames)}
+}
+
+// Histogram registers the histogram, DefaultBuckets is used if buckets is empty
+func (r *Registry) Histogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64{}, buckets...)
+	sort.Float64s(buckets)
+	return &Histogram{m: r.register(name, help, typeHistogram, buckets, labelNames)}
+}
+
+// Collect adds the collector that is called before the metrics are written
+func (r *Registry) Collect(collector Collector) {
+	r.mu.Lock()
+	r.collectors = append(r.collectors, collector)
+	r.mu.Unlock()
+}
+
+// Write writes all metrics in prometheus text format
+func (r *Registry) Write(w io.Writer) (err error) {
+	r.mu.RLock()
+	collectors := append([]Collector{}, r.collectors...)
+	r.mu.RUnlock()
+	for _, collect := range collectors {
+		collect(r)
+	}
+
+	r.mu.RLock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	metrics := make([]*metric, 0, len(names))
+	for _, name := range names {
+		metrics = append(metrics, r.metrics[name])
+	}
+	r.mu.RUnlock()
+
+	for _, m := range metrics {
+		if err = m.write(w); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type brokenWriter struct {
+	writes int
+}
+
+func (w *brokenWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, errors.New("broken pipe")
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry("test")
+
+	t.Run("should write counter", func(t *testing.T) {
+		c := registry.Counter("requests_total", "The total number of requests.", "code")
+		c.Inc("200")
+		c.Add(2, "200")
+		c.Add(-1, "200")
+		c.Inc("500")
+		assert.Equal(t, c.m, registry.Counter("requests_total", "", "code").m)
+
+		buf := new(bytes.Buffer)
+		assert.Equal(t, nil, registry.Write(buf))
+		assert.Contains(t, buf.String(), "# TYPE test_requests_total counter\n")
+		assert.Contains(t, buf.String(), "test_requests_total{code=\"200\"} 3\n")
+		assert.Contains(t, buf.String(), "test_requests_total{code=\"500\"} 1\n")
+	})
+
+	t.Run("should write gauge", func(t *testing.T) {
+		g := registry.Gauge("temperature", "The temperature.")
+		g.Set(10)
+		g.Inc()
+		g.Dec()
+		g.Add(-2.5)
+
+		buf := new(bytes.Buffer)
+		_ = registry.Write(buf)
+		assert.Contains(t, buf.String(), "test_temperature 7.5\n")
+	})
+
+	t.Run("should write histogram", func(t *testing.T) {
+		h := registry.Histogram("latency_seconds", "The latency.", []float64{1, 0.1}, "route")
+		h.Observe(0.05, "/foo/{id}")
+		h.Observe(0.5, "/foo/{id}")
+		h.Observe(5, "/foo/{id}")
+
+		buf := new(bytes.Buffer)
+		_ = registry.Write(buf)
+		out := buf.String()
+		assert.Contains(t, out, "test_latency_seconds_bucket{route=\"/foo/{id}\",le=\"0.1\"} 1\n")
+		assert.Contains(t, out, "test_latency_seconds_bucket{route=\"/foo/{id}\",le=\"1\"} 2\n")
+		assert.Contains(t, out, "test_latency_seconds_bucket{route=\"/foo/{id}\",le=\"+Inf\"} 3\n")
+		assert.Contains(t, out, "test_latency_seconds_sum{route=\"/foo/{id}\"} 5.55\n")
+		assert.Contains(t, out, "test_latency_seconds_count{route=\"/foo/{id}\"} 3\n")
+	})
+
+	t.Run("should escape label values", func(t *testing.T) {
+		c := registry.Counter("escaped_total", "The escaped.", "value")
+		c.Inc("a\"b\\c\nd")
+		buf := new(bytes.Buffer)
+		_ = registry.Write(buf)
+		assert.Contains(t, buf.String(), `test_escaped_total{value="a\"b\\c\nd"} 1`)
+	})
+
+	t.Run("should stop writing on the first error", func(t *testing.T) {
+		w := new(brokenWriter)
+		assert.Equal(t, "broken pipe", registry.Write(w).Error())
+		assert.Equal(t, 1, w.writes)
+	})
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit provides the hiboot starter for rate limiting,
+// the requests are limited by the token bucket or sliding window algorithm per client,
+// globally by the properties ratelimit.* or per controller or method by the annotation at.RateLimit
+package ratelimit
+
+import (
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/at"
+)
+
+const (
+	// Profile is the profile of ratelimit, it should be as same as the package name
+	Profile = "ratelimit"
+)
+
+type configuration struct {
+	at.AutoConfiguration
+
+	Properties *Properties
+}
+
+func init() {
+	app.Register(newConfiguration)
+}
+
+func newConfiguration() *configuration {
+	return &configuration{}
+}
+
+// Store is the in-memory store of the limiter state, it backs off if the user supplies the Store, e.g. a redis based store
+func (c *configuration) Store(_ struct{ at.ConditionalOnMissingBean }) Store {
+	return NewMemoryStore()
+}
+
+// Middleware limits the requests of the routes that are annotated with at.RateLimit, or all requests if ratelimit.global is true
+func (c *configuration) Middleware(applicationContext app.ApplicationContext) (mw *Middleware) {
+	mw = newMiddleware(c.Properties, applicationContext)
+	if c.Properties.Global {
+		applicationContext.Use(mw.Serve)
+	}
+	return
+}
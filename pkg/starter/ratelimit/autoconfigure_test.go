@@ -0,0 +1,110 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/starter/ratelimit"
+)
+
+type orderController struct {
+	at.RestController
+	at.RequestMapping `value:"/order"`
+}
+
+func newOrderController() *orderController {
+	return &orderController{}
+}
+
+func (c *orderController) Get(_ struct {
+	at.GetMapping `value:"/"`
+	at.RateLimit  `limit:"2" period:"60"`
+}) string {
+	return "order"
+}
+
+func (c *orderController) Delete(_ struct {
+	at.DeleteMapping `value:"/"`
+}) string {
+	return "deleted"
+}
+
+type quotaController struct {
+	at.RestController
+	at.RequestMapping `value:"/quota"`
+	at.RateLimit      `limit:"1" algorithm:"sliding_window" key:"tenant"`
+}
+
+func newQuotaController() *quotaController {
+	return &quotaController{}
+}
+
+func (c *quotaController) Get(_ struct {
+	at.GetMapping `value:"/"`
+}) string {
+	return "quota"
+}
+
+func TestRateLimit(t *testing.T) {
+	ratelimit.RegisterKeyFunc("tenant", func(ctx context.Context) string {
+		return ctx.GetHeader("X-Tenant")
+	})
+	testApp := web.NewTestApp(newOrderController, newQuotaController).
+		SetProperty(app.ProfilesInclude, web.Profile, ratelimit.Profile).
+		Run(t)
+
+	t.Run("should limit the annotated method", func(t *testing.T) {
+		resp := testApp.Get("/order").Expect().Status(http.StatusOK)
+		resp.Header(ratelimit.HeaderLimit).Equal("2")
+		resp.Header(ratelimit.HeaderRemaining).Equal("1")
+		testApp.Get("/order").Expect().Status(http.StatusOK).
+			Header(ratelimit.HeaderRemaining).Equal("0")
+		resp = testApp.Get("/order").Expect().Status(http.StatusTooManyRequests)
+		resp.Header(ratelimit.HeaderRetryAfter).Equal("30")
+		resp.Header(ratelimit.HeaderReset).Equal("60")
+	})
+
+	t.Run("should not limit the method that is not annotated", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			testApp.Delete("/order").Expect().Status(http.StatusOK).
+				Header(ratelimit.HeaderLimit).Empty()
+		}
+	})
+
+	t.Run("should limit the annotated controller by the custom key", func(t *testing.T) {
+		testApp.Get("/quota").WithHeader("X-Tenant", "a").Expect().Status(http.StatusOK)
+		testApp.Get("/quota").WithHeader("X-Tenant", "a").Expect().Status(http.StatusTooManyRequests).
+			Header(ratelimit.HeaderRetryAfter).NotEmpty()
+		testApp.Get("/quota").WithHeader("X-Tenant", "b").Expect().Status(http.StatusOK)
+	})
+}
+
+func TestGlobalRateLimit(t *testing.T) {
+	testApp := web.NewTestApp(newOrderController).
+		SetProperty(app.ProfilesInclude, web.Profile, ratelimit.Profile).
+		SetProperty("ratelimit.global", true).
+		SetProperty("ratelimit.limit", 1).
+		Run(t)
+
+	testApp.Delete("/order").Expect().Status(http.StatusOK).
+		Header(ratelimit.HeaderLimit).Equal("1")
+	testApp.Delete("/order").Expect().Status(http.StatusTooManyRequests)
+}
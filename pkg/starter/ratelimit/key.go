@@ -0,0 +1,73 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"sync"
+
+	jwtgo "github.com/golang-jwt/jwt/v4"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/starter/jwt"
+)
+
+const (
+	// KeyIP identifies the client by the remote address
+	KeyIP = "ip"
+	// KeyJwt identifies the client by the subject of the jwt token, the remote address is used if there is no verified token
+	KeyJwt = "jwt"
+)
+
+// KeyFunc returns the key that identifies the client of current request
+type KeyFunc func(ctx context.Context) string
+
+var (
+	keyFuncs = map[string]KeyFunc{
+		KeyIP:  ipKey,
+		KeyJwt: jwtKey,
+	}
+	keyMu sync.RWMutex
+)
+
+// RegisterKeyFunc registers the custom key function, e.g. the one that identifies the client by the api key header,
+// it can be referenced by name in the properties ratelimit.key or at.RateLimit `key:"name"`
+func RegisterKeyFunc(name string, fn KeyFunc) {
+	keyMu.Lock()
+	defer keyMu.Unlock()
+	keyFuncs[name] = fn
+}
+
+func getKeyFunc(name string) (fn KeyFunc, ok bool) {
+	keyMu.RLock()
+	defer keyMu.RUnlock()
+	fn, ok = keyFuncs[name]
+	return
+}
+
+func ipKey(ctx context.Context) string {
+	return ctx.RemoteAddr()
+}
+
+// jwtKey reads the subject of the token that is verified by the jwt middleware,
+// the unverified token is ignored, otherwise the client could bypass the limit with forged subjects
+func jwtKey(ctx context.Context) string {
+	if token, ok := ctx.Values().Get(jwt.DefaultContextKey).(*jwtgo.Token); ok {
+		if claims, ok := token.Claims.(jwtgo.MapClaims); ok {
+			if sub, ok := claims["sub"].(string); ok && sub != "" {
+				return sub
+			}
+		}
+	}
+	return ipKey(ctx)
+}
@@ -0,0 +1,167 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	// TokenBucket is the token bucket algorithm, it allows bursts up to the bucket capacity
+	TokenBucket = "token_bucket"
+	// SlidingWindow is the sliding window counter algorithm, it smooths the requests at window boundaries
+	SlidingWindow = "sliding_window"
+)
+
+var (
+	// ErrUnknownAlgorithm the limiter algorithm is not supported
+	ErrUnknownAlgorithm = errors.New("[ratelimit] unknown algorithm, it should be token_bucket or sliding_window")
+
+	// ErrInvalidLimit the limit or period is not positive
+	ErrInvalidLimit = errors.New("[ratelimit] limit and period must be positive")
+)
+
+// Result is the result of a rate limit check
+type Result struct {
+	// Allowed is true if the request is allowed
+	Allowed bool
+	// Limit is the max number of requests in the period
+	Limit int
+	// Remaining is the number of requests that are left in the period
+	Remaining int
+	// Reset is the duration until the quota is fully restored
+	Reset time.Duration
+	// RetryAfter is the duration that the client should wait before retrying if the request is not allowed
+	RetryAfter time.Duration
+}
+
+// Limiter checks if the request of the client identified by key is allowed
+type Limiter interface {
+	Allow(key string) (result *Result, err error)
+}
+
+// NewLimiter returns the Limiter of the algorithm that allows limit requests per period,
+// burst is the capacity of the token bucket, it is the same as limit if it is not positive
+func NewLimiter(store Store, algorithm string, limit int, period time.Duration, burst int) (limiter Limiter, err error) {
+	if limit <= 0 || period <= 0 {
+		err = ErrInvalidLimit
+		return
+	}
+	switch algorithm {
+	case TokenBucket:
+		if burst <= 0 {
+			burst = limit
+		}
+		limiter = &tokenBucket{
+			store:    store,
+			capacity: float64(burst),
+			rate:     float64(limit) / period.Seconds(),
+			now:      time.Now,
+		}
+	case SlidingWindow:
+		limiter = &slidingWindow{
+			store:  store,
+			limit:  limit,
+			period: period,
+			now:    time.Now,
+		}
+	default:
+		err = ErrUnknownAlgorithm
+	}
+	return
+}
+
+type tokenBucket struct {
+	store    Store
+	capacity float64
+	// rate is the number of tokens that are refilled per second
+	rate float64
+	now  func() time.Time
+}
+
+func (l *tokenBucket) seconds(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+// Allow takes a token from the bucket of key
+func (l *tokenBucket) Allow(key string) (result *Result, err error) {
+	now := l.now()
+	result = &Result{Limit: int(l.capacity)}
+	err = l.store.Update(key, l.seconds(l.capacity), func(s *State) {
+		if s.Timestamp.IsZero() {
+			s.Tokens = l.capacity
+		} else if elapsed := now.Sub(s.Timestamp).Seconds(); elapsed > 0 {
+			s.Tokens = math.Min(l.capacity, s.Tokens+elapsed*l.rate)
+		}
+		s.Timestamp = now
+
+		if s.Tokens >= 1 {
+			s.Tokens--
+			result.Allowed = true
+		} else {
+			result.RetryAfter = l.seconds(1 - s.Tokens)
+		}
+		result.Remaining = int(s.Tokens)
+		result.Reset = l.seconds(l.capacity - s.Tokens)
+	})
+	return
+}
+
+type slidingWindow struct {
+	store  Store
+	limit  int
+	period time.Duration
+	now    func() time.Time
+}
+
+// Allow counts the request in the current window of key,
+// the requests of the previous window are weighted by the overlap of the sliding window
+func (l *slidingWindow) Allow(key string) (result *Result, err error) {
+	now := l.now()
+	start := now.Truncate(l.period)
+	result = &Result{Limit: l.limit}
+	err = l.store.Update(key, 2*l.period, func(s *State) {
+		if !s.Timestamp.Equal(start) {
+			if start.Sub(s.Timestamp) == l.period {
+				s.PrevCount = s.Count
+			} else {
+				s.PrevCount = 0
+			}
+			s.Count = 0
+			s.Timestamp = start
+		}
+
+		elapsed := now.Sub(start)
+		weight := 1 - float64(elapsed)/float64(l.period)
+		estimated := float64(s.PrevCount)*weight + float64(s.Count)
+		if estimated+1 <= float64(l.limit) {
+			s.Count++
+			estimated++
+			result.Allowed = true
+		} else if s.Count+1 > l.limit || s.PrevCount == 0 {
+			// wait for the next window
+			result.RetryAfter = l.period - elapsed
+		} else {
+			// wait until the weight of the previous window is low enough
+			w := float64(l.limit-s.Count-1) / float64(s.PrevCount)
+			result.RetryAfter = time.Duration((1-w)*float64(l.period)) - elapsed
+		}
+		result.Remaining = int(math.Max(0, float64(l.limit)-math.Ceil(estimated)))
+		result.Reset = l.period - elapsed
+	})
+	return
+}
@@ -0,0 +1,109 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func TestTokenBucket(t *testing.T) {
+	c := &clock{t: time.Unix(1000, 0)}
+	l, err := NewLimiter(NewMemoryStore(), TokenBucket, 1, time.Second, 2)
+	assert.Equal(t, nil, err)
+	l.(*tokenBucket).now = c.now
+
+	r, _ := l.Allow("a")
+	assert.Equal(t, true, r.Allowed)
+	assert.Equal(t, 2, r.Limit)
+	assert.Equal(t, 1, r.Remaining)
+	r, _ = l.Allow("a")
+	assert.Equal(t, true, r.Allowed)
+	r, _ = l.Allow("a")
+	assert.Equal(t, false, r.Allowed)
+	assert.Equal(t, time.Second, r.RetryAfter)
+	assert.Equal(t, 2*time.Second, r.Reset)
+
+	r, _ = l.Allow("b")
+	assert.Equal(t, true, r.Allowed)
+
+	c.t = c.t.Add(time.Second)
+	r, _ = l.Allow("a")
+	assert.Equal(t, true, r.Allowed)
+	assert.Equal(t, 0, r.Remaining)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	c := &clock{t: time.Unix(1000, 0)}
+	l, err := NewLimiter(NewMemoryStore(), SlidingWindow, 2, 10*time.Second, 0)
+	assert.Equal(t, nil, err)
+	l.(*slidingWindow).now = c.now
+
+	r, _ := l.Allow("a")
+	assert.Equal(t, true, r.Allowed)
+	assert.Equal(t, 1, r.Remaining)
+	r, _ = l.Allow("a")
+	assert.Equal(t, true, r.Allowed)
+	r, _ = l.Allow("a")
+	assert.Equal(t, false, r.Allowed)
+	assert.Equal(t, 10*time.Second, r.RetryAfter)
+
+	// half of the previous window is counted
+	c.t = c.t.Add(15 * time.Second)
+	r, _ = l.Allow("a")
+	assert.Equal(t, true, r.Allowed)
+	assert.Equal(t, 0, r.Remaining)
+	r, _ = l.Allow("a")
+	assert.Equal(t, false, r.Allowed)
+	assert.Equal(t, 5*time.Second, r.RetryAfter)
+
+	// the previous window is out of the sliding window
+	c.t = c.t.Add(20 * time.Second)
+	r, _ = l.Allow("a")
+	assert.Equal(t, true, r.Allowed)
+}
+
+func TestNewLimiter(t *testing.T) {
+	_, err := NewLimiter(NewMemoryStore(), "leaky_bucket", 1, time.Second, 0)
+	assert.Equal(t, ErrUnknownAlgorithm, err)
+
+	_, err = NewLimiter(NewMemoryStore(), TokenBucket, 0, time.Second, 0)
+	assert.Equal(t, ErrInvalidLimit, err)
+}
+
+func TestMemoryStore(t *testing.T) {
+	c := &clock{t: time.Unix(1000, 0)}
+	s := NewMemoryStore().(*memoryStore)
+	s.now = c.now
+
+	_ = s.Update("a", time.Second, func(state *State) { state.Count++ })
+	_ = s.Update("a", time.Second, func(state *State) { state.Count++ })
+	assert.Equal(t, 2, s.entries["a"].state.Count)
+
+	c.t = c.t.Add(2 * time.Second)
+	_ = s.Update("b", time.Second, func(state *State) { state.Count++ })
+	_, ok := s.entries["a"]
+	assert.Equal(t, false, ok)
+}
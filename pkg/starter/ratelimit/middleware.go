@@ -0,0 +1,175 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+const (
+	// HeaderLimit is the response header of the max number of requests in the period
+	HeaderLimit = "RateLimit-Limit"
+	// HeaderRemaining is the response header of the number of requests that are left in the period
+	HeaderRemaining = "RateLimit-Remaining"
+	// HeaderReset is the response header of the seconds until the quota is fully restored
+	HeaderReset = "RateLimit-Reset"
+	// HeaderRetryAfter is the response header of the seconds that the client should wait before retrying
+	HeaderRetryAfter = "Retry-After"
+
+	globalRoute = "*"
+)
+
+type policy struct {
+	algorithm string
+	limit     int
+	period    int64
+	burst     int
+	key       string
+}
+
+// Middleware limits the request rate of the controllers or methods that are annotated with at.RateLimit,
+// each route is limited separately for each client
+type Middleware struct {
+	at.Middleware
+	at.RateLimit
+
+	properties         *Properties
+	applicationContext app.ApplicationContext
+	defaultPolicy      policy
+
+	once     sync.Once
+	store    Store
+	limiters sync.Map
+}
+
+func newMiddleware(properties *Properties, applicationContext app.ApplicationContext) *Middleware {
+	return &Middleware{
+		properties:         properties,
+		applicationContext: applicationContext,
+		defaultPolicy: policy{
+			algorithm: properties.Algorithm,
+			limit:     properties.Limit,
+			period:    properties.Period,
+			burst:     properties.Burst,
+			key:       properties.Key,
+		},
+	}
+}
+
+// Limit limits the requests of the route that is annotated with at.RateLimit,
+// the values that are not specified by the annotation fall back to the properties
+func (m *Middleware) Limit(_ struct {
+	at.MiddlewareHandler
+}, ctx context.Context) {
+	p := m.defaultPolicy
+	if ann := web.RouteAnnotation(ctx, at.RateLimit{}); ann != nil {
+		rl := ann.Field.Value.Interface().(at.RateLimit)
+		if rl.AtAlgorithm != "" {
+			p.algorithm = rl.AtAlgorithm
+		}
+		if rl.AtLimit != 0 {
+			p.limit = rl.AtLimit
+			// the default burst is for the default limit
+			p.burst = 0
+		}
+		if rl.AtPeriod != 0 {
+			p.period = rl.AtPeriod
+		}
+		if rl.AtBurst != 0 {
+			p.burst = rl.AtBurst
+		}
+		if rl.AtKey != "" {
+			p.key = rl.AtKey
+		}
+	}
+	m.serve(ctx, ctx.GetCurrentRoute().Name(), p)
+}
+
+// Serve limits all requests with the default limit, it is used if the property ratelimit.global is true
+func (m *Middleware) Serve(ctx context.Context) {
+	m.serve(ctx, globalRoute, m.defaultPolicy)
+}
+
+func (m *Middleware) getStore() Store {
+	m.once.Do(func() {
+		if store, ok := m.applicationContext.GetInstance(new(Store)).(Store); ok {
+			m.store = store
+		} else {
+			m.store = NewMemoryStore()
+		}
+	})
+	return m.store
+}
+
+func (m *Middleware) getLimiter(p policy) (limiter Limiter, err error) {
+	if l, ok := m.limiters.Load(p); ok {
+		limiter = l.(Limiter)
+		return
+	}
+	limiter, err = NewLimiter(m.getStore(), p.algorithm, p.limit, time.Duration(p.period)*time.Second, p.burst)
+	if err == nil {
+		l, _ := m.limiters.LoadOrStore(p, limiter)
+		limiter = l.(Limiter)
+	}
+	return
+}
+
+func (m *Middleware) serve(ctx context.Context, route string, p policy) {
+	keyFunc, ok := getKeyFunc(p.key)
+	if !ok {
+		log.Warnf("[ratelimit] unknown key function %v, the remote address is used instead", p.key)
+		keyFunc = ipKey
+	}
+
+	limiter, err := m.getLimiter(p)
+	var result *Result
+	if err == nil {
+		result, err = limiter.Allow(fmt.Sprintf("%v:%v:%v", route, p.key, keyFunc(ctx)))
+	}
+	if err != nil {
+		// do not reject the requests if the limiter does not work
+		log.Errorf("[ratelimit] %v", err)
+		ctx.Next()
+		return
+	}
+
+	if m.properties.Headers {
+		ctx.Header(HeaderLimit, strconv.Itoa(result.Limit))
+		ctx.Header(HeaderRemaining, strconv.Itoa(result.Remaining))
+		ctx.Header(HeaderReset, seconds(result.Reset))
+	}
+	if !result.Allowed {
+		ctx.Header(HeaderRetryAfter, seconds(result.RetryAfter))
+		ctx.ResponseError(http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		return
+	}
+	ctx.Next()
+}
+
+// seconds rounds the duration up to seconds
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -0,0 +1,41 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"github.com/hidevopsio/hiboot/pkg/at"
+)
+
+// Properties the rate limit properties
+type Properties struct {
+	at.ConfigurationProperties `value:"ratelimit"`
+	at.AutoWired
+
+	// Global limits all requests with the default limit,
+	// otherwise only the controllers or methods that are annotated with at.RateLimit are limited
+	Global bool `json:"global"`
+	// Algorithm is the default limiter algorithm, token_bucket or sliding_window
+	Algorithm string `json:"algorithm" default:"token_bucket"`
+	// Limit is the default max number of requests in the period
+	Limit int `json:"limit" default:"100"`
+	// Period is the default period in seconds
+	Period int64 `json:"period" default:"60"`
+	// Burst is the default capacity of the token bucket, it is the same as limit if it is not set
+	Burst int `json:"burst"`
+	// Key is the default key function that identifies the client, ip, jwt or the registered custom key function
+	Key string `json:"key" default:"ip"`
+	// Headers enables the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset response headers
+	Headers bool `json:"headers" default:"true"`
+}
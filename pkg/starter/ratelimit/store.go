@@ -0,0 +1,91 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// State is the limiter state of a client
+type State struct {
+	// Tokens is the number of tokens left in the token bucket
+	Tokens float64
+	// Count is the number of requests in the current window of the sliding window
+	Count int
+	// PrevCount is the number of requests in the previous window of the sliding window
+	PrevCount int
+	// Timestamp is the last refill time of the token bucket, or the start time of the current window
+	Timestamp time.Time
+}
+
+// Store is the state store of the limiters, the in-memory store is used by default,
+// a shared store, e.g. redis, can be provided by registering a constructor that returns ratelimit.Store
+type Store interface {
+	// Update applies fn to the state of key atomically, the state is zero value if key does not exist,
+	// the state may be evicted once it has not been updated for ttl
+	Update(key string, ttl time.Duration, fn func(state *State)) error
+}
+
+type entry struct {
+	state   State
+	expires time.Time
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*entry
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore returns the in-memory Store
+func NewMemoryStore() Store {
+	return &memoryStore{
+		entries: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+// Update applies fn to the state of key
+func (s *memoryStore) Update(key string, ttl time.Duration, fn func(state *State)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	e, ok := s.entries[key]
+	if !ok || now.After(e.expires) {
+		e = new(entry)
+		s.entries[key] = e
+	}
+	fn(&e.state)
+	e.expires = now.Add(ttl)
+	return nil
+}
+
+// sweep evicts the expired states at most once per second
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	for key, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+	s.nextSweep = now.Add(time.Second)
+}
@@ -4,6 +4,7 @@ package swagger
 import (
 	"github.com/hidevopsio/hiboot/pkg/app"
 	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
 )
 
 const (
@@ -31,8 +32,8 @@ func (c *configuration) HttpMethodSubscriber(pathsBuilder *apiPathsBuilder) *htt
 	return newHttpMethodSubscriber(pathsBuilder)
 }
 
-func (c *configuration) ApiPathsBuilder(infoBuilder *apiInfoBuilder) *apiPathsBuilder {
-	return newApiPathsBuilder(infoBuilder)
+func (c *configuration) ApiPathsBuilder(infoBuilder *apiInfoBuilder, configurableFactory factory.ConfigurableFactory) *apiPathsBuilder {
+	return newApiPathsBuilder(infoBuilder, configurableFactory)
 }
 
 
@@ -26,6 +26,7 @@ import (
 
 type controller struct {
 	at.RestController
+	at.ManagementEndpoint
 	at.RequestMapping `value:"/"`
 
 	apiInfoBuilder *apiInfoBuilder
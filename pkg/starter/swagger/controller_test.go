@@ -1,6 +1,7 @@
 package swagger_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/hidevopsio/hiboot/pkg/app"
 	"github.com/hidevopsio/hiboot/pkg/app/web"
@@ -10,6 +11,9 @@ import (
 	"github.com/hidevopsio/hiboot/pkg/log"
 	"github.com/hidevopsio/hiboot/pkg/model"
 	"github.com/hidevopsio/hiboot/pkg/starter/swagger"
+	"github.com/hidevopsio/httpexpect"
+	"github.com/stretchr/testify/assert"
+	"mime/multipart"
 	"net/http"
 	"testing"
 	"time"
@@ -312,6 +316,25 @@ func (c *employeeController) DeleteEmployee(at struct {
 	return
 }
 
+// GetEmployeeAssets
+func (c *employeeController) GetEmployeeAssets(at struct {
+	at.GetMapping `value:"/{id:int}/assets"`
+	at.Operation  `id:"Get Employee's Assets" description:"This is the api that gets the assets of the employee"`
+	at.Produces   `values:"application/json"`
+	Parameters    struct {
+		at.Parameter `type:"integer" name:"id" in:"path" description:"Path variable employee ID" required:"true"`
+	}
+	Responses struct {
+		StatusOK struct {
+			at.Response `code:"200" description:"returns the assets of the employee"`
+			ErrorResponse
+		}
+	}
+}, id int) (response model.ResponseInfo, err error) {
+	response = new(model.BaseResponseInfo)
+	return
+}
+
 // AddEmployeeAsserts
 func (c *employeeController) AddEmployeeAsserts(at struct {
 	at.PostMapping `value:"/add-assets"`
@@ -334,6 +357,34 @@ func (c *employeeController) AddEmployeeAsserts(at struct {
 	return
 }
 
+type AvatarRequest struct {
+	at.RequestForm
+
+	Name   string                  `form:"name" validate:"required" description:"employee name"`
+	Age    int                     `form:"age"`
+	Tags   []string                `form:"tags"`
+	Avatar *web.UploadedFile       `form:"avatar" description:"avatar image"`
+	Photos []*multipart.FileHeader `form:"photos"`
+}
+
+// UploadAvatar
+func (c *employeeController) UploadAvatar(at struct {
+	at.PostMapping `value:"/avatar"`
+	at.Operation   `id:"Upload Avatar" description:"This is the api that uploads the avatar of employee"`
+	Parameters     struct {
+		at.Parameter `name:"avatar" in:"formData" description:"Avatar form"`
+		AvatarRequest
+	}
+	Responses struct {
+		StatusOK struct {
+			at.Response `code:"200" description:"returns success message"`
+		}
+	}
+}, request *AvatarRequest) (response model.ResponseInfo, err error) {
+	response = new(model.BaseResponseInfo)
+	return
+}
+
 // After
 func (c *employeeController) AfterMethod(at struct{ at.AfterMethod }, ctx context.Context) {
 	log.Debug("before method")
@@ -341,9 +392,27 @@ func (c *employeeController) AfterMethod(at struct{ at.AfterMethod }, ctx contex
 	return
 }
 
+type managerController struct {
+	at.RestController
+	at.RequestMapping `value:"${employee.base_path:}/manager"`
+}
+
+func newManagerController() *managerController {
+	return &managerController{}
+}
+
+func (c *managerController) GetManager(_ struct {
+	at.GetMapping `value:"/{id}"`
+	at.Operation  `id:"Get Manager" description:"This is the api that gets the manager"`
+	at.Produces   `values:"application/json"`
+}, id int) *Manager {
+	return &Manager{ID: id}
+}
+
 func TestController(t *testing.T) {
 	app.Register(
 		newEmployeeController,
+		newManagerController,
 		swagger.ApiInfoBuilder().
 			Title("HiBoot Swagger Demo Application - Simple CRUD Demo Application - 演示代码").
 			Description("Simple Server is an application that demonstrate the usage of Swagger Annotations"),
@@ -450,9 +519,9 @@ limitations under the License.
 			}).Expect().Status(http.StatusOK)
 	})
 
-	t.Run("should report 500 error if create employee without request body", func(t *testing.T) {
+	t.Run("should report 400 error if create employee without request body", func(t *testing.T) {
 		testApp.Post("/employee").
-			Expect().Status(http.StatusInternalServerError)
+			Expect().Status(http.StatusBadRequest)
 	})
 
 	t.Run("should get employees", func(t *testing.T) {
@@ -470,4 +539,37 @@ limitations under the License.
 			Expect().Status(http.StatusOK)
 	})
 
+	t.Run("should get formData parameters of file upload", func(t *testing.T) {
+		doc := map[string]interface{}{}
+		body := testApp.Get("/swagger.json").Expect().Status(http.StatusOK).Body().Raw()
+		assert.Equal(t, nil, json.Unmarshal([]byte(body), &doc))
+		operation := httpexpect.NewObject(t, doc).
+			Value("paths").Object().Value("/employee/avatar").Object().Value("post").Object()
+		operation.Value("consumes").Array().Elements("multipart/form-data")
+		params := operation.Value("parameters").Array()
+		params.Length().Equal(5)
+		params.Element(0).Object().ValueEqual("name", "name").ValueEqual("in", "formData").ValueEqual("type", "string").ValueEqual("required", true)
+		params.Element(1).Object().ValueEqual("name", "age").ValueEqual("type", "integer")
+		params.Element(2).Object().ValueEqual("name", "tags").ValueEqual("type", "array").ValueEqual("collectionFormat", "multi")
+		params.Element(3).Object().ValueEqual("name", "avatar").ValueEqual("type", "file").ValueEqual("description", "avatar image")
+		params.Element(4).Object().ValueEqual("name", "photos").ValueEqual("type", "file")
+	})
+
+	t.Run("should strip the constraints of path variables", func(t *testing.T) {
+		doc := map[string]interface{}{}
+		body := testApp.Get("/swagger.json").Expect().Status(http.StatusOK).Body().Raw()
+		assert.Equal(t, nil, json.Unmarshal([]byte(body), &doc))
+		httpexpect.NewObject(t, doc).Value("paths").Object().Value("/employee/{id}/assets").Object().ContainsKey("get")
+	})
+
+	t.Run("should resolve the property references of the request mapping", func(t *testing.T) {
+		doc := map[string]interface{}{}
+		body := testApp.Get("/swagger.json").Expect().Status(http.StatusOK).Body().Raw()
+		assert.Equal(t, nil, json.Unmarshal([]byte(body), &doc))
+		paths := httpexpect.NewObject(t, doc).Value("paths").Object()
+		paths.Value("/manager/{id}").Object().ContainsKey("get")
+		paths.NotContainsKey("${employee.base_path:}/manager/{id}")
+		testApp.Get("/manager/1").Expect().Status(http.StatusOK)
+	})
+
 }
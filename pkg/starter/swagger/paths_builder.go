@@ -3,13 +3,16 @@ package swagger
 import (
 	"fmt"
 	"github.com/go-openapi/spec"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
 	"github.com/hidevopsio/hiboot/pkg/app/web/webutils"
 	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/factory"
 	"github.com/hidevopsio/hiboot/pkg/inject/annotation"
 	"github.com/hidevopsio/hiboot/pkg/log"
 	"github.com/hidevopsio/hiboot/pkg/utils/reflector"
 	"github.com/hidevopsio/hiboot/pkg/utils/str"
 	"github.com/hidevopsio/hiboot/pkg/utils/structtag"
+	"mime/multipart"
 	"path"
 	"reflect"
 	"strings"
@@ -19,11 +22,12 @@ import (
 const refPrefix = "#/definitions/"
 
 type apiPathsBuilder struct {
-	apiInfoBuilder *apiInfoBuilder
-	primitiveTypes map[string]string
+	apiInfoBuilder      *apiInfoBuilder
+	configurableFactory factory.ConfigurableFactory
+	primitiveTypes      map[string]string
 }
 
-func newApiPathsBuilder(builder *apiInfoBuilder) *apiPathsBuilder {
+func newApiPathsBuilder(builder *apiInfoBuilder, configurableFactory factory.ConfigurableFactory) *apiPathsBuilder {
 	if builder.SystemServer != nil {
 		if builder.SystemServer.Host != "" {
 			if builder.SystemServer.Port != "" {
@@ -47,7 +51,8 @@ func newApiPathsBuilder(builder *apiInfoBuilder) *apiPathsBuilder {
 	log.Infof("visit %v to open api doc", visit)
 
 	return &apiPathsBuilder{
-		apiInfoBuilder: builder,
+		apiInfoBuilder:      builder,
+		configurableFactory: configurableFactory,
 		primitiveTypes: map[string]string{
 			// array, boolean, integer, number, object, string
 			"string": "string",
@@ -280,6 +285,18 @@ func (b *apiPathsBuilder) buildParameter(operation *spec.Operation, annotations
 	parameter.In = atParameter.AtIn
 	parameter.Description = atParameter.AtDescription
 
+	// expand the form struct to formData parameters, the uploaded files are file parameters
+	if atParameter.AtIn == "formData" {
+		params := b.buildFormDataParameters(a.Parent.Type)
+		if len(params) > 0 {
+			operation.Parameters = append(operation.Parameters, params...)
+			if len(operation.Consumes) == 0 {
+				operation.Consumes = []string{"multipart/form-data"}
+			}
+			return
+		}
+	}
+
 	if atParameter.AtIn == "body" || atParameter.AtIn == "array" {
 
 		atSchema := annotation.Find(annotations, at.Schema{})
@@ -296,6 +313,52 @@ func (b *apiPathsBuilder) buildParameter(operation *spec.Operation, annotations
 	return
 }
 
+var (
+	fileHeaderType   = reflect.TypeOf(multipart.FileHeader{})
+	uploadedFileType = reflect.TypeOf(web.UploadedFile{})
+)
+
+func isFileType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	typ = reflector.IndirectType(typ)
+	return typ == fileHeaderType || typ == uploadedFileType
+}
+
+// buildFormDataParameters builds formData parameters from the fields of the form struct
+func (b *apiPathsBuilder) buildFormDataParameters(typ reflect.Type) (parameters []spec.Parameter) {
+	for _, f := range deepFields(typ) {
+		name := strings.Split(f.Tag.Get("form"), ",")[0]
+		if name == "" {
+			name = f.Name
+		}
+		if name == "-" {
+			continue
+		}
+		parameter := spec.Parameter{}
+		parameter.Name = name
+		parameter.In = "formData"
+		parameter.Description = f.Tag.Get("description")
+		parameter.Required = strings.Contains(f.Tag.Get("validate"), "required")
+
+		if isFileType(f.Type) {
+			parameter.Type = "file"
+		} else if f.Type.Kind() == reflect.Slice {
+			parameter.Type = "array"
+			parameter.CollectionFormat = "multi"
+			parameter.Items = spec.NewItems().Typed(b.primitiveTypes[f.Type.Elem().Kind().String()], "")
+		} else {
+			parameter.Type = b.primitiveTypes[f.Type.Kind().String()]
+		}
+		if parameter.Type == "" {
+			continue
+		}
+		parameters = append(parameters, parameter)
+	}
+	return
+}
+
 func (b *apiPathsBuilder) findArrayField(schema *annotation.Annotation) (field *reflect.StructField) {
 	parentType := schema.Parent.Type
 	numField := parentType.NumField()
@@ -383,8 +446,15 @@ func (b *apiPathsBuilder) Build(atController *annotation.Annotations, atMethod *
 		atRequestMapping := annotation.GetAnnotation(atController, at.RequestMapping{})
 		if atRequestMapping != nil {
 			ann := atRequestMapping.Field.Value.Interface().(at.RequestMapping)
-			pth = path.Join(ann.AtValue, pth)
+			// the value may reference properties as the dispatcher does, e.g. `value:"${actuator.base_path:}/health"`
+			mappingPath := ann.AtValue
+			if p, ok := b.configurableFactory.Replace(mappingPath).(string); ok {
+				mappingPath = p
+			}
+			pth = path.Join(mappingPath, pth)
 		}
+		// the constraints of path variables are not part of the swagger path, e.g. {id:int}
+		pth = web.StripPathConstraints(pth)
 		//log.Debugf("%v:%v", method, path)
 
 		pathItem := b.apiInfoBuilder.Paths.Paths[pth]
@@ -0,0 +1,72 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing provides the hiboot starter for distributed tracing,
+// it starts a server span for every request and propagates the W3C traceparent and tracestate headers
+package tracing
+
+import (
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+const (
+	// Profile is the profile of tracing, it should be as same as the package name
+	Profile = "tracing"
+)
+
+type configuration struct {
+	at.AutoConfiguration
+
+	Properties *Properties
+}
+
+func init() {
+	app.Register(newConfiguration)
+}
+
+func newConfiguration() *configuration {
+	return &configuration{}
+}
+
+// Tracer is the injectable tracer, it is closed when the application is shut down
+func (c *configuration) Tracer() *Tracer {
+	var exporters []Exporter
+	for _, name := range c.Properties.Exporters {
+		exporter, err := newExporter(name, c.Properties)
+		if err != nil {
+			log.Warn(err)
+			continue
+		}
+		exporters = append(exporters, exporter)
+	}
+	return NewTracer(c.Properties.ServiceName, c.Properties.BatchSize,
+		time.Duration(c.Properties.FlushInterval)*time.Second, exporters...)
+}
+
+// Middleware starts the server span of each request
+func (c *configuration) Middleware(applicationContext app.ApplicationContext, tracer *Tracer) (mw *Middleware) {
+	mw = newMiddleware(tracer)
+	applicationContext.Use(mw.Serve)
+	return
+}
+
+// Span is the server span of current request
+func (c *configuration) Span(ctx context.Context, tracer *Tracer) *Span {
+	return spanFromContext(ctx, tracer)
+}
@@ -0,0 +1,124 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hidevopsio/hiboot/pkg/app"
+	"github.com/hidevopsio/hiboot/pkg/app/web"
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/starter/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+type userService struct {
+	at.Scope `value:"request"`
+
+	span *tracing.Span
+}
+
+func newUserService(span *tracing.Span) *userService {
+	return &userService{span: span}
+}
+
+func (s *userService) Find(id int) string {
+	span := s.span.Child("userService.Find")
+	defer span.End()
+	span.SetAttribute("user.id", id)
+	return "user"
+}
+
+type userController struct {
+	at.RestController
+	at.RequestMapping `value:"/user"`
+}
+
+func newUserController() *userController {
+	return &userController{}
+}
+
+func (c *userController) Get(_ struct {
+	at.GetMapping `value:"/{id}"`
+}, id int, service *userService) string {
+	return service.Find(id)
+}
+
+type collector struct {
+	sync.Mutex
+	spans []map[string]interface{}
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []map[string]interface{} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.Lock()
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			c.spans = append(c.spans, ss.Spans...)
+		}
+	}
+	c.Unlock()
+}
+
+func TestTracing(t *testing.T) {
+	c := new(collector)
+	ts := httptest.NewServer(c)
+	defer ts.Close()
+
+	app.Register(newUserService)
+	testApp := web.NewTestApp(newUserController).
+		SetProperty(app.ProfilesInclude, web.Profile, tracing.Profile).
+		SetProperty("tracing.exporters", []string{"otlp"}).
+		SetProperty("tracing.otlp.endpoint", ts.URL).
+		Run(t)
+	tracer := testApp.(app.ApplicationContext).GetInstance(tracing.Tracer{}).(*tracing.Tracer)
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	testApp.Get("/user/123").
+		WithHeader(tracing.TraceparentHeader, "00-"+traceID+"-00f067aa0ba902b7-01").
+		WithHeader(tracing.TracestateHeader, "vendor=value").
+		Expect().Status(http.StatusOK).
+		Header(tracing.TraceparentHeader).Match("^00-" + traceID + "-[0-9a-f]{16}-01$")
+	tracer.Flush()
+
+	c.Lock()
+	defer c.Unlock()
+	spans := make(map[string]map[string]interface{})
+	for _, s := range c.spans {
+		spans[s["name"].(string)] = s
+	}
+	server, child := spans["GET /user/{id}"], spans["userService.Find"]
+	if assert.NotNil(t, server) && assert.NotNil(t, child) {
+		assert.Equal(t, traceID, server["traceId"])
+		assert.Equal(t, "00f067aa0ba902b7", server["parentSpanId"])
+		assert.Equal(t, "vendor=value", server["traceState"])
+		assert.Equal(t, traceID, child["traceId"])
+		assert.Equal(t, server["spanId"], child["parentSpanId"])
+	}
+}
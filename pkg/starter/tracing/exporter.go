@@ -0,0 +1,213 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+// Exporter exports the ended spans to the tracing backend
+type Exporter interface {
+	Export(serviceName string, spans []*Span) error
+}
+
+// ExporterFactory creates the exporter by the tracing properties
+type ExporterFactory func(properties *Properties) Exporter
+
+var (
+	exportersMu sync.RWMutex
+	exporters   = map[string]ExporterFactory{
+		"log": func(p *Properties) Exporter { return NewLogExporter() },
+		"otlp": func(p *Properties) Exporter {
+			return NewOTLPExporter(p.OTLP.Endpoint, time.Duration(p.OTLP.Timeout)*time.Second)
+		},
+	}
+)
+
+// RegisterExporter registers the exporter factory by name, the exporter is enabled by adding its name to tracing.exporters
+func RegisterExporter(name string, factory ExporterFactory) {
+	exportersMu.Lock()
+	exporters[name] = factory
+	exportersMu.Unlock()
+}
+
+func newExporter(name string, properties *Properties) (exporter Exporter, err error) {
+	exportersMu.RLock()
+	factory, ok := exporters[name]
+	exportersMu.RUnlock()
+	if !ok {
+		err = fmt.Errorf("[tracing] exporter %v is not registered", name)
+		return
+	}
+	exporter = factory(properties)
+	return
+}
+
+type logExporter struct {
+}
+
+// NewLogExporter creates the exporter that writes the spans to the log
+func NewLogExporter() Exporter {
+	return &logExporter{}
+}
+
+// Export writes the spans to the log
+func (e *logExporter) Export(serviceName string, spans []*Span) error {
+	for _, s := range spans {
+		log.Infof("[tracing] service=%v trace_id=%v span_id=%v parent_span_id=%v name=%q duration=%v status=%v",
+			serviceName, s.Context.TraceID, s.Context.SpanID, s.ParentSpanID, s.Name, s.EndTime.Sub(s.StartTime), s.Status)
+	}
+	return nil
+}
+
+type otlpExporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewOTLPExporter creates the exporter that posts the spans to the OTLP/HTTP endpoint in JSON encoding,
+// e.g. http://localhost:4318/v1/traces
+func NewOTLPExporter(endpoint string, timeout time.Duration) Exporter {
+	return &otlpExporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Export posts the spans to the OTLP/HTTP endpoint
+func (e *otlpExporter) Export(serviceName string, spans []*Span) (err error) {
+	body, err := json.Marshal(newOTLPRequest(serviceName, spans))
+	if err != nil {
+		return
+	}
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err = fmt.Errorf("unexpected status %v from %v", resp.Status, e.endpoint)
+	}
+	return
+}
+
+// OTLP JSON encoding, see https://github.com/open-telemetry/opentelemetry-proto/blob/main/docs/specification.md#json-protobuf-encoding
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	TraceState        string          `json:"traceState,omitempty"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              SpanKind        `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    StatusCode `json:"code"`
+	Message string     `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+func newOTLPRequest(serviceName string, spans []*Span) *otlpRequest {
+	var otlpSpans []otlpSpan
+	for _, s := range spans {
+		otlpSpans = append(otlpSpans, otlpSpan{
+			TraceID:           s.Context.TraceID,
+			SpanID:            s.Context.SpanID,
+			TraceState:        s.Context.TraceState,
+			ParentSpanID:      s.ParentSpanID,
+			Name:              s.Name,
+			Kind:              s.Kind,
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+			Attributes:        newOTLPAttributes(s.Attributes),
+			Status:            otlpStatus{Code: s.Status, Message: s.StatusMessage},
+		})
+	}
+	return &otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: newOTLPAttributes(map[string]interface{}{"service.name": serviceName}),
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "github.com/hidevopsio/hiboot/pkg/starter/tracing"},
+				Spans: otlpSpans,
+			}},
+		}},
+	}
+}
+
+func newOTLPAttributes(attributes map[string]interface{}) (retVal []otlpAttribute) {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		var value map[string]interface{}
+		switch v := attributes[k].(type) {
+		case bool:
+			value = map[string]interface{}{"boolValue": v}
+		case int:
+			value = map[string]interface{}{"intValue": strconv.Itoa(v)}
+		case int64:
+			value = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+		case float64:
+			value = map[string]interface{}{"doubleValue": v}
+		default:
+			value = map[string]interface{}{"stringValue": fmt.Sprintf("%v", v)}
+		}
+		retVal = append(retVal, otlpAttribute{Key: k, Value: value})
+	}
+	return
+}
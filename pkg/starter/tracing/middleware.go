@@ -0,0 +1,71 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"fmt"
+
+	"github.com/hidevopsio/hiboot/pkg/app/web/context"
+)
+
+const spanKey = "tracing.span"
+
+// Middleware is the tracing middleware
+type Middleware struct {
+	tracer *Tracer
+}
+
+func newMiddleware(tracer *Tracer) *Middleware {
+	return &Middleware{tracer: tracer}
+}
+
+// Serve continues the trace of the incoming traceparent header, or starts a new one,
+// and writes the traceparent of the server span to the response
+func (m *Middleware) Serve(ctx context.Context) {
+	route := ctx.Path()
+	if r := ctx.GetCurrentRoute(); r != nil {
+		route = r.Path()
+	}
+	parent, _ := Extract(ctx.Request().Header)
+	span := m.tracer.Start(ctx.Method()+" "+route, SpanKindServer, parent)
+	span.SetAttribute("http.method", ctx.Method()).
+		SetAttribute("http.route", route).
+		SetAttribute("http.target", ctx.Request().URL.RequestURI())
+	ctx.Values().Set(spanKey, span)
+	span.Context.Inject(ctx.ResponseWriter().Header())
+
+	defer func() {
+		code := ctx.GetStatusCode()
+		span.SetAttribute("http.status_code", code)
+		if code >= 500 {
+			span.SetStatus(StatusError, fmt.Sprintf("HTTP %d", code))
+		}
+		span.End()
+	}()
+
+	ctx.Next()
+}
+
+// spanFromContext returns the server span of current request
+func spanFromContext(ctx context.Context, tracer *Tracer) (span *Span) {
+	span, ok := ctx.Values().Get(spanKey).(*Span)
+	if !ok {
+		// the middleware is not served yet, start the span for current request here
+		parent, _ := Extract(ctx.Request().Header)
+		span = tracer.Start(ctx.Method()+" "+ctx.Path(), SpanKindServer, parent)
+		ctx.Values().Set(spanKey, span)
+	}
+	return
+}
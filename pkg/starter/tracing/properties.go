@@ -0,0 +1,42 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import "github.com/hidevopsio/hiboot/pkg/at"
+
+// Properties the tracing properties
+type Properties struct {
+	at.ConfigurationProperties `value:"tracing"`
+	at.AutoWired
+
+	// ServiceName is the service.name resource attribute of the exported spans
+	ServiceName string `json:"service_name" default:"${app.name}"`
+	// Exporters is the names of the enabled exporters, e.g. log, otlp or the ones registered by RegisterExporter
+	Exporters []string `json:"exporters" default:"log"`
+	// BatchSize is the number of ended spans that triggers an export
+	BatchSize int `json:"batch_size" default:"512"`
+	// FlushInterval is the interval in seconds of exporting the ended spans
+	FlushInterval int64 `json:"flush_interval" default:"5"`
+
+	OTLP OTLPProperties `json:"otlp"`
+}
+
+// OTLPProperties the OTLP/HTTP exporter properties
+type OTLPProperties struct {
+	// Endpoint is the OTLP/HTTP traces endpoint of the collector
+	Endpoint string `json:"endpoint" default:"http://localhost:4318/v1/traces"`
+	// Timeout is the timeout in seconds of each export
+	Timeout int64 `json:"timeout" default:"10"`
+}
@@ -0,0 +1,133 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// TraceparentHeader is the W3C trace context header that carries the trace id and the parent span id
+	TraceparentHeader = "traceparent"
+	// TracestateHeader is the W3C trace context header that carries the vendor specific trace data
+	TracestateHeader = "tracestate"
+
+	traceparentVersion = "00"
+	flagSampled        = 0x01
+)
+
+// SpanContext is the part of the span that is propagated across services
+type SpanContext struct {
+	// TraceID is the 32 hex digits trace id
+	TraceID string
+	// SpanID is the 16 hex digits span id
+	SpanID string
+	// Flags is the trace flags, e.g. 01 for sampled
+	Flags byte
+	// TraceState is the vendor specific trace data
+	TraceState string
+}
+
+// IsValid returns true if both trace id and span id are valid
+func (sc SpanContext) IsValid() bool {
+	return isValidID(sc.TraceID, 32) && isValidID(sc.SpanID, 16)
+}
+
+// Traceparent returns the traceparent header value, e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("%s-%s-%s-%02x", traceparentVersion, sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+// Inject writes the traceparent and tracestate headers, it is used for propagating the trace to downstream services
+func (sc SpanContext) Inject(header http.Header) {
+	if !sc.IsValid() {
+		return
+	}
+	header.Set(TraceparentHeader, sc.Traceparent())
+	if sc.TraceState != "" {
+		header.Set(TracestateHeader, sc.TraceState)
+	}
+}
+
+// Extract reads the span context from traceparent and tracestate headers, ok is false if traceparent is absent or invalid
+func Extract(header http.Header) (sc SpanContext, ok bool) {
+	sc, ok = ParseTraceparent(header.Get(TraceparentHeader))
+	if ok {
+		sc.TraceState = header.Get(TracestateHeader)
+	}
+	return
+}
+
+// ParseTraceparent parses the traceparent header value
+func ParseTraceparent(traceparent string) (sc SpanContext, ok bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 {
+		return
+	}
+	version := parts[0]
+	// version ff is invalid, version 00 must have exactly 4 parts
+	if len(version) != 2 || version == "ff" || (version == traceparentVersion && len(parts) != 4) {
+		return
+	}
+	if _, err := hex.DecodeString(version); err != nil {
+		return
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return
+	}
+	sc = SpanContext{
+		TraceID: parts[1],
+		SpanID:  parts[2],
+		Flags:   flags[0],
+	}
+	ok = sc.IsValid()
+	return
+}
+
+func isValidID(id string, length int) bool {
+	if len(id) != length || strings.ToLower(id) != id {
+		return false
+	}
+	b, err := hex.DecodeString(id)
+	if err != nil {
+		return false
+	}
+	for _, c := range b {
+		if c != 0 {
+			return true
+		}
+	}
+	// all zeros is invalid
+	return false
+}
+
+func newID(size int) string {
+	b := make([]byte, size)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func newTraceID() string {
+	return newID(16)
+}
+
+func newSpanID() string {
+	return newID(8)
+}
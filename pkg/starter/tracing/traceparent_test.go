@@ -0,0 +1,66 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	testCases := []struct {
+		name        string
+		traceparent string
+		ok          bool
+	}{
+		{"valid", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"future version with extra fields", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true},
+		{"empty", "", false},
+		{"invalid version", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"extra fields of version 00", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false},
+		{"all zero trace id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"all zero span id", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"upper case", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"short trace id", "00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01", false},
+		{"invalid flags", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-x1", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sc, ok := ParseTraceparent(tc.traceparent)
+			assert.Equal(t, tc.ok, ok)
+			if ok {
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID)
+				assert.Equal(t, "00f067aa0ba902b7", sc.SpanID)
+				assert.Equal(t, byte(flagSampled), sc.Flags)
+			}
+		})
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	header := http.Header{}
+	SpanContext{}.Inject(header)
+	assert.Equal(t, "", header.Get(TraceparentHeader))
+
+	sc := SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Flags: flagSampled, TraceState: "vendor=value"}
+	sc.Inject(header)
+	assert.Equal(t, "00-"+sc.TraceID+"-"+sc.SpanID+"-01", header.Get(TraceparentHeader))
+
+	extracted, ok := Extract(header)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, sc, extracted)
+}
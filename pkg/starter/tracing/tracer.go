@@ -0,0 +1,210 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/at"
+	"github.com/hidevopsio/hiboot/pkg/log"
+)
+
+// SpanKind is the role of the span in the trace
+type SpanKind int
+
+// the values are the same as OTLP
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// StatusCode is the status of the span
+type StatusCode int
+
+// the values are the same as OTLP
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// Span is a single operation within a trace, the server span of current request is request scoped,
+// it can be injected into at.Scope "request" components to create child spans
+type Span struct {
+	at.Scope `value:"request" json:"-"`
+
+	mu            sync.Mutex
+	tracer        *Tracer
+	ended         bool
+	Name          string
+	Kind          SpanKind
+	Context       SpanContext
+	ParentSpanID  string
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    map[string]interface{}
+	Status        StatusCode
+	StatusMessage string
+}
+
+// SetAttribute sets the attribute of the span, the value should be string, bool, int, int64 or float64
+func (s *Span) SetAttribute(key string, value interface{}) *Span {
+	s.mu.Lock()
+	s.Attributes[key] = value
+	s.mu.Unlock()
+	return s
+}
+
+// SetStatus sets the status of the span
+func (s *Span) SetStatus(code StatusCode, message string) *Span {
+	s.mu.Lock()
+	s.Status, s.StatusMessage = code, message
+	s.mu.Unlock()
+	return s
+}
+
+// RecordError marks the span as failed
+func (s *Span) RecordError(err error) *Span {
+	if err != nil {
+		s.SetStatus(StatusError, err.Error())
+	}
+	return s
+}
+
+// Child starts a child span of this span
+func (s *Span) Child(name string) *Span {
+	return s.tracer.Start(name, SpanKindInternal, s.Context)
+}
+
+// End ends the span and hands it over to the exporters, it is safe to call it more than once
+func (s *Span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	s.mu.Unlock()
+	s.tracer.end(s)
+}
+
+// Tracer creates spans and exports them in batches
+type Tracer struct {
+	mu          sync.Mutex
+	serviceName string
+	exporters   []Exporter
+	batchSize   int
+	pending     []*Span
+	stop        chan struct{}
+	stopOnce    sync.Once
+}
+
+// NewTracer creates a new tracer, the ended spans are exported every flushInterval or once there are batchSize spans
+func NewTracer(serviceName string, batchSize int, flushInterval time.Duration, exporters ...Exporter) *Tracer {
+	t := &Tracer{
+		serviceName: serviceName,
+		exporters:   exporters,
+		batchSize:   batchSize,
+		stop:        make(chan struct{}),
+	}
+	if flushInterval > 0 {
+		go t.run(flushInterval)
+	}
+	return t
+}
+
+// ServiceName returns the service name of the tracer
+func (t *Tracer) ServiceName() string {
+	return t.serviceName
+}
+
+// AddExporter adds the exporter to the tracer
+func (t *Tracer) AddExporter(exporter Exporter) {
+	t.mu.Lock()
+	t.exporters = append(t.exporters, exporter)
+	t.mu.Unlock()
+}
+
+// Start starts a new span, the span continues the trace of parent if parent is valid, otherwise a new trace is started
+func (t *Tracer) Start(name string, kind SpanKind, parent SpanContext) *Span {
+	s := &Span{
+		tracer:     t,
+		Name:       name,
+		Kind:       kind,
+		StartTime:  time.Now(),
+		Attributes: make(map[string]interface{}),
+	}
+	if parent.IsValid() {
+		s.Context = SpanContext{TraceID: parent.TraceID, Flags: parent.Flags, TraceState: parent.TraceState}
+		s.ParentSpanID = parent.SpanID
+	} else {
+		s.Context = SpanContext{TraceID: newTraceID(), Flags: flagSampled}
+	}
+	s.Context.SpanID = newSpanID()
+	return s
+}
+
+func (t *Tracer) end(s *Span) {
+	t.mu.Lock()
+	t.pending = append(t.pending, s)
+	full := len(t.pending) >= t.batchSize
+	t.mu.Unlock()
+	if full {
+		go t.Flush()
+	}
+}
+
+func (t *Tracer) run(flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			t.Flush()
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+// Flush exports the ended spans immediately
+func (t *Tracer) Flush() {
+	t.mu.Lock()
+	spans := t.pending
+	t.pending = nil
+	exporters := t.exporters
+	t.mu.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+	for _, exporter := range exporters {
+		if err := exporter.Export(t.serviceName, spans); err != nil {
+			log.Errorf("[tracing] failed to export %d spans: %v", len(spans), err)
+		}
+	}
+}
+
+// Close flushes the ended spans and stops the tracer, it is called when the application is shut down
+func (t *Tracer) Close() error {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+	t.Flush()
+	return nil
+}
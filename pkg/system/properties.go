@@ -67,6 +67,26 @@ type App struct {
 	TermsOfService string       `json:"termsOfService,omitempty"`
 	Contact        *ContactInfo `json:"contact,omitempty"`
 	License        *License     `json:"license,omitempty"`
+	// Refresh is the properties of the configuration hot reload
+	Refresh Refresh `json:"refresh"`
+	// Event is the properties of the application event bus
+	Event Event `json:"event"`
+}
+
+// Event is the properties of the application event bus
+type Event struct {
+	// Workers is the number of the workers that deliver the events to the asynchronous listeners
+	Workers int `json:"workers" default:"4"`
+	// QueueSize is the number of the events that are waiting for the asynchronous listeners
+	QueueSize int `json:"queue_size" default:"256"`
+}
+
+// Refresh is the properties of the configuration hot reload
+type Refresh struct {
+	// Watch toggles watching the config files, the configuration properties are refreshed once the files are changed
+	Watch bool `json:"watch"`
+	// Interval is the seconds between checking the changes of the config files
+	Interval int64 `json:"interval" default:"5"`
 }
 
 // Server is the properties of http server
@@ -81,6 +101,26 @@ type Server struct {
 	ContextPath string   `json:"context_path,omitempty" default:"/"`
 	TlsCert     string   `json:"tls_cert,omitempty" `
 	TlsKey      string   `json:"tls_key,omitempty" `
+	// ShutdownTimeout is the seconds to wait for in-flight requests on graceful shutdown
+	ShutdownTimeout int64 `json:"shutdown_timeout,omitempty" default:"30"`
+	// ReadTimeout is the seconds to read the entire request, including the body, 0 means no timeout
+	ReadTimeout int64 `json:"read_timeout,omitempty" default:"60"`
+	// ReadHeaderTimeout is the seconds to read the request headers, 0 means ReadTimeout is used
+	ReadHeaderTimeout int64 `json:"read_header_timeout,omitempty" default:"10"`
+	// WriteTimeout is the seconds to write the response, 0 means no timeout
+	WriteTimeout int64 `json:"write_timeout,omitempty" default:"60"`
+	// RequestTimeout is the default seconds that a controller method, including its streamed response, may take, 0 means no timeout
+	RequestTimeout int64 `json:"request_timeout,omitempty" default:"0"`
+	// IdleTimeout is the seconds to wait for the next request on a keep-alive connection
+	IdleTimeout int64 `json:"idle_timeout,omitempty" default:"120"`
+	// MaxHeaderBytes is the max bytes of the request headers
+	MaxHeaderBytes int `json:"max_header_bytes,omitempty" default:"1048576"`
+	// KeepAlive enables HTTP keep-alive
+	KeepAlive bool `json:"keep_alive" default:"true"`
+	// Http2 enables HTTP/2 over TLS
+	Http2 bool `json:"http2" default:"true"`
+	// H2c enables HTTP/2 over cleartext TCP, for servers behind a proxy that terminates TLS
+	H2c bool `json:"h2c" default:"false"`
 }
 
 // Logging is the properties of logging
@@ -92,4 +132,26 @@ type Logging struct {
 	Level      string `json:"level,omitempty" default:"info"`
 	TimeFormat string `json:"timeFormat" default:"[2006-01-02 15:04:05.000]"`
 	FileLine   bool   `json:"fileline" default:"false"`
+	// Format is the log format, text or json
+	Format string `json:"format" default:"text"`
+	// Levels is the levels of the named loggers, e.g. logging.levels.user: debug
+	Levels map[string]string `json:"levels"`
+	// File is the log file appender
+	File LogFile `json:"file"`
+}
+
+// LogFile is the properties of the log file appender
+type LogFile struct {
+	// Path is the path of the log file, the logs are written to the file if it is not empty
+	Path string `json:"path"`
+	// MaxSize is the max size in megabytes before the log file is rotated, 0 means never rotate
+	MaxSize int `json:"max_size" default:"100"`
+	// MaxAge is the max days to keep the rotated log files, 0 means no limit
+	MaxAge int `json:"max_age"`
+	// MaxBackups is the max number of the rotated log files to keep, 0 means no limit
+	MaxBackups int `json:"max_backups"`
+	// Compress toggles the gzip compression of the rotated log files
+	Compress bool `json:"compress"`
+	// Stdout toggles writing to both stdout and the log file
+	Stdout bool `json:"stdout" default:"true"`
 }
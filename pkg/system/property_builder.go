@@ -51,16 +51,27 @@ type ConfigFile struct {
 	profile  string
 }
 
+// keyValue is the property that is set at runtime
+type keyValue struct {
+	key   string
+	value interface{}
+}
+
 type propertyBuilder struct {
 	at.Qualifier `value:"github.com/hidevopsio/hiboot/pkg/system.builder"`
 	*viper.Viper
 	ConfigFile
 	configuration     interface{}
 	defaultProperties map[string]interface{}
-	profiles          []string
-	merge             bool
-	embedFS           *embed.FS
-	sync.Mutex
+	// overrides and defaults are the properties that are set by SetProperty and SetDefaultProperty in order,
+	// they are kept on Reload
+	overrides []keyValue
+	defaults  []keyValue
+	profiles  []string
+	merge     bool
+	embedFS   *embed.FS
+	// RWMutex guards the viper instance that is swapped on Reload
+	sync.RWMutex
 }
 
 // NewBuilder is the constructor of system.Builder
@@ -203,6 +214,7 @@ func (b *propertyBuilder) parseConfigFile(fullPath string) (config *ConfigFile,
 // Build config file
 func (b *propertyBuilder) Build(profiles ...string) (conf interface{}, err error) {
 	// parse profiles
+	b.profiles = profiles
 
 	// set custom properties
 	for key, value := range b.defaultProperties {
@@ -446,7 +458,9 @@ func (b *propertyBuilder) Load(properties interface{}, opts ...func(*mapstructur
 	if ann != nil {
 		prefix := ann.Field.StructField.Tag.Get("value")
 
+		b.RLock()
 		allSettings := b.AllSettings()
+		b.RUnlock()
 		settings := allSettings[prefix]
 		if settings != nil {
 			err = mapstruct.Decode(properties, settings, opts...)
@@ -457,6 +471,8 @@ func (b *propertyBuilder) Load(properties interface{}, opts ...func(*mapstructur
 
 // Replace reference and
 func (b *propertyBuilder) Replace(source string) (retVal interface{}) {
+	b.RLock()
+	defer b.RUnlock()
 	result := source
 	matches := replacer.GetMatches(source)
 	if len(matches) != 0 {
@@ -465,7 +481,9 @@ func (b *propertyBuilder) Replace(source string) (retVal interface{}) {
 			// check if it contains default value
 			var defaultValue string
 			n := strings.Index(varName, ":")
-			if n > 0 {
+			// empty default value is allowed, e.g. ${foo.bar:}
+			hasDefaultValue := n > 0
+			if hasDefaultValue {
 				defaultValue = varName[n+1:]
 				varName = varName[:n]
 				//log.Debugf("name: %v, default value: %v", varName, defaultValue)
@@ -490,7 +508,7 @@ func (b *propertyBuilder) Replace(source string) (retVal interface{}) {
 				result = strings.Replace(result, varFullName, envValue, -1)
 			}
 
-			if envValue == "" && newVal == "" && defaultValue != "" {
+			if envValue == "" && newVal == "" && hasDefaultValue {
 				result = strings.Replace(result, varFullName, defaultValue, -1)
 			}
 			log.Debugf("replaced %v to %v", varName, result)
@@ -501,18 +519,32 @@ func (b *propertyBuilder) Replace(source string) (retVal interface{}) {
 }
 
 func (b *propertyBuilder) GetProperty(name string) (retVal interface{}) {
+	b.RLock()
 	retVal = b.Get(name)
+	b.RUnlock()
+	return
+}
+
+// AllKeys returns all keys of the properties
+func (b *propertyBuilder) AllKeys() (keys []string) {
+	b.RLock()
+	keys = b.Viper.AllKeys()
+	b.RUnlock()
 	return
 }
 
 func (b *propertyBuilder) SetProperty(name string, val interface{}) Builder {
+	b.Lock()
 	b.Set(name, val)
+	b.overrides = append(b.overrides, keyValue{name, val})
+	b.Unlock()
 	return b
 }
 
 func (b *propertyBuilder) SetDefaultProperty(name string, val interface{}) Builder {
 	b.Lock()
 	b.SetDefault(name, val)
+	b.defaults = append(b.defaults, keyValue{name, val})
 	b.Unlock()
 
 	return b
@@ -158,6 +158,14 @@ func TestPropertyBuilderBuild(t *testing.T) {
 		assert.Equal(t, "this is default property", res)
 	})
 
+	t.Run("should replace with empty default property", func(t *testing.T) {
+		res := b.Replace("${default.base_path:}/health")
+		assert.Equal(t, "/health", res)
+
+		res = b.Replace("${app.name:}/health")
+		assert.Equal(t, "foo/health", res)
+	})
+
 	t.Run("should replace with environment variable", func(t *testing.T) {
 		res := b.Replace("this is ${HOME}")
 		home := os.Getenv("HOME")
@@ -0,0 +1,109 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/hidevopsio/hiboot/pkg/utils/str"
+	"github.com/hidevopsio/viper"
+)
+
+// Reloader is the Builder that is able to reload the config files at runtime
+type Reloader interface {
+	// Reload re-reads the config files and returns the sorted keys of the changed properties
+	Reload() (changedKeys []string, err error)
+	// ConfigFiles returns the modification time of the external config files, it is used for watching the changes
+	ConfigFiles() map[string]time.Time
+}
+
+// Reload re-reads the config files with the same profiles of last Build, the properties that are set by SetProperty
+// and the command line arguments are kept. The config files are read into a new viper instance which is swapped in
+// once it is built, so that the properties can be read while reloading
+func (b *propertyBuilder) Reload() (changedKeys []string, err error) {
+	b.RLock()
+	// the default properties are replayed from b.defaults in order rather than applied again by Build
+	nb := &propertyBuilder{
+		ConfigFile: ConfigFile{path: b.path},
+		Viper:      viper.New(),
+	}
+	for _, p := range b.defaults {
+		nb.SetDefaultProperty(p.key, p.value)
+	}
+	for _, p := range b.overrides {
+		nb.SetProperty(p.key, p.value)
+	}
+	profiles := b.profiles
+	b.RUnlock()
+
+	_, err = nb.Build(profiles...)
+	if err != nil {
+		return
+	}
+	after := nb.settings()
+
+	b.Lock()
+	before := b.settingsLocked()
+	b.Viper, b.embedFS, b.defaults, b.overrides = nb.Viper, nb.embedFS, nb.defaults, nb.overrides
+	b.Unlock()
+
+	for key, val := range after {
+		if old, ok := before[key]; !ok || !reflect.DeepEqual(old, val) {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+	sort.Strings(changedKeys)
+	return
+}
+
+// ConfigFiles returns the modification time of the external config files
+func (b *propertyBuilder) ConfigFiles() (files map[string]time.Time) {
+	files = make(map[string]time.Time)
+	pp, _ := filepath.Abs(b.path)
+	_ = filepath.Walk(pp, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			ext := filepath.Ext(path)
+			if ext != "" && str.InSlice(ext[1:], viper.SupportedExts) {
+				files[path] = info.ModTime()
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (b *propertyBuilder) settings() map[string]interface{} {
+	b.RLock()
+	defer b.RUnlock()
+	return b.settingsLocked()
+}
+
+// settingsLocked returns all settings, the caller must hold the lock
+func (b *propertyBuilder) settingsLocked() map[string]interface{} {
+	s := make(map[string]interface{})
+	for _, key := range b.Viper.AllKeys() {
+		s[key] = b.Get(key)
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "application.yml")
+	assert.Equal(t, nil, os.WriteFile(file, []byte("foo:\n  name: foo\n  removed: true\nbar:\n  name: bar\n"), 0644))
+
+	b := NewPropertyBuilder(dir, map[string]interface{}{"foo.custom": "custom"}).(*propertyBuilder)
+	b.SetProperty("foo.override", "override")
+	_, err := b.Build("default")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "foo", b.GetProperty("foo.name"))
+
+	files := b.ConfigFiles()
+	assert.Equal(t, 1, len(files))
+
+	// make sure the modification time is changed
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, nil, os.WriteFile(file, []byte("foo:\n  name: baz\n  added: 1\nbar:\n  name: bar\n"), 0644))
+	assert.NotEqual(t, files, b.ConfigFiles())
+
+	changedKeys, err := b.Reload()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"foo.added", "foo.name", "foo.removed"}, changedKeys)
+	assert.Equal(t, "baz", b.GetProperty("foo.name"))
+	assert.Equal(t, nil, b.GetProperty("foo.removed"))
+	assert.Equal(t, "custom", b.GetProperty("foo.custom"))
+	assert.Equal(t, "override", b.GetProperty("foo.override"))
+
+	changedKeys, err = b.Reload()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(changedKeys))
+}
+
+func TestReloadWhileReading(t *testing.T) {
+	dir := t.TempDir()
+	assert.Equal(t, nil, os.WriteFile(filepath.Join(dir, "application.yml"), []byte("foo:\n  name: foo\n"), 0644))
+	b := NewPropertyBuilder(dir, nil).(*propertyBuilder)
+	_, err := b.Build("default")
+	assert.Equal(t, nil, err)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				for _, key := range b.AllKeys() {
+					_ = b.GetProperty(key)
+				}
+			}
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		_, err = b.Reload()
+		assert.Equal(t, nil, err)
+	}
+	close(stop)
+	wg.Wait()
+	assert.Equal(t, "foo", b.GetProperty("foo.name"))
+}
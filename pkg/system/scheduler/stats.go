@@ -0,0 +1,54 @@
+// Copyright 2018 John Deng (hi.devops.io@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// JobStats is the run statistics of a scheduled job
+type JobStats struct {
+	// Runs is the number of times the job has run
+	Runs uint64
+	// Failures is the number of times the job has failed
+	Failures uint64
+}
+
+var stats sync.Map
+
+// RecordRun records a run of the job, failed indicates if the run is failed
+func RecordRun(job string, failed bool) {
+	v, _ := stats.LoadOrStore(job, new(JobStats))
+	s := v.(*JobStats)
+	atomic.AddUint64(&s.Runs, 1)
+	if failed {
+		atomic.AddUint64(&s.Failures, 1)
+	}
+}
+
+// Stats returns the snapshot of the run statistics of all scheduled jobs
+func Stats() map[string]JobStats {
+	snapshot := make(map[string]JobStats)
+	stats.Range(func(key, value interface{}) bool {
+		s := value.(*JobStats)
+		snapshot[key.(string)] = JobStats{
+			Runs:     atomic.LoadUint64(&s.Runs),
+			Failures: atomic.LoadUint64(&s.Failures),
+		}
+		return true
+	})
+	return snapshot
+}